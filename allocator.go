@@ -0,0 +1,102 @@
+package lexer
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// Allocator supplies the memory the lexer uses for each emitted token.
+//
+// High-throughput callers can supply an allocator to control how token values and trivia
+// are allocated, and return that memory once they are done with a token.
+type Allocator interface {
+	// Value returns the value of a token of the specified type and lexeme.
+	Value(tokenType TokenType, lexeme string) interface{}
+
+	// Trivia returns an empty slice in which the lexer collects a token's trivia.
+	Trivia() []Token
+
+	// Release returns the memory held by a token to the allocator.
+	Release(t Token)
+}
+
+// WithAllocator allocates token values and trivia using the specified allocator.
+func WithAllocator(allocator Allocator) Option {
+	return func(l *Lexer) {
+		l.allocator = allocator
+	}
+}
+
+// PoolAllocator is an allocator that reuses released trivia slices.
+type PoolAllocator struct {
+	trivia sync.Pool
+}
+
+// NewPoolAllocator creates an allocator backed by a sync.Pool.
+func NewPoolAllocator() *PoolAllocator {
+	return &PoolAllocator{}
+}
+
+// Value returns the lexeme.
+func (p *PoolAllocator) Value(tokenType TokenType, lexeme string) interface{} {
+	return lexeme
+}
+
+// Trivia returns a previously released trivia slice, if any.
+func (p *PoolAllocator) Trivia() []Token {
+	if s, ok := p.trivia.Get().(*[]Token); ok {
+		return (*s)[:0]
+	}
+	return nil
+}
+
+// Release returns the token's trivia slice to the pool.
+func (p *PoolAllocator) Release(t Token) {
+	if cap(t.Trivia) == 0 {
+		return
+	}
+	s := t.Trivia[:0]
+	p.trivia.Put(&s)
+}
+
+// Arena is an allocator that copies token values into large blocks of memory.
+//
+// Values allocated by an arena do not retain the input they were lexed from and are freed
+// together once no token refers to their block.
+type Arena struct {
+	mutex     sync.Mutex
+	block     []byte
+	blockSize int
+}
+
+// NewArena creates an arena that allocates blocks of (at least) the specified size.
+func NewArena(blockSize int) *Arena {
+	return &Arena{blockSize: blockSize}
+}
+
+// Value returns a copy of the lexeme allocated in the arena.
+func (a *Arena) Value(tokenType TokenType, lexeme string) interface{} {
+	if lexeme == "" {
+		return lexeme
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if cap(a.block)-len(a.block) < len(lexeme) {
+		size := a.blockSize
+		if size < len(lexeme) {
+			size = len(lexeme)
+		}
+		a.block = make([]byte, 0, size)
+	}
+	start := len(a.block)
+	a.block = append(a.block, lexeme...)
+	return unsafe.String(&a.block[start], len(lexeme))
+}
+
+// Trivia returns nil; the lexer allocates trivia slices as needed.
+func (a *Arena) Trivia() []Token {
+	return nil
+}
+
+// Release does nothing; memory allocated by an arena is reclaimed by the garbage collector.
+func (a *Arena) Release(t Token) {}
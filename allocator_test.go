@@ -0,0 +1,52 @@
+package lexer_test
+
+import (
+	"unicode"
+	"unsafe"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Allocators", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Token)
+			}
+		}
+	}
+
+	It("should copy token values into the arena (i.e. Arena)", func() {
+		input := "abc def"
+		l := lexer.NewLexer(input, state, lexer.WithAllocator(lexer.NewArena(64)))
+		abc, def := l.NextToken(), l.NextToken()
+		Expect(abc.Value).To(Equal("abc"))
+		Expect(def.Value).To(Equal("def"))
+		address := func(s interface{}) uintptr {
+			return uintptr(unsafe.Pointer(unsafe.StringData(s.(string))))
+		}
+		Expect(address(abc.Value)).NotTo(Equal(address(input)))
+		Expect(address(def.Value)).To(Equal(address(abc.Value) + 3))
+	})
+
+	It("should reuse released trivia slices (i.e. PoolAllocator)", func() {
+		pool := lexer.NewPoolAllocator()
+		l := lexer.NewLexer(" a b", state, lexer.WithAllocator(pool), lexer.WithTrivia(lexer.TriviaLeading))
+		a := l.NextToken()
+		Expect(a.Trivia).To(HaveLen(1))
+		pool.Release(a)
+		b := l.NextToken()
+		Expect(b.Value).To(Equal("b"))
+		Expect(b.Trivia).To(HaveLen(1))
+		Expect(b.Trivia[0]).To(EqualToken(lexer.Token{Type: lexer.TokenTrivia, Value: " "}))
+	})
+})
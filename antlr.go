@@ -0,0 +1,68 @@
+package lexer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANTLRHiddenChannel is the ANTLR channel trivia is exported on.
+const ANTLRHiddenChannel = 1
+
+var antlrEscaper = strings.NewReplacer("\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// ExportANTLR writes the tokens lexed from the input to w in the format ANTLR's TestRig
+// prints token streams in, e.g. [@0,0:2='abc',<IDENT>,1:0], followed by an EOF token.
+//
+// Start and stop indices count runes, as ANTLR does, and are derived from the input, as is
+// the text of each token, as RenderHTML takes it (e.g. the quoted literal of a token whose
+// value is unquoted). Trivia is exported as tokens on ANTLRHiddenChannel; error and warning
+// tokens are omitted.
+func ExportANTLR(w io.Writer, input string, tokens []Token) error {
+	flattened := flattenTrivia(tokens)
+	offset, index := 0, 0
+	runeIndex := func(position RunePosition) int {
+		if p := int(position); p > offset && p <= len(input) {
+			index += utf8.RuneCountInString(input[offset:p])
+			offset = p
+		}
+		return index
+	}
+	for i, t := range flattened {
+		text := ""
+		if p := int(t.Position); p <= len(input) {
+			text = input[p:sourceEnd(input, flattened[i+1:], t.Token)]
+		}
+		start := runeIndex(t.Position)
+		channel := ""
+		if t.trivia {
+			channel = fmt.Sprintf(",channel=%d", ANTLRHiddenChannel)
+		}
+		_, err := fmt.Fprintf(w, "[@%d,%d:%d='%s',<%v>%s,%d:%d]\n", i, start, start+utf8.RuneCountInString(text)-1, antlrEscaper.Replace(text), t.Type, channel, t.Line, t.Column-1)
+		if err != nil {
+			return err
+		}
+	}
+	end := runeIndex(RunePosition(len(input)))
+	line := strings.Count(input, "\n") + 1
+	column := utf8.RuneCountInString(input[strings.LastIndex(input, "\n")+1:])
+	_, err := fmt.Fprintf(w, "[@%d,%d:%d='<EOF>',<EOF>,%d:%d]\n", len(flattened), end, end-1, line, column)
+	return err
+}
+
+// ExportANTLRVocabulary writes the registered names of the token types to w in the format
+// of an ANTLR .tokens file, e.g. IDENT=1. Token types without a registered name are
+// skipped.
+func ExportANTLRVocabulary(w io.Writer, types []TokenType) error {
+	for _, t := range types {
+		name, ok := t.name()
+		if !ok {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s=%d\n", name, int(t)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
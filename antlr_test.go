@@ -0,0 +1,68 @@
+package lexer_test
+
+import (
+	"bytes"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ANTLR export", func() {
+	const Word lexer.TokenType = 102
+
+	BeforeEach(func() {
+		lexer.RegisterTokenType(Word, "WORD")
+	})
+
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.IgnoreUpTo(func(r rune) bool {
+					return !unicode.IsSpace(r)
+				})
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Word)
+			}
+		}
+	}
+
+	It("should write tokens in ANTLR's token stream format (i.e. ExportANTLR)", func() {
+		input := "héllo\n wörld"
+		l := lexer.NewLexer(input, state, lexer.WithTrivia(lexer.TriviaLeading))
+		tokens := []lexer.Token{l.NextToken(), l.NextToken()}
+		var b bytes.Buffer
+		Expect(lexer.ExportANTLR(&b, input, tokens)).To(Succeed())
+		Expect(b.String()).To(Equal("" +
+			"[@0,0:4='héllo',<WORD>,1:0]\n" +
+			"[@1,5:6='\\n ',<lexer.TRIVIA>,channel=1,1:5]\n" +
+			"[@2,7:11='wörld',<WORD>,2:1]\n" +
+			"[@3,12:11='<EOF>',<EOF>,2:6]\n"))
+	})
+
+	It("should take the text of tokens from the input (i.e. ExportANTLR)", func() {
+		input := `"a b" c`
+		tokens := []lexer.Token{
+			{Type: Word, Value: "a b", Position: 0, Line: 1, Column: 1},
+			{Type: Word, Value: "c", Position: 6, Line: 1, Column: 7},
+		}
+		var b bytes.Buffer
+		Expect(lexer.ExportANTLR(&b, input, tokens)).To(Succeed())
+		Expect(b.String()).To(Equal("" +
+			"[@0,0:4='\"a b\"',<WORD>,1:0]\n" +
+			"[@1,6:6='c',<WORD>,1:6]\n" +
+			"[@2,7:6='<EOF>',<EOF>,1:7]\n"))
+	})
+
+	It("should write token types in ANTLR's .tokens format (i.e. ExportANTLRVocabulary)", func() {
+		var b bytes.Buffer
+		Expect(lexer.ExportANTLRVocabulary(&b, []lexer.TokenType{Word, 7})).To(Succeed())
+		Expect(b.String()).To(Equal("WORD=102\n"))
+	})
+})
@@ -0,0 +1,271 @@
+package lexer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+const binaryMagic = "LXTK\x01"
+
+// maxBinaryTriviaDepth limits the nesting of trivia decoded, so that corrupt input cannot
+// exhaust the stack, and maxBinaryTrivia the trivia of a token.
+const (
+	maxBinaryTriviaDepth = 8
+	maxBinaryTrivia      = 1 << 20
+)
+
+var errInvalidBinaryToken = errors.New("lexer: invalid binary token")
+
+const (
+	binaryNil byte = iota
+	binaryString
+	binaryLexError
+	binaryInt
+	binaryFloat
+	binaryBool
+)
+
+// TokenBinaryEncoder writes tokens to an io.Writer in a compact, varint-based binary form
+// that can be read back by a TokenBinaryDecoder.
+//
+// Token values must be nil, a string, a LexError, an int, a float64, or a bool.
+type TokenBinaryEncoder struct {
+	w       io.Writer
+	started bool
+	buffer  []byte
+}
+
+// NewTokenBinaryEncoder creates an encoder that writes to w.
+func NewTokenBinaryEncoder(w io.Writer) *TokenBinaryEncoder {
+	return &TokenBinaryEncoder{w: w}
+}
+
+// Encode writes the token to the underlying writer.
+func (e *TokenBinaryEncoder) Encode(t Token) error {
+	e.buffer = e.buffer[:0]
+	if !e.started {
+		e.buffer = append(e.buffer, binaryMagic...)
+		e.started = true
+	}
+	var err error
+	if e.buffer, err = appendBinaryToken(e.buffer, t); err != nil {
+		return err
+	}
+	_, err = e.w.Write(e.buffer)
+	return err
+}
+
+// EncodeTokens writes the tokens to w in the form written by a TokenBinaryEncoder.
+func EncodeTokens(w io.Writer, tokens []Token) error {
+	e := NewTokenBinaryEncoder(w)
+	for _, t := range tokens {
+		if err := e.Encode(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendBinaryToken(b []byte, t Token) ([]byte, error) {
+	b = binary.AppendVarint(b, int64(t.Type))
+	b = binary.AppendUvarint(b, uint64(t.Position))
+	b = binary.AppendUvarint(b, uint64(t.Line))
+	b = binary.AppendUvarint(b, uint64(t.Column))
+	switch v := t.Value.(type) {
+	case nil:
+		b = append(b, binaryNil)
+	case string:
+		b = appendBinaryString(append(b, binaryString), v)
+	case LexError:
+		b = appendBinaryString(append(b, binaryLexError), v.Code)
+		b = appendBinaryString(b, v.Message)
+		b = binary.AppendUvarint(b, uint64(v.Position))
+		b = binary.AppendUvarint(b, uint64(v.Severity))
+		b = appendBinaryString(b, v.State)
+	case int:
+		b = binary.AppendVarint(append(b, binaryInt), int64(v))
+	case float64:
+		b = binary.BigEndian.AppendUint64(append(b, binaryFloat), math.Float64bits(v))
+	case bool:
+		if v {
+			b = append(b, binaryBool, 1)
+		} else {
+			b = append(b, binaryBool, 0)
+		}
+	default:
+		return nil, fmt.Errorf("lexer: cannot encode token value of type %T", t.Value)
+	}
+	b = appendBinaryString(b, t.Raw)
+	b = binary.AppendUvarint(b, uint64(len(t.Trivia)))
+	var err error
+	for _, trivia := range t.Trivia {
+		if b, err = appendBinaryToken(b, trivia); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func appendBinaryString(b []byte, s string) []byte {
+	return append(binary.AppendUvarint(b, uint64(len(s))), s...)
+}
+
+// TokenBinaryDecoder reads tokens written by a TokenBinaryEncoder from an io.Reader.
+type TokenBinaryDecoder struct {
+	r       *bufio.Reader
+	started bool
+}
+
+// NewTokenBinaryDecoder creates a decoder that reads from r.
+func NewTokenBinaryDecoder(r io.Reader) *TokenBinaryDecoder {
+	return &TokenBinaryDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next token from the underlying reader.
+//
+// Returns io.EOF if there are no more tokens.
+func (d *TokenBinaryDecoder) Decode() (Token, error) {
+	if !d.started {
+		magic := make([]byte, len(binaryMagic))
+		if _, err := io.ReadFull(d.r, magic); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = errors.New("lexer: invalid binary token stream")
+			}
+			return Token{}, err
+		}
+		if string(magic) != binaryMagic {
+			return Token{}, errors.New("lexer: invalid binary token stream")
+		}
+		d.started = true
+	}
+	if _, err := d.r.Peek(1); err != nil {
+		return Token{}, err
+	}
+	t, err := d.token(0)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return t, err
+}
+
+// DecodeTokens reads all tokens written by a TokenBinaryEncoder from r.
+func DecodeTokens(r io.Reader) ([]Token, error) {
+	d := NewTokenBinaryDecoder(r)
+	var tokens []Token
+	for {
+		t, err := d.Decode()
+		if err == io.EOF {
+			return tokens, nil
+		}
+		if err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, t)
+	}
+}
+
+func (d *TokenBinaryDecoder) token(depth int) (Token, error) {
+	var t Token
+	tokenType, err := binary.ReadVarint(d.r)
+	if err != nil {
+		return t, err
+	}
+	t.Type = TokenType(tokenType)
+	var position, line, column uint64
+	for _, u := range []*uint64{&position, &line, &column} {
+		if *u, err = binary.ReadUvarint(d.r); err != nil {
+			return t, err
+		}
+	}
+	t.Position, t.Line, t.Column = RunePosition(position), int(line), int(column)
+	if t.Value, err = d.value(); err != nil {
+		return t, err
+	}
+	if t.Raw, err = d.string(); err != nil {
+		return t, err
+	}
+	n, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return t, err
+	}
+	if n > 0 && depth >= maxBinaryTriviaDepth || n > maxBinaryTrivia {
+		return t, errInvalidBinaryToken
+	}
+	for i := uint64(0); i < n; i++ {
+		trivia, err := d.token(depth + 1)
+		if err != nil {
+			return t, err
+		}
+		t.Trivia = append(t.Trivia, trivia)
+	}
+	return t, nil
+}
+
+func (d *TokenBinaryDecoder) value() (interface{}, error) {
+	kind, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case binaryNil:
+		return nil, nil
+	case binaryString:
+		return d.string()
+	case binaryLexError:
+		var e LexError
+		if e.Code, err = d.string(); err != nil {
+			return nil, err
+		}
+		if e.Message, err = d.string(); err != nil {
+			return nil, err
+		}
+		position, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return nil, err
+		}
+		severity, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return nil, err
+		}
+		e.Position, e.Severity = RunePosition(position), Severity(severity)
+		if e.State, err = d.string(); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case binaryInt:
+		i, err := binary.ReadVarint(d.r)
+		return int(i), err
+	case binaryFloat:
+		var b [8]byte
+		if _, err := io.ReadFull(d.r, b[:]); err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b[:])), nil
+	case binaryBool:
+		b, err := d.r.ReadByte()
+		return b == 1, err
+	}
+	return nil, fmt.Errorf("lexer: invalid token value kind %d", kind)
+}
+
+func (d *TokenBinaryDecoder) string() (string, error) {
+	n, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return "", err
+	}
+	if n > math.MaxInt32 {
+		return "", errInvalidBinaryToken
+	}
+	// The string is read as it is copied, rather than allocated up front, so that a corrupt
+	// length fails at the end of the input.
+	var b strings.Builder
+	if _, err := io.CopyN(&b, d.r, int64(n)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
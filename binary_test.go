@@ -0,0 +1,75 @@
+package lexer_test
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Binary encoding", func() {
+	tokens := []lexer.Token{
+		{Type: Token, Value: "x", Raw: "x", Position: 2, Line: 1, Column: 3, Trivia: []lexer.Token{
+			{Type: lexer.TokenTrivia, Value: "  ", Raw: "  ", Line: 1, Column: 1},
+		}},
+		{Type: lexer.TokenError, Value: lexer.LexError{Code: "E1", Message: "m", Position: 4, Severity: lexer.SeverityError, State: "s"}},
+		{Type: 7, Value: 42},
+		{Type: 8, Value: 2.5},
+		{Type: 9, Value: true},
+		{Type: 10},
+	}
+
+	It("should decode encoded tokens (i.e. EncodeTokens and DecodeTokens)", func() {
+		var b bytes.Buffer
+		Expect(lexer.EncodeTokens(&b, tokens)).To(Succeed())
+		decoded, err := lexer.DecodeTokens(&b)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(Equal(tokens))
+	})
+
+	It("should decode tokens one at a time (i.e. TokenBinaryDecoder)", func() {
+		var b bytes.Buffer
+		e := lexer.NewTokenBinaryEncoder(&b)
+		Expect(e.Encode(tokens[0])).To(Succeed())
+		d := lexer.NewTokenBinaryDecoder(&b)
+		t, err := d.Decode()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(t).To(Equal(tokens[0]))
+		_, err = d.Decode()
+		Expect(err).To(Equal(io.EOF))
+	})
+
+	It("should reject invalid streams and values", func() {
+		_, err := lexer.DecodeTokens(bytes.NewBufferString("nope!"))
+		Expect(err).To(MatchError("lexer: invalid binary token stream"))
+		var b bytes.Buffer
+		Expect(lexer.EncodeTokens(&b, tokens)).To(Succeed())
+		_, err = lexer.DecodeTokens(bytes.NewReader(b.Bytes()[:b.Len()-3]))
+		Expect(err).To(Equal(io.ErrUnexpectedEOF))
+		Expect(lexer.EncodeTokens(&b, []lexer.Token{{Type: Token, Value: []int{1}}})).To(MatchError("lexer: cannot encode token value of type []int"))
+	})
+
+	It("should reject corrupt lengths and trivia without panicking (i.e. DecodeTokens)", func() {
+		// A string value whose length exceeds the input, and one exceeding any input.
+		for _, length := range [][]byte{{0xff, 0xff, 0x03}, {0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}} {
+			stream := append([]byte("LXTK\x01\x00\x00\x00\x00\x01"), length...)
+			Expect(func() {
+				_, err := lexer.DecodeTokens(bytes.NewReader(stream))
+				Expect(err).To(HaveOccurred())
+			}).NotTo(Panic())
+		}
+		_, err := lexer.DecodeTokens(bytes.NewReader([]byte("LXTK\x01\x00\x00\x00\x00\x00\x00\xff\xff\xff\xff\x0f")))
+		Expect(err).To(MatchError("lexer: invalid binary token"))
+		t := lexer.Token{Type: Token}
+		for i := 0; i < 20; i++ {
+			t = lexer.Token{Type: Token, Trivia: []lexer.Token{t}}
+		}
+		var b bytes.Buffer
+		Expect(lexer.EncodeTokens(&b, []lexer.Token{t})).To(Succeed())
+		_, err = lexer.DecodeTokens(&b)
+		Expect(err).To(MatchError("lexer: invalid binary token"))
+	})
+})
@@ -0,0 +1,126 @@
+package lexer
+
+import "strings"
+
+// Category represents a standard syntax highlighting category, named as by Pygments and
+// chroma (e.g. Keyword, Name.Function, or Literal.String).
+//
+// Categories form a hierarchy; each dot-separated component refines its parent.
+type Category string
+
+const (
+	CategoryText        Category = "Text"
+	CategoryWhitespace  Category = "Text.Whitespace"
+	CategoryError       Category = "Error"
+	CategoryOther       Category = "Other"
+	CategoryKeyword     Category = "Keyword"
+	CategoryConstant    Category = "Keyword.Constant"
+	CategoryDeclaration Category = "Keyword.Declaration"
+	CategoryNamespace   Category = "Keyword.Namespace"
+	CategoryReserved    Category = "Keyword.Reserved"
+	CategoryType        Category = "Keyword.Type"
+	CategoryName        Category = "Name"
+	CategoryAttribute   Category = "Name.Attribute"
+	CategoryBuiltin     Category = "Name.Builtin"
+	CategoryClass       Category = "Name.Class"
+	CategoryFunction    Category = "Name.Function"
+	CategoryLabel       Category = "Name.Label"
+	CategoryTag         Category = "Name.Tag"
+	CategoryVariable    Category = "Name.Variable"
+	CategoryLiteral     Category = "Literal"
+	CategoryDate        Category = "Literal.Date"
+	CategoryString      Category = "Literal.String"
+	CategoryChar        Category = "Literal.String.Char"
+	CategoryEscape      Category = "Literal.String.Escape"
+	CategoryNumber      Category = "Literal.Number"
+	CategoryFloat       Category = "Literal.Number.Float"
+	CategoryHex         Category = "Literal.Number.Hex"
+	CategoryInteger     Category = "Literal.Number.Integer"
+	CategoryOperator    Category = "Operator"
+	CategoryPunctuation Category = "Punctuation"
+	CategoryComment     Category = "Comment"
+	CategoryMultiline   Category = "Comment.Multiline"
+	CategoryPreproc     Category = "Comment.Preproc"
+	CategorySingle      Category = "Comment.Single"
+)
+
+var categoryClasses = map[Category]string{
+	CategoryText:        "",
+	CategoryWhitespace:  "w",
+	CategoryError:       "err",
+	CategoryOther:       "x",
+	CategoryKeyword:     "k",
+	CategoryConstant:    "kc",
+	CategoryDeclaration: "kd",
+	CategoryNamespace:   "kn",
+	CategoryReserved:    "kr",
+	CategoryType:        "kt",
+	CategoryName:        "n",
+	CategoryAttribute:   "na",
+	CategoryBuiltin:     "nb",
+	CategoryClass:       "nc",
+	CategoryFunction:    "nf",
+	CategoryLabel:       "nl",
+	CategoryTag:         "nt",
+	CategoryVariable:    "nv",
+	CategoryLiteral:     "l",
+	CategoryDate:        "ld",
+	CategoryString:      "s",
+	CategoryChar:        "sc",
+	CategoryEscape:      "se",
+	CategoryNumber:      "m",
+	CategoryFloat:       "mf",
+	CategoryHex:         "mh",
+	CategoryInteger:     "mi",
+	CategoryOperator:    "o",
+	CategoryPunctuation: "p",
+	CategoryComment:     "c",
+	CategoryMultiline:   "cm",
+	CategoryPreproc:     "cp",
+	CategorySingle:      "c1",
+}
+
+// Parent returns the category refined by this category, or the empty category if it is a
+// top-level category.
+func (c Category) Parent() Category {
+	if i := strings.LastIndexByte(string(c), '.'); i >= 0 {
+		return c[:i]
+	}
+	return ""
+}
+
+// In reports whether the category is, or refines, the specified category.
+func (c Category) In(parent Category) bool {
+	return c == parent || strings.HasPrefix(string(c), string(parent)+".")
+}
+
+// Class returns the short CSS class Pygments and chroma use for the category (e.g. "kd" for
+// Keyword.Declaration), falling back to the class of the nearest known parent.
+func (c Category) Class() string {
+	for ; c != ""; c = c.Parent() {
+		if class, ok := categoryClasses[c]; ok {
+			return class
+		}
+	}
+	return ""
+}
+
+// CategoryMap maps token types to highlighting categories.
+type CategoryMap map[TokenType]Category
+
+// Categorize returns the category of the token type.
+//
+// Error tokens and trivia are categorized as Error and Text.Whitespace unless mapped
+// otherwise; other unmapped token types are categorized as Text.
+func (m CategoryMap) Categorize(tokenType TokenType) Category {
+	if c, ok := m[tokenType]; ok {
+		return c
+	}
+	switch tokenType {
+	case TokenError:
+		return CategoryError
+	case TokenTrivia:
+		return CategoryWhitespace
+	}
+	return CategoryText
+}
@@ -0,0 +1,33 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Categories", func() {
+	It("should navigate the category hierarchy (i.e. Parent and In)", func() {
+		Expect(lexer.CategoryChar.Parent()).To(Equal(lexer.CategoryString))
+		Expect(lexer.CategoryString.Parent()).To(Equal(lexer.CategoryLiteral))
+		Expect(lexer.CategoryLiteral.Parent()).To(Equal(lexer.Category("")))
+		Expect(lexer.CategoryChar.In(lexer.CategoryLiteral)).To(BeTrue())
+		Expect(lexer.CategoryLiteral.In(lexer.CategoryLiteral)).To(BeTrue())
+		Expect(lexer.Category("Literal2").In(lexer.CategoryLiteral)).To(BeFalse())
+	})
+
+	It("should return the Pygments CSS class of a category (i.e. Class)", func() {
+		Expect(lexer.CategoryDeclaration.Class()).To(Equal("kd"))
+		Expect(lexer.Category("Name.Function.Magic").Class()).To(Equal("nf"))
+		Expect(lexer.CategoryText.Class()).To(Equal(""))
+	})
+
+	It("should map token types to categories (i.e. CategoryMap)", func() {
+		m := lexer.CategoryMap{Token: lexer.CategoryKeyword}
+		Expect(m.Categorize(Token)).To(Equal(lexer.CategoryKeyword))
+		Expect(m.Categorize(lexer.TokenError)).To(Equal(lexer.CategoryError))
+		Expect(m.Categorize(lexer.TokenTrivia)).To(Equal(lexer.CategoryWhitespace))
+		Expect(m.Categorize(42)).To(Equal(lexer.CategoryText))
+	})
+})
@@ -0,0 +1,75 @@
+package lexer
+
+import (
+	"errors"
+	"io"
+	"iter"
+)
+
+var errStageStopped = errors.New("lexer: stage stopped")
+
+// Stage is a second-stage lexer (e.g. a screener classifying identifiers) that reads the
+// tokens emitted by the previous stage from in and emits its own tokens to out, until in
+// returns io.EOF.
+//
+// If writing to out returns an error the stage should stop and return it.
+type Stage func(in TokenReader, out TokenWriter) error
+
+// StageReader reads the tokens emitted by the last of a chain of stages.
+type StageReader struct {
+	next func() (Token, bool)
+	stop func()
+	err  error
+}
+
+// Chain returns a reader of the tokens read from the source after passing through each of
+// the stages in turn.
+//
+// Stages run lazily as tokens are read; a stage's error is returned by ReadToken once the
+// tokens it emitted have been read.
+func Chain(source TokenReader, stages ...Stage) TokenReader {
+	for _, stage := range stages {
+		source = newStageReader(source, stage)
+	}
+	return source
+}
+
+func newStageReader(in TokenReader, stage Stage) *StageReader {
+	r := &StageReader{}
+	r.next, r.stop = iter.Pull(iter.Seq[Token](func(yield func(Token) bool) {
+		err := stage(in, stageWriter(yield))
+		if err != nil && err != errStageStopped {
+			r.err = err
+		}
+	}))
+	return r
+}
+
+// ReadToken returns the next token emitted by the stage.
+//
+// Returns io.EOF once the stage has returned without an error and every token it emitted
+// has been returned.
+func (r *StageReader) ReadToken() (Token, error) {
+	t, ok := r.next()
+	if !ok {
+		if r.err != nil {
+			return Token{}, r.err
+		}
+		return Token{}, io.EOF
+	}
+	return t, nil
+}
+
+// Close stops the stage; any token it has not yet emitted is discarded.
+func (r *StageReader) Close() {
+	r.stop()
+}
+
+type stageWriter func(Token) bool
+
+func (w stageWriter) WriteToken(t Token) error {
+	if !w(t) {
+		return errStageStopped
+	}
+	return nil
+}
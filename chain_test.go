@@ -0,0 +1,122 @@
+package lexer_test
+
+import (
+	"errors"
+	"io"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Chain", func() {
+	const (
+		Word lexer.TokenType = iota
+		Punct
+		Keyword
+		Arrow
+	)
+
+	raw := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case unicode.IsLetter(r):
+				l.NextUpTo(func(r rune) bool {
+					return !unicode.IsLetter(r)
+				})
+				l.Emit(Word)
+			default:
+				l.Next()
+				l.Emit(Punct)
+			}
+		}
+	}
+
+	keywords := func(in lexer.TokenReader, out lexer.TokenWriter) error {
+		for {
+			t, err := in.ReadToken()
+			if err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			if t.Type == Word && (t.Value == "fn" || t.Value == "return") {
+				t.Type = Keyword
+			}
+			if err := out.WriteToken(t); err != nil {
+				return err
+			}
+		}
+	}
+
+	arrows := func(in lexer.TokenReader, out lexer.TokenWriter) error {
+		var lookahead *lexer.Token
+		for {
+			t, err := in.ReadToken()
+			if err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			if t.Value == "-" {
+				next, err := in.ReadToken()
+				if err == nil && next.Value == ">" {
+					t = lexer.Token{Type: Arrow, Value: "->", Position: t.Position, Line: t.Line, Column: t.Column}
+				} else if err == nil {
+					lookahead = &next
+				}
+			}
+			if err := out.WriteToken(t); err != nil {
+				return err
+			}
+			if lookahead != nil {
+				if err := out.WriteToken(*lookahead); err != nil {
+					return err
+				}
+				lookahead = nil
+			}
+		}
+	}
+
+	It("should pass tokens through each stage in turn (i.e. Chain)", func() {
+		tokens, err := lexer.ReadTokens(lexer.Chain(lexer.NewLexer("fn f -> x - y", raw), keywords, arrows))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(6))
+		Expect(tokens[0]).To(EqualToken(lexer.Token{Type: Keyword, Value: "fn"}))
+		Expect(tokens[2]).To(EqualToken(lexer.Token{Type: Arrow, Value: "->"}))
+		Expect(tokens[2].Column).To(Equal(6))
+		Expect(tokens[4]).To(EqualToken(lexer.Token{Type: Punct, Value: "-"}))
+		Expect(tokens[5]).To(EqualToken(lexer.Token{Type: Word, Value: "y"}))
+	})
+
+	It("should return the error of a stage (i.e. Chain)", func() {
+		failure := errors.New("screener failed")
+		r := lexer.Chain(lexer.NewTokenSliceReader(lexer.Token{Type: Word, Value: "a"}), keywords, func(in lexer.TokenReader, out lexer.TokenWriter) error {
+			t, _ := in.ReadToken()
+			out.WriteToken(t)
+			return failure
+		})
+		t, err := r.ReadToken()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(t.Value).To(Equal("a"))
+		_, err = r.ReadToken()
+		Expect(err).To(Equal(failure))
+	})
+
+	It("should stop the stages once closed (i.e. StageReader.Close)", func() {
+		r := lexer.Chain(lexer.NewLexer("a b c", raw), keywords).(*lexer.StageReader)
+		_, err := r.ReadToken()
+		Expect(err).NotTo(HaveOccurred())
+		r.Close()
+		_, err = r.ReadToken()
+		Expect(err).To(Equal(io.EOF))
+	})
+})
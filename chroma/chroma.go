@@ -0,0 +1,376 @@
+// Package chroma imports the XML lexer definitions of chroma (and of Pygments lexers
+// converted to them) as rule tables, so that the existing corpus of language definitions
+// can be reused for tokenization beyond syntax highlighting, e.g.
+//
+//	def, err := chroma.Parse(f)
+//	rules, types, err := def.Rules()
+//	state, err := rules.State()
+//
+// The states of a definition are mapped to exclusive modes, root being lexer.ModeInitial,
+// and its rules to matchers tried in order, the first that matches being applied, as chroma
+// does. A subset of chroma is supported:
+//
+//   - rules emitting a token, or a token for each group (bygroups)
+//   - rules pushing states (including #push and #pop), or popping them
+//   - rules including the rules of another state
+//   - rules without a pattern, changing the state without consuming input
+//   - the case_insensitive, dot_all, and not_multiline options
+//
+// Patterns are compiled by the regexp package, so patterns using features of other regular
+// expression engines (e.g. lookahead or backreferences) are not supported, and neither are
+// rules delegating to other lexers (using, usingself, and combined). Patterns are matched
+// at the position of the lexer in its input, so that assertions at their start (e.g. ^ or
+// \b) apply to the rune preceding it.
+package chroma
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/eczarny/lexer"
+)
+
+// RootState is the name of the state chroma lexers start in.
+const RootState = "root"
+
+// Definition is a chroma lexer definition.
+type Definition struct {
+	Name      string
+	Aliases   []string
+	Filenames []string
+	MimeTypes []string
+
+	config xmlConfig
+	states []xmlState
+}
+
+type xmlLexer struct {
+	Config xmlConfig  `xml:"config"`
+	States []xmlState `xml:"rules>state"`
+}
+
+type xmlConfig struct {
+	Name            string   `xml:"name"`
+	Aliases         []string `xml:"alias"`
+	Filenames       []string `xml:"filename"`
+	MimeTypes       []string `xml:"mime_type"`
+	CaseInsensitive bool     `xml:"case_insensitive"`
+	DotAll          bool     `xml:"dot_all"`
+	NotMultiline    bool     `xml:"not_multiline"`
+}
+
+type xmlState struct {
+	Name  string    `xml:"name,attr"`
+	Rules []xmlRule `xml:"rule"`
+}
+
+type xmlRule struct {
+	Pattern  *string      `xml:"pattern,attr"`
+	Token    *xmlToken    `xml:"token"`
+	ByGroups *xmlByGroups `xml:"bygroups"`
+	Push     []xmlPush    `xml:"push"`
+	Pop      *xmlPop      `xml:"pop"`
+	Include  *xmlInclude  `xml:"include"`
+	Other    []xmlElement `xml:",any"`
+}
+
+type xmlToken struct {
+	Type string `xml:"type,attr"`
+}
+
+type xmlByGroups struct {
+	Tokens []xmlToken   `xml:"token"`
+	Other  []xmlElement `xml:",any"`
+}
+
+type xmlPush struct {
+	State string `xml:"state,attr"`
+}
+
+type xmlPop struct {
+	Depth int `xml:"depth,attr"`
+}
+
+type xmlInclude struct {
+	State string `xml:"state,attr"`
+}
+
+type xmlElement struct {
+	XMLName xml.Name
+}
+
+// Parse parses the chroma lexer definition read from r.
+func Parse(r io.Reader) (*Definition, error) {
+	var l xmlLexer
+	if err := xml.NewDecoder(r).Decode(&l); err != nil {
+		return nil, fmt.Errorf("chroma: %w", err)
+	}
+	return &Definition{
+		Name:      l.Config.Name,
+		Aliases:   l.Config.Aliases,
+		Filenames: l.Config.Filenames,
+		MimeTypes: l.Config.MimeTypes,
+		config:    l.Config,
+		states:    l.States,
+	}, nil
+}
+
+// Rules returns the rule table of the definition, and the token types of the names of the
+// chroma token types it emits (e.g. KeywordNamespace), numbered in the order they first
+// appear. The states other than root are numbered in order from 1.
+//
+// Returns an error if the definition has no root state, or a rule is not supported.
+func (d *Definition) Rules() (*lexer.Rules, map[string]lexer.TokenType, error) {
+	c := &compiler{
+		definition: d,
+		modes:      map[string]lexer.Mode{RootState: lexer.ModeInitial},
+		states:     map[string]xmlState{},
+		types:      map[string]lexer.TokenType{},
+	}
+	for _, s := range d.states {
+		if _, ok := c.states[s.Name]; ok {
+			return nil, nil, fmt.Errorf("chroma: duplicate state %q", s.Name)
+		}
+		c.states[s.Name] = s
+		if s.Name != RootState {
+			c.modes[s.Name] = lexer.Mode(len(c.modes))
+		}
+	}
+	if _, ok := c.states[RootState]; !ok {
+		return nil, nil, fmt.Errorf("chroma: no %s state", RootState)
+	}
+	rules := lexer.NewRules()
+	if err := c.addRules(rules, RootState, RootState, nil); err != nil {
+		return nil, nil, err
+	}
+	for _, s := range d.states {
+		if s.Name == RootState {
+			continue
+		}
+		modeRules := lexer.NewRules()
+		if err := c.addRules(modeRules, s.Name, s.Name, nil); err != nil {
+			return nil, nil, err
+		}
+		rules.Exclusive(c.modes[s.Name], modeRules)
+	}
+	return rules, c.types, nil
+}
+
+// Categories returns the highlighting categories of the token types (e.g. Keyword.Namespace
+// for KeywordNamespace), e.g. to render the tokens as HTML (see lexer.RenderHTML).
+func Categories(types map[string]lexer.TokenType) lexer.CategoryMap {
+	categories := lexer.CategoryMap{}
+	for name, t := range types {
+		var category strings.Builder
+		for i, r := range name {
+			if i > 0 && unicode.IsUpper(r) {
+				category.WriteByte('.')
+			}
+			category.WriteRune(r)
+		}
+		categories[t] = lexer.Category(category.String())
+	}
+	return categories
+}
+
+type compiler struct {
+	definition *Definition
+	modes      map[string]lexer.Mode
+	states     map[string]xmlState
+	types      map[string]lexer.TokenType
+}
+
+// addRules adds the rules of the state to the table, as rules of the mode, including the
+// rules of the states it includes.
+func (c *compiler) addRules(rules *lexer.Rules, mode, state string, including []string) error {
+	for _, s := range including {
+		if s == state {
+			return fmt.Errorf("chroma: state %q includes itself", state)
+		}
+	}
+	for i, r := range c.states[state].Rules {
+		if r.Include != nil {
+			if _, ok := c.states[r.Include.State]; !ok {
+				return fmt.Errorf("chroma: state %q: rule %d includes unknown state %q", state, i, r.Include.State)
+			}
+			if err := c.addRules(rules, mode, r.Include.State, append(including, state)); err != nil {
+				return err
+			}
+			continue
+		}
+		m, err := c.matcher(mode, r)
+		if err != nil {
+			return fmt.Errorf("chroma: state %q: rule %d: %w", state, i, err)
+		}
+		rules.Match(m)
+	}
+	return nil
+}
+
+// matcher returns a matcher applying the rule in the mode.
+func (c *compiler) matcher(mode string, r xmlRule) (lexer.Matcher, error) {
+	if len(r.Other) > 0 {
+		return nil, fmt.Errorf("%s is not supported", r.Other[0].XMLName.Local)
+	}
+	if r.ByGroups != nil && len(r.ByGroups.Other) > 0 {
+		return nil, fmt.Errorf("%s is not supported", r.ByGroups.Other[0].XMLName.Local)
+	}
+	change, err := c.stateChange(mode, r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Pattern == nil {
+		return func(l *lexer.Lexer) bool {
+			return change(l)
+		}, nil
+	}
+	match, err := c.compile(*r.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	var types []lexer.TokenType
+	switch {
+	case r.ByGroups != nil:
+		for _, t := range r.ByGroups.Tokens {
+			types = append(types, c.tokenType(t.Type))
+		}
+	case r.Token != nil:
+		types = append(types, c.tokenType(r.Token.Type))
+	}
+	return func(l *lexer.Lexer) bool {
+		position := l.CurrentPosition
+		loc := match(l)
+		if loc == nil || loc[1] == 0 && !change(l) {
+			return false
+		}
+		if r.ByGroups != nil {
+			for i, t := range types {
+				if 2*i+3 >= len(loc) || loc[2*i+2] < 0 {
+					continue
+				}
+				groupStart, groupEnd := position+lexer.RunePosition(loc[2*i+2]), position+lexer.RunePosition(loc[2*i+3])
+				if groupStart < l.CurrentPosition {
+					continue
+				}
+				advance(l, groupStart)
+				l.EmitTrivia(lexer.TokenTrivia)
+				advance(l, groupEnd)
+				l.EmitNonEmpty(t)
+			}
+			advance(l, position+lexer.RunePosition(loc[1]))
+			l.EmitTrivia(lexer.TokenTrivia)
+		} else {
+			advance(l, position+lexer.RunePosition(loc[1]))
+			if len(types) > 0 {
+				l.EmitNonEmpty(types[0])
+			} else {
+				l.EmitTrivia(lexer.TokenTrivia)
+			}
+		}
+		if loc[1] > 0 {
+			change(l)
+		}
+		return true
+	}, nil
+}
+
+// stateChange returns a function applying the state changes of the rule, returning false
+// if the rule does not change the state.
+func (c *compiler) stateChange(mode string, r xmlRule) (func(*lexer.Lexer) bool, error) {
+	var pushes []func(*lexer.Lexer)
+	for _, p := range r.Push {
+		switch p.State {
+		case "", "#push":
+			pushes = append(pushes, func(l *lexer.Lexer) {
+				l.PushMode(l.Mode())
+			})
+		case "#pop":
+			pushes = append(pushes, func(l *lexer.Lexer) {
+				l.PopMode()
+			})
+		default:
+			m, ok := c.modes[p.State]
+			if !ok {
+				return nil, fmt.Errorf("pushes unknown state %q", p.State)
+			}
+			pushes = append(pushes, func(l *lexer.Lexer) {
+				l.PushMode(m)
+			})
+		}
+	}
+	depth := 0
+	if r.Pop != nil {
+		depth = r.Pop.Depth
+	}
+	return func(l *lexer.Lexer) bool {
+		for i := 0; i < depth; i++ {
+			l.PopMode()
+		}
+		for _, push := range pushes {
+			push(l)
+		}
+		return depth > 0 || len(pushes) > 0
+	}, nil
+}
+
+// compile compiles the pattern with the flags of the definition, returning a function
+// matching it at the position of the lexer, and returning the locations of the match and
+// its groups relative to the position, or nil.
+func (c *compiler) compile(pattern string) (func(*lexer.Lexer) []int, error) {
+	flags := ""
+	if !c.definition.config.NotMultiline {
+		flags += "m"
+	}
+	if c.definition.config.DotAll {
+		flags += "s"
+	}
+	if c.definition.config.CaseInsensitive {
+		flags += "i"
+	}
+	expr := "(?" + flags + ":" + pattern + ")"
+	if flags == "" {
+		expr = "(?:" + pattern + ")"
+	}
+	re, err := regexp.Compile("^" + expr)
+	if err != nil {
+		return nil, err
+	}
+	// Past the start of the input, the pattern is matched following the rune preceding the
+	// position, so that assertions at its start see that rune.
+	following := regexp.MustCompile("^(?s:.)" + expr)
+	return func(l *lexer.Lexer) []int {
+		position := int(l.CurrentPosition)
+		if position == 0 {
+			return re.FindStringSubmatchIndex(l.Input)
+		}
+		_, w := utf8.DecodeLastRuneInString(l.Input[:position])
+		loc := following.FindStringSubmatchIndex(l.Input[position-w:])
+		for i := range loc {
+			if loc[i] >= 0 {
+				loc[i] -= w
+			}
+		}
+		return loc
+	}, nil
+}
+
+func (c *compiler) tokenType(name string) lexer.TokenType {
+	t, ok := c.types[name]
+	if !ok {
+		t = lexer.TokenType(len(c.types))
+		c.types[name] = t
+	}
+	return t
+}
+
+// advance consumes the input up to the position.
+func advance(l *lexer.Lexer, position lexer.RunePosition) {
+	for l.CurrentPosition < position {
+		l.Next()
+	}
+}
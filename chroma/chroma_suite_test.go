@@ -0,0 +1,13 @@
+package chroma_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestChroma(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Chroma Suite")
+}
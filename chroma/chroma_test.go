@@ -0,0 +1,144 @@
+package chroma_test
+
+import (
+	"strings"
+
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/chroma"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Chroma", func() {
+	const definition = `<lexer>
+  <config>
+    <name>Calc</name>
+    <alias>calc</alias>
+    <filename>*.calc</filename>
+    <mime_type>text/x-calc</mime_type>
+  </config>
+  <rules>
+    <state name="whitespace">
+      <rule pattern="\s+"><token type="TextWhitespace"/></rule>
+      <rule pattern="^#[^\n]*"><token type="CommentPreproc"/></rule>
+    </state>
+    <state name="root">
+      <rule><include state="whitespace"/></rule>
+      <rule pattern="(func)(\s+)(\w+)">
+        <bygroups><token type="KeywordDeclaration"/><token type="TextWhitespace"/><token type="NameFunction"/></bygroups>
+      </rule>
+      <rule pattern="\d+"><token type="LiteralNumber"/></rule>
+      <rule pattern="\bif\b"><token type="Keyword"/></rule>
+      <rule pattern="\w+"><token type="Name"/></rule>
+      <rule pattern="&quot;"><token type="LiteralString"/><push state="string"/></rule>
+      <rule pattern="[-+*/#]"><token type="Operator"/></rule>
+    </state>
+    <state name="string">
+      <rule pattern="&quot;"><token type="LiteralString"/><pop depth="1"/></rule>
+      <rule pattern="\$\{"><token type="LiteralStringInterpol"/><push state="interpolation"/></rule>
+      <rule pattern="[^&quot;$]+"><token type="LiteralString"/></rule>
+    </state>
+    <state name="interpolation">
+      <rule pattern="\}"><token type="LiteralStringInterpol"/><pop depth="1"/></rule>
+      <rule><include state="root"/></rule>
+    </state>
+  </rules>
+</lexer>`
+
+	type token struct {
+		Type  string
+		Value string
+	}
+
+	lex := func(input string) []token {
+		def, err := chroma.Parse(strings.NewReader(definition))
+		Expect(err).NotTo(HaveOccurred())
+		rules, types, err := def.Rules()
+		Expect(err).NotTo(HaveOccurred())
+		names := map[lexer.TokenType]string{}
+		for name, t := range types {
+			names[t] = name
+		}
+		state, err := rules.State()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize(input, state)
+		Expect(err).NotTo(HaveOccurred())
+		var ts []token
+		for _, t := range tokens {
+			ts = append(ts, token{names[t.Type], t.Value.(string)})
+		}
+		return ts
+	}
+
+	It("should parse the configuration of definitions (i.e. Parse)", func() {
+		def, err := chroma.Parse(strings.NewReader(definition))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(def.Name).To(Equal("Calc"))
+		Expect(def.Aliases).To(Equal([]string{"calc"}))
+		Expect(def.Filenames).To(Equal([]string{"*.calc"}))
+		Expect(def.MimeTypes).To(Equal([]string{"text/x-calc"}))
+	})
+
+	It("should import rules, groups, and states (i.e. Rules)", func() {
+		Expect(lex("func f 1")).To(Equal([]token{
+			{"KeywordDeclaration", "func"},
+			{"TextWhitespace", " "},
+			{"NameFunction", "f"},
+			{"TextWhitespace", " "},
+			{"LiteralNumber", "1"},
+		}))
+		Expect(lex(`"a ${b "c"} d"`)).To(Equal([]token{
+			{"LiteralString", `"`},
+			{"LiteralString", "a "},
+			{"LiteralStringInterpol", "${"},
+			{"Name", "b"},
+			{"TextWhitespace", " "},
+			{"LiteralString", `"`},
+			{"LiteralString", "c"},
+			{"LiteralString", `"`},
+			{"LiteralStringInterpol", "}"},
+			{"LiteralString", " d"},
+			{"LiteralString", `"`},
+		}))
+	})
+
+	It("should check assertions at the start of patterns against the preceding input (i.e. Rules)", func() {
+		Expect(lex("#x\n1 # y")).To(Equal([]token{
+			{"CommentPreproc", "#x"},
+			{"TextWhitespace", "\n"},
+			{"LiteralNumber", "1"},
+			{"TextWhitespace", " "},
+			{"Operator", "#"},
+			{"TextWhitespace", " "},
+			{"Name", "y"},
+		}))
+		Expect(lex("1if if")).To(Equal([]token{
+			{"LiteralNumber", "1"},
+			{"Name", "if"},
+			{"TextWhitespace", " "},
+			{"Keyword", "if"},
+		}))
+	})
+
+	It("should map token types to categories (i.e. Categories)", func() {
+		categories := chroma.Categories(map[string]lexer.TokenType{"KeywordDeclaration": 0, "Name": 1})
+		Expect(categories).To(Equal(lexer.CategoryMap{0: lexer.CategoryDeclaration, 1: lexer.CategoryName}))
+	})
+
+	It("should report unsupported definitions (i.e. Rules)", func() {
+		rules := func(states string) error {
+			def, err := chroma.Parse(strings.NewReader("<lexer><rules>" + states + "</rules></lexer>"))
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = def.Rules()
+			return err
+		}
+		Expect(rules(`<state name="a"/>`)).To(MatchError(`chroma: no root state`))
+		Expect(rules(`<state name="root"><rule pattern="a(?=b)"><token type="Name"/></rule></state>`)).To(MatchError(ContainSubstring(`chroma: state "root": rule 0: error parsing regexp`)))
+		Expect(rules(`<state name="root"><rule pattern="a"><usingself state="root"/></rule></state>`)).To(MatchError(`chroma: state "root": rule 0: usingself is not supported`))
+		Expect(rules(`<state name="root"><rule pattern="a"><push state="b"/></rule></state>`)).To(MatchError(`chroma: state "root": rule 0: pushes unknown state "b"`))
+		Expect(rules(`<state name="root"><rule><include state="root"/></rule></state>`)).To(MatchError(`chroma: state "root" includes itself`))
+		_, err := chroma.Parse(strings.NewReader("<lexer>"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,177 @@
+package lexer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// runeRange is an inclusive range of runes.
+type runeRange struct {
+	lo, hi rune
+}
+
+// DefineClass defines a named character class of the runes in any of the specified tables,
+// and returns the table. The patterns of the rules refer to the class as \p{name}, and to
+// its complement as \P{name}, both within and outside of bracketed character classes, e.g.
+//
+//	rules := lexer.NewRules().
+//		DefineClass("IDStart", unicode.L, unicode.Nl, unicode.Other_ID_Start).
+//		Add(Identifier, lexer.Regexp(`\p{IDStart}[\p{IDStart}\p{Mn}\p{Nd}_]*`))
+//
+// The classes of a table apply to the rules of its modes, which may define classes of their
+// own. A class overrides the Unicode class or script of the same name, if any, which the
+// patterns may otherwise refer to as well (e.g. \p{L} or \p{Greek}).
+func (r *Rules) DefineClass(name string, tables ...*unicode.RangeTable) *Rules {
+	if r.classes == nil {
+		r.classes = map[string][]runeRange{}
+	}
+	var ranges []runeRange
+	for _, t := range tables {
+		for _, r16 := range t.R16 {
+			ranges = appendRange(ranges, rune(r16.Lo), rune(r16.Hi), rune(r16.Stride))
+		}
+		for _, r32 := range t.R32 {
+			ranges = appendRange(ranges, rune(r32.Lo), rune(r32.Hi), rune(r32.Stride))
+		}
+	}
+	r.classes[name] = normalizeRanges(ranges)
+	return r
+}
+
+func appendRange(ranges []runeRange, lo, hi, stride rune) []runeRange {
+	if stride == 1 {
+		return append(ranges, runeRange{lo, hi})
+	}
+	for r := lo; r <= hi; r += stride {
+		ranges = append(ranges, runeRange{r, r})
+	}
+	return ranges
+}
+
+// normalizeRanges sorts the ranges and merges overlapping and adjacent ones.
+func normalizeRanges(ranges []runeRange) []runeRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].lo < ranges[j].lo
+	})
+	var merged []runeRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.lo <= merged[n-1].hi+1 {
+			if r.hi > merged[n-1].hi {
+				merged[n-1].hi = r.hi
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// complementRanges returns the ranges of the runes not in the normalized ranges.
+func complementRanges(ranges []runeRange) []runeRange {
+	var complement []runeRange
+	next := rune(0)
+	for _, r := range ranges {
+		if r.lo > next {
+			complement = append(complement, runeRange{next, r.lo - 1})
+		}
+		next = r.hi + 1
+	}
+	if next <= unicode.MaxRune {
+		complement = append(complement, runeRange{next, unicode.MaxRune})
+	}
+	return complement
+}
+
+// mergeClasses returns the classes of the table merged with the classes of a mode.
+func (r *Rules) mergeClasses(m *Rules) map[string][]runeRange {
+	if len(m.classes) == 0 {
+		return r.classes
+	}
+	classes := map[string][]runeRange{}
+	for name, ranges := range r.classes {
+		classes[name] = ranges
+	}
+	for name, ranges := range m.classes {
+		classes[name] = ranges
+	}
+	return classes
+}
+
+// expandClasses replaces the references to the classes in the regular expression with the
+// ranges of runes they contain.
+func expandClasses(expr string, classes map[string][]runeRange) string {
+	if len(classes) == 0 {
+		return expr
+	}
+	var b strings.Builder
+	bracket := false
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case c == '\\' && i+1 < len(expr):
+			if p := expr[i+1]; (p == 'p' || p == 'P') && i+2 < len(expr) && expr[i+2] == '{' {
+				if end := strings.IndexByte(expr[i+3:], '}'); end >= 0 {
+					if ranges, ok := classes[expr[i+3:i+3+end]]; ok {
+						if p == 'P' {
+							ranges = complementRanges(ranges)
+						}
+						writeRanges(&b, ranges, !bracket)
+						i += 3 + end
+						continue
+					}
+				}
+			}
+			b.WriteString(expr[i : i+2])
+			i++
+		case c == '[' && !bracket:
+			bracket = true
+			b.WriteByte(c)
+			if i+1 < len(expr) && expr[i+1] == '^' {
+				b.WriteByte('^')
+				i++
+			}
+			if i+1 < len(expr) && expr[i+1] == ']' {
+				b.WriteByte(']')
+				i++
+			}
+		case c == '[' && bracket && strings.HasPrefix(expr[i:], "[:"):
+			end := strings.Index(expr[i:], ":]")
+			if end < 0 {
+				b.WriteString(expr[i:])
+				return b.String()
+			}
+			b.WriteString(expr[i : i+end+2])
+			i += end + 1
+		case c == ']' && bracket:
+			bracket = false
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// writeRanges writes the ranges as the contents of a bracketed character class, or as a
+// bracketed character class if enclosed is true.
+func writeRanges(b *strings.Builder, ranges []runeRange, enclosed bool) {
+	if enclosed {
+		if len(ranges) == 0 {
+			// A class matching no runes.
+			b.WriteString(`[^\x00-\x{10FFFF}]`)
+			return
+		}
+		b.WriteByte('[')
+	}
+	for _, r := range ranges {
+		fmt.Fprintf(b, `\x{%x}`, r.lo)
+		if r.hi > r.lo {
+			fmt.Fprintf(b, `-\x{%x}`, r.hi)
+		}
+	}
+	if enclosed {
+		b.WriteByte(']')
+	}
+}
@@ -0,0 +1,137 @@
+// Command lexgen generates a standalone Go lexer from a rule spec (see Rules.Generate).
+//
+// Usage:
+//
+//	lexgen [-package name] [-o file] spec
+//
+// Each line of the spec is a rule: the name of a token type followed by a regular
+// expression, e.g.
+//
+//	# Arithmetic
+//	NUMBER  \d+(\.\d+)?
+//	PLUS    \+
+//	-       \s+
+//
+// Rules named - skip the input they match. Blank lines and lines starting with # are
+// ignored. Token types are numbered in the order they first appear.
+//
+// Specs with a .json, .yaml, or .yml extension are loaded as rulespec specs instead.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/rulespec"
+)
+
+func main() {
+	pkg := flag.String("package", "main", "package of the generated lexer")
+	output := flag.String("o", "", "file the generated lexer is written to (default standard output)")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: lexgen [-package name] [-o file] spec")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *pkg, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "lexgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(spec, pkg, output string) error {
+	f, err := os.Open(spec)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var rules *lexer.Rules
+	switch filepath.Ext(spec) {
+	case ".json", ".yaml", ".yml":
+		rules, err = loadSpec(f, filepath.Ext(spec) == ".json")
+	default:
+		rules, err = parseSpec(f)
+	}
+	if err != nil {
+		return fmt.Errorf("%s:%w", spec, err)
+	}
+	var w io.Writer = os.Stdout
+	if output != "" {
+		out, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		w = out
+	}
+	return rules.Generate(w, pkg)
+}
+
+func parseSpec(r io.Reader) (*lexer.Rules, error) {
+	rules := lexer.NewRules()
+	types := map[string]lexer.TokenType{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%d: expected a token type and a pattern", n)
+		}
+		name := fields[0]
+		pattern := lexer.Regexp(strings.TrimSpace(line[len(name):]))
+		if name == "-" {
+			rules.Skip(pattern)
+			continue
+		}
+		tokenType, ok := types[name]
+		if !ok {
+			tokenType = lexer.TokenType(len(types))
+			types[name] = tokenType
+			lexer.RegisterTokenType(tokenType, name)
+		}
+		rules.Add(tokenType, pattern)
+	}
+	return rules, scanner.Err()
+}
+
+func loadSpec(r io.Reader, isJSON bool) (*lexer.Rules, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	parse := rulespec.ParseYAML
+	if isJSON {
+		parse = rulespec.ParseJSON
+	}
+	spec, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	rules, types, err := spec.Table()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lexer.RegisterTokenType(types[name], name)
+	}
+	return rules, nil
+}
@@ -0,0 +1,70 @@
+package lexer
+
+// Case pairs a predicate with the state Choice transitions to when the next rune satisfies
+// it.
+type Case struct {
+	When RunePredicate
+	Then StateFunc
+}
+
+// Seq returns a state that runs each of the states, and the states they transition to, in
+// turn until they finish.
+//
+// The sequence stops once an error token has been emitted.
+func Seq(states ...StateFunc) StateFunc {
+	if len(states) == 0 {
+		return nil
+	}
+	return then(states[0], Seq(states[1:]...))
+}
+
+// Choice returns a state that transitions to the state of the first case whose predicate
+// the next rune satisfies.
+//
+// An error token is emitted if no case applies, unless the lexer is at the end of the
+// input, in which case the state finishes.
+func Choice(cases ...Case) StateFunc {
+	return func(l *Lexer) StateFunc {
+		r := l.Peek()
+		for _, c := range cases {
+			if c.When(r) {
+				return c.Then
+			}
+		}
+		if r == EOF {
+			return nil
+		}
+		return l.Errorf("unexpected %q", r)
+	}
+}
+
+// Loop returns a state that runs the state, and the states it transitions to, until they
+// finish, repeatedly until the next rune satisfies the predicate or the lexer is at the end
+// of the input.
+//
+// The state must consume input, or the loop never ends. The loop stops once an error token
+// has been emitted.
+func Loop(state StateFunc, until RunePredicate) StateFunc {
+	var loop StateFunc
+	loop = func(l *Lexer) StateFunc {
+		if r := l.Peek(); r == EOF || until(r) {
+			return nil
+		}
+		return then(state, loop)(l)
+	}
+	return loop
+}
+
+// then returns a state that runs the state, and the states it transitions to, until they
+// finish and then transitions to the next state, unless an error token has been emitted.
+func then(state StateFunc, next StateFunc) StateFunc {
+	return func(l *Lexer) StateFunc {
+		if s := state(l); s != nil {
+			return then(s, next)
+		}
+		if l.failed || next == nil {
+			return nil
+		}
+		return next
+	}
+}
@@ -0,0 +1,104 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Combinators", func() {
+	const (
+		Word lexer.TokenType = iota
+		Number
+		Punct
+	)
+
+	emit := func(tokenType lexer.TokenType, predicate lexer.RunePredicate) lexer.StateFunc {
+		return func(l *lexer.Lexer) lexer.StateFunc {
+			l.NextUpTo(func(r rune) bool {
+				return !predicate(r)
+			})
+			l.Emit(tokenType)
+			return nil
+		}
+	}
+	word := emit(Word, unicode.IsLetter)
+	number := emit(Number, unicode.IsDigit)
+	space := func(l *lexer.Lexer) lexer.StateFunc {
+		l.IgnoreUpTo(func(r rune) bool {
+			return !unicode.IsSpace(r)
+		})
+		return nil
+	}
+	punct := func(l *lexer.Lexer) lexer.StateFunc {
+		l.Next()
+		l.Emit(Punct)
+		return nil
+	}
+
+	values := func(tokens []lexer.Token) []interface{} {
+		var values []interface{}
+		for _, t := range tokens {
+			values = append(values, t.Value)
+		}
+		return values
+	}
+
+	It("should run states in turn (i.e. Seq)", func() {
+		tokens, err := lexer.Tokenize("abc 123", lexer.Seq(word, space, number))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values(tokens)).To(Equal([]interface{}{"abc", "123"}))
+	})
+
+	It("should run the states a state transitions to before the next (i.e. Seq)", func() {
+		twice := func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Emit(Punct)
+			return punct
+		}
+		tokens, err := lexer.Tokenize("+-x", lexer.Seq(twice, word))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values(tokens)).To(Equal([]interface{}{"+", "-", "x"}))
+	})
+
+	It("should transition to the state of the first matching case (i.e. Choice)", func() {
+		token := lexer.Choice(
+			lexer.Case{When: unicode.IsLetter, Then: word},
+			lexer.Case{When: unicode.IsDigit, Then: number},
+			lexer.Case{When: unicode.IsPunct, Then: punct},
+		)
+		tokens, err := lexer.Tokenize("1", token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(1))
+		Expect(tokens[0]).To(EqualToken(lexer.Token{Type: Number, Value: "1"}))
+		_, err = lexer.Tokenize("~", token)
+		Expect(err).To(MatchError(`unexpected '~'`))
+	})
+
+	It("should repeat a state until the predicate is satisfied (i.e. Loop)", func() {
+		token := lexer.Choice(
+			lexer.Case{When: unicode.IsSpace, Then: space},
+			lexer.Case{When: unicode.IsLetter, Then: word},
+			lexer.Case{When: unicode.IsDigit, Then: number},
+		)
+		semicolon := func(r rune) bool {
+			return r == ';'
+		}
+		tokens, err := lexer.Tokenize("a 1 b; c", lexer.Seq(lexer.Loop(token, semicolon), punct, lexer.Loop(token, semicolon)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values(tokens)).To(Equal([]interface{}{"a", "1", "b", ";", "c"}))
+	})
+
+	It("should stop once an error token has been emitted (i.e. Seq and Loop)", func() {
+		token := lexer.Choice(
+			lexer.Case{When: unicode.IsSpace, Then: space},
+			lexer.Case{When: unicode.IsLetter, Then: word},
+		)
+		tokens, err := lexer.Tokenize("a 1 b", lexer.Seq(lexer.Loop(token, unicode.IsPunct), punct))
+		Expect(values(tokens)).To(Equal([]interface{}{"a"}))
+		Expect(err).To(MatchError(`unexpected '1'`))
+	})
+})
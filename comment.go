@@ -0,0 +1,99 @@
+package lexer
+
+import "strings"
+
+// TokenComment represents a type of token that contains a comment (see CommentSyntax).
+const TokenComment TokenType = -10
+
+// CommentSyntax is the syntax of the comments of a language.
+type CommentSyntax struct {
+	// Line are the prefixes of line comments (e.g. "//" or "#").
+	Line []string
+
+	// Open and Close delimit block comments (e.g. "/*" and "*/"), if any, which nest if
+	// Nested is true.
+	Open, Close string
+	Nested      bool
+
+	// Ignore skips comments (capturing them as trivia of type TokenComment if the lexer was
+	// created with WithTrivia) instead of emitting them as tokens of type TokenComment.
+	Ignore bool
+}
+
+// ScanLineComment scans a line comment starting with the prefix at the current position of
+// the lexer, up to but not including the line break ending it. The comment is consumed, not
+// emitted. Returns an error, a LexError, if there is no such prefix at the current position.
+func ScanLineComment(l *Lexer, prefix string) error {
+	start := l.CurrentPosition
+	if !strings.HasPrefix(l.Input[start:], prefix) {
+		return l.errorAt(start, "expected %q", prefix)
+	}
+	l.advance(len(prefix))
+	for r := l.Peek(); r != '\n' && r != EOF; r = l.Peek() {
+		if r == '\r' && l.peekAt(1) == '\n' {
+			break
+		}
+		l.Next()
+	}
+	return nil
+}
+
+// ScanBlockComment scans a block comment enclosed in the open and close delimiters at the
+// current position of the lexer, including the block comments nested in it if nested is
+// true (e.g. /* a /* b */ c */). The comment is consumed, not emitted.
+//
+// Returns an error, a LexError, if there is no open delimiter at the current position, or
+// if the comment is unterminated, positioned at its open delimiter, having consumed the rest
+// of the input.
+func ScanBlockComment(l *Lexer, open, close string, nested bool) error {
+	start := l.CurrentPosition
+	if !strings.HasPrefix(l.Input[start:], open) {
+		return l.errorAt(start, "expected %q", open)
+	}
+	l.advance(len(open))
+	for depth := 1; depth > 0; {
+		rest := l.Input[l.CurrentPosition:]
+		switch {
+		case rest == "":
+			return l.errorAt(start, "unterminated comment")
+		case strings.HasPrefix(rest, close):
+			l.advance(len(close))
+			depth--
+		case nested && strings.HasPrefix(rest, open):
+			l.advance(len(open))
+			depth++
+		default:
+			l.Next()
+		}
+	}
+	return nil
+}
+
+// Lex lexes a comment at the current position of the lexer, if any, emitting or skipping it
+// as configured, and reports whether there was one. Returns an error, as ScanBlockComment
+// does, if a block comment is unterminated, having emitted or skipped the rest of the input.
+func (c CommentSyntax) Lex(l *Lexer) (bool, error) {
+	input := l.Input[l.CurrentPosition:]
+	var err error
+	switch {
+	case c.Open != "" && strings.HasPrefix(input, c.Open):
+		err = ScanBlockComment(l, c.Open, c.Close, c.Nested)
+	default:
+		prefix := ""
+		for _, p := range c.Line {
+			if len(p) > len(prefix) && strings.HasPrefix(input, p) {
+				prefix = p
+			}
+		}
+		if prefix == "" {
+			return false, nil
+		}
+		err = ScanLineComment(l, prefix)
+	}
+	if c.Ignore {
+		l.EmitTrivia(TokenComment)
+	} else {
+		l.Emit(TokenComment)
+	}
+	return true, err
+}
@@ -0,0 +1,90 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Comment", func() {
+	const Word lexer.TokenType = iota + 1900
+
+	c := lexer.CommentSyntax{Line: []string{"#", "//"}, Open: "/*", Close: "*/"}
+
+	state := func(c lexer.CommentSyntax) lexer.StateFunc {
+		var state lexer.StateFunc
+		state = func(l *lexer.Lexer) lexer.StateFunc {
+			if ok, err := c.Lex(l); err != nil {
+				return l.ReportError(err)
+			} else if ok {
+				return state
+			}
+			switch r := l.Next(); {
+			case r == lexer.EOF:
+				return nil
+			case r == ' ' || r == '\n':
+				l.EmitTrivia(lexer.TokenTrivia)
+			default:
+				l.Emit(Word)
+			}
+			return state
+		}
+		return state
+	}
+
+	It("should scan line comments up to the line break (i.e. ScanLineComment)", func() {
+		l := lexer.NewLexer("// a\r\nb", nil)
+		Expect(lexer.ScanLineComment(l, "//")).To(Succeed())
+		Expect(l.Input[:l.CurrentPosition]).To(Equal("// a"))
+		l = lexer.NewLexer("# a", nil)
+		Expect(lexer.ScanLineComment(l, "#")).To(Succeed())
+		Expect(l.Input[:l.CurrentPosition]).To(Equal("# a"))
+		l = lexer.NewLexer("a", nil)
+		Expect(lexer.ScanLineComment(l, "#")).To(MatchError(`expected "#"`))
+	})
+
+	It("should scan block comments, optionally nested (i.e. ScanBlockComment)", func() {
+		l := lexer.NewLexer("/* a /* b */ c */", nil)
+		Expect(lexer.ScanBlockComment(l, "/*", "*/", false)).To(Succeed())
+		Expect(l.Input[:l.CurrentPosition]).To(Equal("/* a /* b */"))
+		l = lexer.NewLexer("/* a /* b */ c */ d", nil)
+		Expect(lexer.ScanBlockComment(l, "/*", "*/", true)).To(Succeed())
+		Expect(l.Input[:l.CurrentPosition]).To(Equal("/* a /* b */ c */"))
+		l = lexer.NewLexer("x (* a (* b *)", nil)
+		l.Ignore()
+		l.Ignore()
+		err := lexer.ScanBlockComment(l, "(*", "*)", true)
+		Expect(err).To(MatchError("unterminated comment"))
+		Expect(err.(lexer.LexError).Position).To(Equal(lexer.RunePosition(2)))
+		Expect(l.Peek()).To(Equal(lexer.EOF))
+	})
+
+	It("should emit comments (i.e. CommentSyntax)", func() {
+		tokens, err := lexer.Tokenize("a // b\n/* c */d", state(c))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(Equal([]lexer.Token{
+			{Type: Word, Value: "a", Line: 1, Column: 1},
+			{Type: lexer.TokenComment, Value: "// b", Position: 2, Line: 1, Column: 3},
+			{Type: lexer.TokenComment, Value: "/* c */", Position: 7, Line: 2, Column: 1},
+			{Type: Word, Value: "d", Position: 14, Line: 2, Column: 8},
+		}))
+		l := lexer.NewLexer("a /* b", state(c))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Word, Value: "a"}))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: lexer.TokenComment, Value: "/* b"}))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: lexer.TokenError, Value: "unterminated comment"}))
+	})
+
+	It("should skip comments if configured to (i.e. CommentSyntax)", func() {
+		ignore := c
+		ignore.Ignore = true
+		tokens, err := lexer.Tokenize("a # b\nc", state(ignore))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(2))
+		l := lexer.NewLexer("# b\na", state(ignore), lexer.WithTrivia(lexer.TriviaLeading))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Word, Value: "a", Trivia: []lexer.Token{
+			{Type: lexer.TokenComment, Value: "# b"},
+			{Type: lexer.TokenTrivia, Value: "\n"},
+		}}))
+	})
+})
@@ -0,0 +1,213 @@
+package lexer
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// maxExpansion is the maximum number of strings a pattern is expanded into when checking
+// whether it is shadowed by the patterns of earlier rules.
+const maxExpansion = 1024
+
+// RuleConflict describes a rule of a Rules table that can never be applied.
+type RuleConflict struct {
+	// Rule is the index of the rule in the order the rules were added.
+	Rule int
+
+	// ShadowedBy is the index of an earlier rule matching everything the rule matches, or -1
+	// if the rule matches nothing or is only shadowed by several earlier rules together.
+	ShadowedBy int
+}
+
+func (c RuleConflict) String() string {
+	if c.ShadowedBy < 0 {
+		return fmt.Sprintf("rule %d is unreachable", c.Rule)
+	}
+	return fmt.Sprintf("rule %d is shadowed by rule %d", c.Rule, c.ShadowedBy)
+}
+
+// RuleConflicts is the error returned by Validate for a table with rules that can never be
+// applied.
+type RuleConflicts []RuleConflict
+
+func (c RuleConflicts) Error() string {
+	conflicts := make([]string, len(c))
+	for i, conflict := range c {
+		conflicts[i] = conflict.String()
+	}
+	return "lexer: " + strings.Join(conflicts, ", ")
+}
+
+// Validate returns an error if a pattern is not a valid regular expression, or RuleConflicts
+// if a rule can never be applied, because its pattern matches nothing but the empty string,
+// or everything it matches is also matched by the patterns of earlier rules.
+//
+// A rule is only found to be shadowed if it matches the same input as an earlier rule, or
+// matches a finite set of strings (e.g. a literal). The rules of a mode may override the
+// rules it inherits. State validates the table.
+func (r *Rules) Validate() error {
+	_, err := r.Matcher()
+	return err
+}
+
+func validate(rules []rule) error {
+	full := make([]*regexp.Regexp, len(rules))
+	exprs := make([]string, len(rules))
+	var conflicts RuleConflicts
+	for i, u := range rules {
+		if u.match != nil {
+			continue
+		}
+		full[i] = regexp.MustCompile(`^(?:` + u.pattern.full() + `)$`)
+		re, _ := syntax.Parse(u.pattern.full(), syntax.Perl)
+		re = re.Simplify()
+		exprs[i] = re.String()
+		if conflict, ok := shadowed(i, re, exprs, full); ok {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	if len(conflicts) > 0 {
+		return conflicts
+	}
+	return nil
+}
+
+// shadowed reports whether the i-th rule, with the specified pattern, is shadowed by the
+// earlier rules.
+func shadowed(i int, re *syntax.Regexp, exprs []string, full []*regexp.Regexp) (RuleConflict, bool) {
+	for j := 0; j < i; j++ {
+		if full[j] != nil && exprs[j] == exprs[i] {
+			return RuleConflict{Rule: i, ShadowedBy: j}, true
+		}
+	}
+	strs, ok := expand(re)
+	if !ok {
+		return RuleConflict{}, false
+	}
+	var matches []string
+	for _, s := range strs {
+		if s != "" {
+			matches = append(matches, s)
+		}
+	}
+	if len(matches) == 0 {
+		return RuleConflict{Rule: i, ShadowedBy: -1}, true
+	}
+	for j := 0; j < i; j++ {
+		if full[j] != nil && matchesAll(full[j], matches) {
+			return RuleConflict{Rule: i, ShadowedBy: j}, true
+		}
+	}
+	for _, s := range matches {
+		if !matchedByAny(full[:i], s) {
+			return RuleConflict{}, false
+		}
+	}
+	return RuleConflict{Rule: i, ShadowedBy: -1}, true
+}
+
+func matchesAll(re *regexp.Regexp, strs []string) bool {
+	for _, s := range strs {
+		if !re.MatchString(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchedByAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re != nil && re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// expand returns the strings matched by the regular expression, or false if it matches
+// more than maxExpansion strings or contains assertions.
+func expand(re *syntax.Regexp) ([]string, bool) {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return nil, true
+	case syntax.OpEmptyMatch:
+		return []string{""}, true
+	case syntax.OpLiteral:
+		strs := []string{""}
+		for _, r := range re.Rune {
+			runes := []rune{r}
+			if re.Flags&syntax.FoldCase != 0 {
+				for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+					runes = append(runes, f)
+				}
+			}
+			var ok bool
+			if strs, ok = product(strs, runeStrings(runes)); !ok {
+				return nil, false
+			}
+		}
+		return strs, true
+	case syntax.OpCharClass:
+		var runes []rune
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			for r := re.Rune[i]; r <= re.Rune[i+1]; r++ {
+				if runes = append(runes, r); len(runes) > maxExpansion {
+					return nil, false
+				}
+			}
+		}
+		return runeStrings(runes), true
+	case syntax.OpCapture:
+		return expand(re.Sub[0])
+	case syntax.OpQuest:
+		strs, ok := expand(re.Sub[0])
+		return append([]string{""}, strs...), ok && len(strs) < maxExpansion
+	case syntax.OpConcat:
+		strs := []string{""}
+		for _, sub := range re.Sub {
+			expanded, ok := expand(sub)
+			if !ok {
+				return nil, false
+			}
+			if strs, ok = product(strs, expanded); !ok {
+				return nil, false
+			}
+		}
+		return strs, true
+	case syntax.OpAlternate:
+		var strs []string
+		for _, sub := range re.Sub {
+			expanded, ok := expand(sub)
+			if !ok || len(strs)+len(expanded) > maxExpansion {
+				return nil, false
+			}
+			strs = append(strs, expanded...)
+		}
+		return strs, true
+	}
+	return nil, false
+}
+
+func product(prefixes, suffixes []string) ([]string, bool) {
+	if len(prefixes)*len(suffixes) > maxExpansion {
+		return nil, false
+	}
+	strs := make([]string, 0, len(prefixes)*len(suffixes))
+	for _, p := range prefixes {
+		for _, s := range suffixes {
+			strs = append(strs, p+s)
+		}
+	}
+	return strs, true
+}
+
+func runeStrings(runes []rune) []string {
+	strs := make([]string, len(runes))
+	for i, r := range runes {
+		strs[i] = string(r)
+	}
+	return strs
+}
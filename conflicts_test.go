@@ -0,0 +1,54 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Conflicts", func() {
+	const (
+		Identifier lexer.TokenType = iota
+		Keyword
+		Operator
+	)
+
+	It("should accept rules that can all be applied (i.e. Validate)", func() {
+		err := lexer.NewRules().
+			Add(Keyword, lexer.Regexp("if|else")).
+			Add(Identifier, lexer.Regexp(`[a-z]+`)).
+			Add(Operator, lexer.Literal("=")).
+			Add(Operator, lexer.Literal("==")).
+			Validate()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report rules shadowed by an earlier rule (i.e. Validate)", func() {
+		err := lexer.NewRules().
+			Add(Identifier, lexer.Regexp(`[a-z]+`)).
+			Add(Keyword, lexer.Literal("if")).
+			Add(Operator, lexer.Class(`[-+]`)).
+			Add(Operator, lexer.Regexp(`(?:[-+])+`)).
+			Add(Keyword, lexer.Regexp(`if|[-+]`)).
+			Validate()
+		Expect(err).To(Equal(lexer.RuleConflicts{{Rule: 1, ShadowedBy: 0}, {Rule: 3, ShadowedBy: 2}, {Rule: 4, ShadowedBy: -1}}))
+		Expect(err).To(MatchError("lexer: rule 1 is shadowed by rule 0, rule 3 is shadowed by rule 2, rule 4 is unreachable"))
+	})
+
+	It("should report rules that are unreachable (i.e. Validate)", func() {
+		err := lexer.NewRules().
+			Add(Keyword, lexer.Literal("a")).
+			Add(Keyword, lexer.Literal("b")).
+			Add(Identifier, lexer.Regexp("a|b")).
+			Add(Operator, lexer.Literal("")).
+			Validate()
+		Expect(err).To(Equal(lexer.RuleConflicts{{Rule: 2, ShadowedBy: -1}, {Rule: 3, ShadowedBy: -1}}))
+	})
+
+	It("should fail to construct a state from conflicting rules (i.e. State)", func() {
+		state, err := lexer.NewRules().Add(Keyword, lexer.Literal("if")).Add(Keyword, lexer.Literal("if")).State()
+		Expect(state).To(BeNil())
+		Expect(err).To(MatchError("lexer: rule 1 is shadowed by rule 0"))
+	})
+})
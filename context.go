@@ -0,0 +1,80 @@
+package lexer
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrClosed is returned when reading tokens from a lexer that has been closed.
+var ErrClosed = errors.New("lexer: closed")
+
+// WithContext closes the lexer once the context is done, unless the state machine has
+// finished, or the lexer has been closed, by then.
+func WithContext(ctx context.Context) Option {
+	return func(l *Lexer) {
+		l.stopContext = context.AfterFunc(ctx, l.Close)
+	}
+}
+
+// releaseContext stops the context of WithContext from closing the lexer, so that neither
+// is kept alive by the other.
+func (l *Lexer) releaseContext() {
+	if l.stopContext != nil {
+		l.stopContext()
+	}
+}
+
+// NextTokenContext returns the next token emitted by the lexer, or the context's error if
+// the context is done first, in which case the lexer is closed.
+//
+// Returns io.EOF once the state machine has finished and every token has been returned, and
+// ErrClosed if the lexer has been closed.
+func (l *Lexer) NextTokenContext(ctx context.Context) (Token, error) {
+	if err := ctx.Err(); err != nil {
+		l.Close()
+		return Token{}, err
+	}
+	if l.closed() {
+		return Token{}, ErrClosed
+	}
+	if t, ok := l.popLookahead(); ok {
+		l.track(t)
+		return t, nil
+	}
+	if l.synchronous {
+		for len(l.queue) == 0 && ctx.Err() == nil && l.step() {
+		}
+		if err := ctx.Err(); err != nil {
+			l.Close()
+			return Token{}, err
+		}
+		t, ok := l.fetchSynchronously()
+		if ok {
+			l.track(t)
+		}
+		return l.tokenOrEOF(t, ok)
+	}
+	select {
+	case t, ok := <-l.tokens:
+		if ok {
+			l.track(t)
+		}
+		return l.tokenOrEOF(t, ok)
+	case <-l.done:
+		return Token{}, ErrClosed
+	case <-ctx.Done():
+		l.Close()
+		return Token{}, ctx.Err()
+	}
+}
+
+func (l *Lexer) tokenOrEOF(t Token, ok bool) (Token, error) {
+	if !ok {
+		if l.closed() {
+			return t, ErrClosed
+		}
+		return t, io.EOF
+	}
+	return t, nil
+}
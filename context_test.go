@@ -0,0 +1,93 @@
+package lexer_test
+
+import (
+	"context"
+	"io"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Context", func() {
+	It("should return the next token (i.e. NextTokenContext)", func() {
+		l := lexer.NewLexer("E", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Emit(Token)
+			return nil
+		})
+		t, err := l.NextTokenContext(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(t).To(EqualToken(lexer.Token{Type: Token, Value: "E"}))
+		_, err = l.NextTokenContext(context.Background())
+		Expect(err).To(Equal(io.EOF))
+		l.Close()
+		_, err = l.NextTokenContext(context.Background())
+		Expect(err).To(Equal(lexer.ErrClosed))
+	})
+
+	It("should close the lexer once the context is done", func(done Done) {
+		ctx, cancel := context.WithCancel(context.Background())
+		l := lexer.NewLexer("", func(l *lexer.Lexer) lexer.StateFunc {
+			for {
+				l.Emit(Token)
+			}
+		})
+		_, err := l.NextTokenContext(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		cancel()
+		_, err = l.NextTokenContext(ctx)
+		Expect(err).To(Equal(context.Canceled))
+		_, err = l.NextTokenContext(context.Background())
+		Expect(err).To(Equal(lexer.ErrClosed))
+		close(done)
+	})
+
+	It("should stop a synchronous lexer once the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		steps := 0
+		var state lexer.StateFunc
+		state = func(l *lexer.Lexer) lexer.StateFunc {
+			if steps++; steps == 10 {
+				cancel()
+			}
+			return state
+		}
+		l := lexer.NewLexer("", state, lexer.WithSynchronous())
+		_, err := l.NextTokenContext(ctx)
+		Expect(err).To(Equal(context.Canceled))
+		Expect(steps).To(Equal(10))
+	})
+
+	It("should stop the goroutine running the state machine (i.e. WithContext)", func(done Done) {
+		ctx, cancel := context.WithCancel(context.Background())
+		exited := make(chan struct{})
+		l := lexer.NewLexer("", func(l *lexer.Lexer) lexer.StateFunc {
+			defer close(exited)
+			for {
+				l.Emit(Token)
+			}
+		}, lexer.WithContext(ctx))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: ""}))
+		cancel()
+		Eventually(exited).Should(BeClosed())
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{}))
+		close(done)
+	})
+	It("should not close a finished lexer once the context is done (i.e. WithContext)", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		l := lexer.NewLexer("E", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Emit(Token)
+			return nil
+		}, lexer.WithContext(ctx))
+		for range l.All() {
+		}
+		cancel()
+		Consistently(func() error {
+			_, err := l.NextTokenContext(context.Background())
+			return err
+		}, "50ms").Should(Equal(io.EOF))
+	})
+})
@@ -0,0 +1,105 @@
+package lexer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Coverage records which named states (see Named) and rules (see NamedRule) executed
+// while lexing, so that test suites can verify every lexical path of a language is
+// exercised.
+//
+// A coverage may be shared by any number of lexers, including concurrently running ones.
+type Coverage struct {
+	mutex sync.Mutex
+	hits  map[string]int
+}
+
+// NewCoverage creates a coverage of the states and rules with the specified names.
+func NewCoverage(names ...string) *Coverage {
+	c := &Coverage{hits: make(map[string]int)}
+	for _, name := range names {
+		c.hits[name] = 0
+	}
+	return c
+}
+
+// WithCoverage records the named states and rules executed by the lexer in the coverage.
+func WithCoverage(c *Coverage) Option {
+	return func(l *Lexer) {
+		l.coverage = c
+	}
+}
+
+// NamedRule returns a rule that applies the specified rule under a name, recording in the
+// lexer's coverage each time it matches.
+func NamedRule(name string, m Matcher) Matcher {
+	return func(l *Lexer) bool {
+		if !m(l) {
+			return false
+		}
+		l.coverage.hit(name)
+		return true
+	}
+}
+
+// Hits returns the number of times the named state or rule executed.
+func (c *Coverage) Hits(name string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.hits[name]
+}
+
+// Missing returns the names, in order, of the states and rules the coverage was created
+// with that never executed.
+func (c *Coverage) Missing() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var missing []string
+	for name, hits := range c.hits {
+		if hits == 0 {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// String returns a report of the number of times each state and rule executed, in order of
+// name, followed by the percentage of them that executed at least once.
+func (c *Coverage) String() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	names := make([]string, 0, len(c.hits))
+	width := 0
+	for name := range c.hits {
+		names = append(names, name)
+		width = max(width, len(name))
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	covered := 0
+	for _, name := range names {
+		if c.hits[name] > 0 {
+			covered++
+		}
+		fmt.Fprintf(&b, "%-*s  %d\n", width, name, c.hits[name])
+	}
+	percent := 100.0
+	if len(names) > 0 {
+		percent = 100 * float64(covered) / float64(len(names))
+	}
+	fmt.Fprintf(&b, "coverage: %.1f%% of states and rules\n", percent)
+	return b.String()
+}
+
+func (c *Coverage) hit(name string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.hits[name]++
+}
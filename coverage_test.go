@@ -0,0 +1,66 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Coverage", func() {
+	const (
+		Word lexer.TokenType = iota
+		Number
+	)
+
+	rule := func(tokenType lexer.TokenType, predicate lexer.RunePredicate) lexer.Matcher {
+		return func(l *lexer.Lexer) bool {
+			if !predicate(l.Peek()) {
+				return false
+			}
+			l.NextUpTo(func(r rune) bool {
+				return !predicate(r)
+			})
+			l.Emit(tokenType)
+			return true
+		}
+	}
+	space := func(l *lexer.Lexer) bool {
+		if !unicode.IsSpace(l.Peek()) {
+			return false
+		}
+		l.Ignore()
+		return true
+	}
+	modes := lexer.NewModes(
+		space,
+		lexer.NamedRule("word", rule(Word, unicode.IsLetter)),
+		lexer.NamedRule("number", rule(Number, unicode.IsDigit)),
+	)
+	state := lexer.Named("initial", modes.State())
+
+	It("should record the named states and rules executed (i.e. WithCoverage and Hits)", func() {
+		c := lexer.NewCoverage("initial", "word", "number")
+		_, err := lexer.Tokenize("a b", state, lexer.WithCoverage(c))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = lexer.Tokenize("c", state, lexer.WithCoverage(c))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Hits("word")).To(Equal(3))
+		Expect(c.Hits("initial")).To(Equal(2))
+		Expect(c.Missing()).To(Equal([]string{"number"}))
+	})
+
+	It("should report the coverage of the states and rules (i.e. Coverage.String)", func() {
+		c := lexer.NewCoverage("word", "number", "string")
+		_, err := lexer.Tokenize("1", state, lexer.WithCoverage(c))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.String()).To(Equal(`initial  1
+number   1
+string   0
+word     0
+coverage: 50.0% of states and rules
+`))
+	})
+})
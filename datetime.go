@@ -0,0 +1,128 @@
+package lexer
+
+import (
+	"regexp"
+	"strconv"
+)
+
+const (
+	// TokenDate represents a type of token that contains an ISO 8601 date (see
+	// ScanDateTime).
+	TokenDate TokenType = -14
+
+	// TokenTime represents a type of token that contains an ISO 8601 time of day (see
+	// ScanDateTime).
+	TokenTime TokenType = -15
+
+	// TokenDateTime represents a type of token that contains an ISO 8601 date and time (see
+	// ScanDateTime).
+	TokenDateTime TokenType = -16
+
+	// TokenDuration represents a type of token that contains an ISO 8601 duration (see
+	// ScanDateTime).
+	TokenDuration TokenType = -17
+)
+
+var (
+	dateRe     = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})`)
+	timeRe     = regexp.MustCompile(`^(\d{2}):(\d{2})(?::(\d{2})(?:[.,]\d+)?)?(?:Z|[+-](\d{2})(?::?(\d{2}))?)?`)
+	durationRe = regexp.MustCompile(`^P(?:\d+(?:[.,]\d+)?Y)?(?:\d+(?:[.,]\d+)?M)?(?:\d+(?:[.,]\d+)?W)?(?:\d+(?:[.,]\d+)?D)?(?:T(?:\d+(?:[.,]\d+)?H)?(?:\d+(?:[.,]\d+)?M)?(?:\d+(?:[.,]\d+)?S)?)?`)
+)
+
+// ScanDateTime scans an ISO 8601 date (e.g. 2024-02-29), time of day (e.g. 13:45,
+// 13:45:30.5Z, or 13:45:30+01:00), date and time (e.g. 2024-02-29T13:45:30Z), or duration
+// (e.g. P1Y2M3DT4H5M6S or PT0.5S) in the extended format, at the current position of the
+// lexer, returning the type of the literal, TokenDate, TokenTime, TokenDateTime, or
+// TokenDuration. The literal is consumed, not emitted.
+//
+// Returns an error, a LexError positioned at the offending field, if a field of the literal
+// is out of range (e.g. 2023-02-29 or 25:00), having consumed it, or if there is no date,
+// time, or duration at the current position, having consumed nothing.
+func ScanDateTime(l *Lexer) (TokenType, error) {
+	start := l.CurrentPosition
+	input := l.Input[start:]
+	d := &dateTimeScanner{l: l, start: start}
+	if loc := dateRe.FindStringSubmatchIndex(input); loc != nil {
+		d.checkDate(input, loc)
+		n, t := loc[1], TokenDate
+		if n < len(input) && (input[n] == 'T' || input[n] == 't') {
+			if tloc := timeRe.FindStringSubmatchIndex(input[n+1:]); tloc != nil {
+				for i := range tloc {
+					if tloc[i] >= 0 {
+						tloc[i] += n + 1
+					}
+				}
+				d.checkTime(input, tloc)
+				n, t = tloc[1], TokenDateTime
+			}
+		}
+		l.advance(n)
+		return t, d.err
+	}
+	if loc := timeRe.FindStringSubmatchIndex(input); loc != nil {
+		d.checkTime(input, loc)
+		l.advance(loc[1])
+		return TokenTime, d.err
+	}
+	if loc := durationRe.FindStringIndex(input); loc != nil && loc[1] > 1 && input[loc[1]-1] != 'T' {
+		l.advance(loc[1])
+		return TokenDuration, nil
+	}
+	return TokenDate, l.errorAt(start, "expected a date, time, or duration")
+}
+
+// dateTimeScanner validates the fields of a date or time, recording the first error.
+type dateTimeScanner struct {
+	l     *Lexer
+	start RunePosition
+	err   error
+}
+
+func (d *dateTimeScanner) checkDate(input string, loc []int) {
+	year := d.field(input, loc, 1)
+	month := d.field(input, loc, 2)
+	day := d.field(input, loc, 3)
+	days := []int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+	if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+		days[1] = 29
+	}
+	switch {
+	case month < 1 || month > 12:
+		d.fail(loc, 2, "month out of range")
+	case day < 1 || day > days[month-1]:
+		d.fail(loc, 3, "day out of range")
+	}
+}
+
+func (d *dateTimeScanner) checkTime(input string, loc []int) {
+	hour := d.field(input, loc, 1)
+	minute := d.field(input, loc, 2)
+	second := d.field(input, loc, 3)
+	switch {
+	case hour > 24 || hour == 24 && (minute > 0 || second > 0):
+		d.fail(loc, 1, "hour out of range")
+	case minute > 59:
+		d.fail(loc, 2, "minute out of range")
+	case second > 60:
+		d.fail(loc, 3, "second out of range")
+	case d.field(input, loc, 4) > 23:
+		d.fail(loc, 4, "time zone offset hour out of range")
+	case d.field(input, loc, 5) > 59:
+		d.fail(loc, 5, "time zone offset minute out of range")
+	}
+}
+
+// field returns the value of the submatch of the time or date, or 0 if it is absent.
+func (d *dateTimeScanner) field(input string, loc []int, i int) int {
+	if loc[2*i] < 0 {
+		return 0
+	}
+	v, _ := strconv.Atoi(input[loc[2*i]:loc[2*i+1]])
+	return v
+}
+
+func (d *dateTimeScanner) fail(loc []int, i int, message string) {
+	if d.err == nil {
+		d.err = d.l.errorAt(d.start+RunePosition(loc[2*i]), "%s", message)
+	}
+}
@@ -0,0 +1,64 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DateTime", func() {
+	It("should scan dates, times, and durations (i.e. ScanDateTime)", func() {
+		for input, tokenType := range map[string]lexer.TokenType{
+			"2024-02-29":                lexer.TokenDate,
+			"13:45":                     lexer.TokenTime,
+			"13:45:30.5Z":               lexer.TokenTime,
+			"24:00":                     lexer.TokenTime,
+			"13:45:30+0100":             lexer.TokenTime,
+			"2024-02-29T13:45:30Z":      lexer.TokenDateTime,
+			"2024-02-29t13:45:30,25-05": lexer.TokenDateTime,
+			"P1Y2M3DT4H5M6S":            lexer.TokenDuration,
+			"PT0.5S":                    lexer.TokenDuration,
+			"P2W":                       lexer.TokenDuration,
+		} {
+			l := lexer.NewLexer(input+" x", nil)
+			t, err := lexer.ScanDateTime(l)
+			Expect(err).NotTo(HaveOccurred(), input)
+			Expect(t).To(Equal(tokenType), input)
+			Expect(l.Input[:l.CurrentPosition]).To(Equal(input))
+		}
+		l := lexer.NewLexer("2024-02-29T", nil)
+		Expect(lexer.ScanDateTime(l)).To(Equal(lexer.TokenDate))
+		Expect(l.Input[l.CurrentPosition:]).To(Equal("T"))
+	})
+
+	It("should report fields out of range (i.e. ScanDateTime)", func() {
+		for input, message := range map[string]string{
+			"2023-02-29":  "day out of range",
+			"2024-13-01":  "month out of range",
+			"25:00":       "hour out of range",
+			"24:01":       "hour out of range",
+			"12:60":       "minute out of range",
+			"12:00:61":    "second out of range",
+			"12:00+24:00": "time zone offset hour out of range",
+			"12:00-01:60": "time zone offset minute out of range",
+		} {
+			l := lexer.NewLexer(input, nil)
+			_, err := lexer.ScanDateTime(l)
+			Expect(err).To(MatchError(message), input)
+			Expect(l.CurrentPosition).To(Equal(lexer.RunePosition(len(input))))
+		}
+		l := lexer.NewLexer("2024-02-30T12:00", nil)
+		_, err := lexer.ScanDateTime(l)
+		Expect(err.(lexer.LexError).Position).To(Equal(lexer.RunePosition(8)))
+	})
+
+	It("should consume nothing if there is no literal (i.e. ScanDateTime)", func() {
+		for _, input := range []string{"2024", "2024-1-1", "1:00", "P", "PT", "P1DT", "Px", "x"} {
+			l := lexer.NewLexer(input, nil)
+			_, err := lexer.ScanDateTime(l)
+			Expect(err).To(MatchError("expected a date, time, or duration"), input)
+			Expect(l.CurrentPosition).To(BeZero())
+		}
+	})
+})
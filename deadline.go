@@ -0,0 +1,28 @@
+package lexer
+
+import (
+	"errors"
+	"time"
+)
+
+var errHalt = errors.New("lexer: halted")
+
+// WithDeadline halts the lexer once the specified duration has elapsed since it was
+// created, emitting an error token with the message "lexing deadline exceeded".
+//
+// The deadline is checked whenever the lexer transitions to another state or emits a token;
+// a state that does neither cannot be interrupted.
+func WithDeadline(d time.Duration) Option {
+	return func(l *Lexer) {
+		l.deadline = time.Now().Add(d)
+	}
+}
+
+func (l *Lexer) checkDeadline() {
+	if l.deadline.IsZero() || time.Now().Before(l.deadline) {
+		return
+	}
+	l.deadline = time.Time{}
+	l.Errorf("lexing deadline exceeded")
+	l.halt()
+}
@@ -0,0 +1,46 @@
+package lexer_test
+
+import (
+	"time"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deadline", func() {
+	It("should halt a lexer that keeps emitting tokens (i.e. WithDeadline)", func(done Done) {
+		l := lexer.NewLexer("", func(l *lexer.Lexer) lexer.StateFunc {
+			for {
+				l.Emit(Token)
+			}
+		}, lexer.WithDeadline(10*time.Millisecond))
+		var last lexer.Token
+		for t := range l.Tokens() {
+			last = t
+		}
+		Expect(last).To(EqualToken(lexer.Token{Type: lexer.TokenError, Value: "lexing deadline exceeded"}))
+		close(done)
+	})
+
+	It("should halt a lexer that keeps transitioning between states", func() {
+		var state lexer.StateFunc
+		state = func(l *lexer.Lexer) lexer.StateFunc {
+			return state
+		}
+		l := lexer.NewLexer("", state, lexer.WithDeadline(10*time.Millisecond), lexer.WithSynchronous())
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: lexer.TokenError, Value: "lexing deadline exceeded"}))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{}))
+	})
+
+	It("should not interfere with lexers that finish in time", func() {
+		tokens, err := lexer.Tokenize("E", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Emit(Token)
+			return nil
+		}, lexer.WithDeadline(time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,78 @@
+package lexer
+
+import "fmt"
+
+type delimiter struct {
+	r        rune
+	position RunePosition
+}
+
+// WithDelimiters sets the pairs of opening and closing delimiters tracked by OpenDelim and
+// CloseDelim, e.g. "()[]{}<>"; by default parentheses, brackets, and braces are tracked.
+func WithDelimiters(pairs string) Option {
+	return func(l *Lexer) {
+		l.delimiterPairs = make(map[rune]rune)
+		runes := []rune(pairs)
+		for i := 0; i+1 < len(runes); i += 2 {
+			l.delimiterPairs[runes[i]] = runes[i+1]
+		}
+	}
+}
+
+// OpenDelim records the opening delimiter at the start of the current lexeme, which should
+// be called before it is emitted. Each delimiter left open when the lexer stops is reported
+// with an error token.
+func (l *Lexer) OpenDelim(r rune) {
+	l.delimiters = append(l.delimiters, delimiter{r, l.startPosition})
+}
+
+// CloseDelim closes the most recently opened delimiter with the closing delimiter at the
+// start of the current lexeme, which should be called before it is emitted.
+//
+// Emits an error token, and returns false, if no delimiter is open or the closing delimiter
+// does not match the one most recently opened; a mismatched delimiter still closes it.
+func (l *Lexer) CloseDelim(r rune) bool {
+	n := len(l.delimiters)
+	if n == 0 {
+		l.report(l.lexError(SeverityError, "", "unexpected %q", r))
+		return false
+	}
+	open := l.delimiters[n-1]
+	l.delimiters = l.delimiters[:n-1]
+	if expected := l.closingDelim(open.r); r != expected {
+		l.report(l.lexError(SeverityError, "", "mismatched %q, expected %q", r, expected))
+		return false
+	}
+	return true
+}
+
+// DelimDepth returns the number of delimiters currently open, e.g. to suppress newline
+// tokens within parentheses.
+func (l *Lexer) DelimDepth() int {
+	return len(l.delimiters)
+}
+
+func (l *Lexer) closingDelim(r rune) rune {
+	pairs := l.delimiterPairs
+	if pairs == nil {
+		pairs = defaultDelimiters
+	}
+	if closing, ok := pairs[r]; ok {
+		return closing
+	}
+	return r
+}
+
+var defaultDelimiters = map[rune]rune{'(': ')', '[': ']', '{': '}'}
+
+// reportUnclosedDelims emits an error token for each delimiter left open, outermost first.
+func (l *Lexer) reportUnclosedDelims() {
+	for _, d := range l.delimiters {
+		l.reportLocked(LexError{
+			Message:  fmt.Sprintf("unclosed %q", d.r),
+			Position: d.position + l.origin.Position,
+			Severity: SeverityError,
+		}, true)
+	}
+	l.delimiters = nil
+}
@@ -0,0 +1,85 @@
+package lexer_test
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Delim", func() {
+	const Newline lexer.TokenType = 1
+
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case r == '\n' && l.DelimDepth() == 0:
+				l.Next()
+				l.Emit(Newline)
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case strings.ContainsRune("([{<", r):
+				l.OpenDelim(r)
+				l.Next()
+				l.Emit(Token)
+			case strings.ContainsRune(")]}>", r):
+				l.CloseDelim(r)
+				l.Next()
+				l.Emit(Token)
+			default:
+				l.Next()
+				l.NextUpTo(func(r rune) bool {
+					return !unicode.IsLetter(r)
+				})
+				l.Emit(Token)
+			}
+		}
+	}
+
+	values := func(tokens []lexer.Token) []interface{} {
+		var values []interface{}
+		for _, t := range tokens {
+			values = append(values, t.Value)
+		}
+		return values
+	}
+
+	It("should expose the depth of open delimiters (i.e. OpenDelim, CloseDelim, and DelimDepth)", func() {
+		tokens, err := lexer.Tokenize("f(a,\nb)\ng[{c}]\n", state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values(tokens)).To(Equal([]interface{}{"f", "(", "a", ",", "b", ")", "\n", "g", "[", "{", "c", "}", "]", "\n"}))
+	})
+
+	It("should report unexpected and mismatched closing delimiters (i.e. CloseDelim)", func() {
+		l := lexer.NewLexer(")(]", state)
+		var tokens []lexer.Token
+		for t := range l.All() {
+			tokens = append(tokens, t)
+		}
+		Expect(values(tokens)).To(Equal([]interface{}{"unexpected ')'", ")", "(", "mismatched ']', expected ')'", "]"}))
+		Expect(l.Diagnostics()[1].Position).To(Equal(lexer.RunePosition(2)))
+	})
+
+	It("should report unclosed delimiters at the end of the input (i.e. OpenDelim)", func() {
+		l := lexer.NewLexer("(a [b", state)
+		var tokens []lexer.Token
+		for t := range l.All() {
+			tokens = append(tokens, t)
+		}
+		Expect(values(tokens[4:])).To(Equal([]interface{}{"unclosed '('", "unclosed '['"}))
+		Expect(l.Diagnostics()[0].Position).To(Equal(lexer.RunePosition(0)))
+		Expect(l.Diagnostics()[1].Position).To(Equal(lexer.RunePosition(3)))
+	})
+
+	It("should track custom delimiter pairs (i.e. WithDelimiters)", func() {
+		_, err := lexer.Tokenize("<a>(b)", state, lexer.WithDelimiters("<>()"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = lexer.Tokenize("<a>", state)
+		Expect(err).To(MatchError("mismatched '>', expected '<'"))
+	})
+})
@@ -0,0 +1,273 @@
+package lexer
+
+import (
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxDFAStates is the maximum number of states of the DFA compiled from a rule table; larger
+// tables are interpreted instead.
+const maxDFAStates = 4096
+
+// dfa is a deterministic finite automaton matching the patterns of a rule table by longest
+// match, then declaration order.
+type dfa struct {
+	states []dfaState
+}
+
+type dfaState struct {
+	ascii  [utf8.RuneSelf]int32
+	ranges []dfaRange
+	accept int
+}
+
+// dfaRange is a transition on the non-ASCII runes from lo to hi.
+type dfaRange struct {
+	lo, hi rune
+	next   int32
+}
+
+// nfaItem is an instruction of the program compiled from the pattern of a rule.
+type nfaItem struct {
+	rule int
+	pc   uint32
+}
+
+type dfaCompiler struct {
+	progs  []*syntax.Prog
+	dfa    *dfa
+	index  map[string]int32
+	items  [][]nfaItem
+	failed bool
+}
+
+// compileDFA compiles the patterns of the rules into a DFA, returning false if a pattern
+// contains an assertion (e.g. ^ or \b) or the DFA would be too large.
+func compileDFA(rules []rule) (*dfa, bool) {
+	c := &dfaCompiler{progs: make([]*syntax.Prog, len(rules)), dfa: &dfa{}, index: map[string]int32{}}
+	var start []nfaItem
+	for i, u := range rules {
+		if u.match != nil {
+			continue
+		}
+		re, err := syntax.Parse(u.pattern.full(), syntax.Perl)
+		if err != nil {
+			return nil, false
+		}
+		prog, err := syntax.Compile(re.Simplify())
+		if err != nil {
+			return nil, false
+		}
+		for _, inst := range prog.Inst {
+			if inst.Op == syntax.InstEmptyWidth {
+				return nil, false
+			}
+		}
+		c.progs[i] = prog
+		start = append(start, nfaItem{i, uint32(prog.Start)})
+	}
+	if c.state(start) < 0 {
+		return nil, false
+	}
+	for s := 0; s < len(c.items) && !c.failed; s++ {
+		c.transitions(int32(s))
+	}
+	if c.failed {
+		return nil, false
+	}
+	return c.dfa, true
+}
+
+// state returns the DFA state for the closure of the items, adding it if necessary.
+func (c *dfaCompiler) state(items []nfaItem) int32 {
+	closure, accept := c.closure(items)
+	if len(closure) == 0 && accept < 0 {
+		return -1
+	}
+	var key strings.Builder
+	key.WriteString(strconv.Itoa(accept) + ";")
+	for _, item := range closure {
+		key.WriteString(strconv.Itoa(item.rule) + ":" + strconv.Itoa(int(item.pc)) + ",")
+	}
+	if s, ok := c.index[key.String()]; ok {
+		return s
+	}
+	if len(c.dfa.states) == maxDFAStates {
+		c.failed = true
+		return -1
+	}
+	s := int32(len(c.dfa.states))
+	c.index[key.String()] = s
+	c.items = append(c.items, closure)
+	state := dfaState{accept: accept}
+	for i := range state.ascii {
+		state.ascii[i] = -1
+	}
+	c.dfa.states = append(c.dfa.states, state)
+	return s
+}
+
+// closure returns the instructions consuming a rune reachable from the items without
+// consuming input, ordered by rule, and the first rule matching without consuming input,
+// or -1 if none does.
+func (c *dfaCompiler) closure(items []nfaItem) ([]nfaItem, int) {
+	visited := map[nfaItem]bool{}
+	var closure []nfaItem
+	accept := -1
+	stack := append([]nfaItem(nil), items...)
+	for len(stack) > 0 {
+		item := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[item] {
+			continue
+		}
+		visited[item] = true
+		inst := c.progs[item.rule].Inst[item.pc]
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			stack = append(stack, nfaItem{item.rule, inst.Out}, nfaItem{item.rule, inst.Arg})
+		case syntax.InstCapture, syntax.InstNop:
+			stack = append(stack, nfaItem{item.rule, inst.Out})
+		case syntax.InstMatch:
+			if accept < 0 || item.rule < accept {
+				accept = item.rule
+			}
+		case syntax.InstRune, syntax.InstRune1, syntax.InstRuneAny, syntax.InstRuneAnyNotNL:
+			closure = append(closure, item)
+		}
+	}
+	sort.Slice(closure, func(i, j int) bool {
+		if closure[i].rule != closure[j].rule {
+			return closure[i].rule < closure[j].rule
+		}
+		return closure[i].pc < closure[j].pc
+	})
+	return closure, accept
+}
+
+// transitions adds the transitions of the DFA state on each rune consumed by its items.
+func (c *dfaCompiler) transitions(s int32) {
+	items := c.items[s]
+	ranges := make([][]rune, len(items))
+	var bounds []rune
+	for i, item := range items {
+		ranges[i] = instRanges(c.progs[item.rule].Inst[item.pc])
+		for j := 0; j < len(ranges[i]); j += 2 {
+			bounds = append(bounds, ranges[i][j], ranges[i][j+1]+1)
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool {
+		return bounds[i] < bounds[j]
+	})
+	for k := 0; k+1 < len(bounds); k++ {
+		lo, hi := bounds[k], bounds[k+1]-1
+		if lo > hi {
+			continue
+		}
+		var next []nfaItem
+		for i, item := range items {
+			if containsRune(ranges[i], lo) {
+				next = append(next, nfaItem{item.rule, c.progs[item.rule].Inst[item.pc].Out})
+			}
+		}
+		if len(next) == 0 {
+			continue
+		}
+		t := c.state(next)
+		if t < 0 {
+			continue
+		}
+		state := &c.dfa.states[s]
+		for r := lo; r <= hi && r < utf8.RuneSelf; r++ {
+			state.ascii[r] = t
+		}
+		if hi < utf8.RuneSelf {
+			continue
+		}
+		if lo < utf8.RuneSelf {
+			lo = utf8.RuneSelf
+		}
+		if n := len(state.ranges); n > 0 && state.ranges[n-1].next == t && state.ranges[n-1].hi == lo-1 {
+			state.ranges[n-1].hi = hi
+		} else {
+			state.ranges = append(state.ranges, dfaRange{lo, hi, t})
+		}
+	}
+}
+
+// instRanges returns the sorted, non-overlapping pairs of runes bounding the ranges of
+// runes consumed by the instruction.
+func instRanges(inst syntax.Inst) []rune {
+	switch inst.Op {
+	case syntax.InstRuneAny:
+		return []rune{0, unicode.MaxRune}
+	case syntax.InstRuneAnyNotNL:
+		return []rune{0, '\n' - 1, '\n' + 1, unicode.MaxRune}
+	case syntax.InstRune1:
+		return []rune{inst.Rune[0], inst.Rune[0]}
+	}
+	if len(inst.Rune) == 1 && syntax.Flags(inst.Arg)&syntax.FoldCase != 0 {
+		r := inst.Rune[0]
+		runes := []rune{r}
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			runes = append(runes, f)
+		}
+		sort.Slice(runes, func(i, j int) bool {
+			return runes[i] < runes[j]
+		})
+		ranges := make([]rune, 0, 2*len(runes))
+		for _, r := range runes {
+			ranges = append(ranges, r, r)
+		}
+		return ranges
+	}
+	if len(inst.Rune) == 1 {
+		return []rune{inst.Rune[0], inst.Rune[0]}
+	}
+	return inst.Rune
+}
+
+func containsRune(ranges []rune, r rune) bool {
+	i := sort.Search(len(ranges)/2, func(i int) bool {
+		return ranges[2*i+1] >= r
+	})
+	return i < len(ranges)/2 && ranges[2*i] <= r
+}
+
+// match returns the index of the rule matching the longest prefix of the input, and the
+// length of the prefix, or a length of 0 if no rule matches.
+func (d *dfa) match(input string) (int, int) {
+	s, rule, length := int32(0), -1, 0
+	for i := 0; i < len(input); {
+		state := &d.states[s]
+		if c := input[i]; c < utf8.RuneSelf {
+			s = state.ascii[c]
+			i++
+		} else {
+			r, w := utf8.DecodeRuneInString(input[i:])
+			s = state.step(r)
+			i += w
+		}
+		if s < 0 {
+			break
+		}
+		if accept := d.states[s].accept; accept >= 0 {
+			rule, length = accept, i
+		}
+	}
+	return rule, length
+}
+
+func (s *dfaState) step(r rune) int32 {
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].hi >= r
+	})
+	if i < len(s.ranges) && s.ranges[i].lo <= r {
+		return s.ranges[i].next
+	}
+	return -1
+}
@@ -0,0 +1,66 @@
+package lexer_test
+
+import (
+	"strings"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DFA", func() {
+	const (
+		Identifier lexer.TokenType = iota
+		Keyword
+		Number
+		Operator
+		Comment
+		Word
+	)
+
+	table := func() *lexer.Rules {
+		return lexer.NewRules().
+			Skip(lexer.Class(`\s`)).
+			Add(Keyword, lexer.Regexp(`(?i)select|from`)).
+			Add(Identifier, lexer.Regexp(`[\p{L}_][\p{L}\d_]*`)).
+			Add(Number, lexer.Regexp(`\d+(\.\d+)?|0x[[:xdigit:]]+`)).
+			Add(Operator, lexer.Regexp(`<|<=|<>|=|\*|,`)).
+			Add(Comment, lexer.Regexp(`--.*`))
+	}
+
+	lex := func(rules *lexer.Rules, input string) ([]lexer.Token, error) {
+		state, err := rules.State()
+		Expect(err).NotTo(HaveOccurred())
+		return lexer.Tokenize(input, state)
+	}
+
+	input := "SELECT naïve, ß_2, 0x1F <= 3.25 FROM 表 -- done\nselect * from t <> 1"
+
+	It("should lex the input in a single pass over it (i.e. State)", func() {
+		tokens, err := lex(table(), input)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(17))
+		Expect(tokens[0]).To(EqualToken(lexer.Token{Type: Keyword, Value: "SELECT"}))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: Identifier, Value: "naïve"}))
+		Expect(tokens[5]).To(EqualToken(lexer.Token{Type: Number, Value: "0x1F"}))
+		Expect(tokens[6]).To(EqualToken(lexer.Token{Type: Operator, Value: "<="}))
+		Expect(tokens[9]).To(Equal(lexer.Token{Type: Identifier, Value: "表", Position: 39, Line: 1, Column: 38}))
+		Expect(tokens[10]).To(EqualToken(lexer.Token{Type: Comment, Value: "-- done"}))
+		Expect(tokens[15]).To(EqualToken(lexer.Token{Type: Operator, Value: "<>"}))
+	})
+
+	It("should lex the input as patterns with assertions do (i.e. State)", func() {
+		interpreted := table().Add(Word, lexer.Regexp(`\bunused\b`))
+		input := strings.Repeat(input+"\n", 10)
+		compiled, err := lex(table(), input)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lex(interpreted, input)).To(Equal(compiled))
+	})
+
+	It("should report input no pattern matches (i.e. State)", func() {
+		tokens, err := lex(table(), "a ?")
+		Expect(tokens).To(HaveLen(1))
+		Expect(err).To(MatchError(`unexpected '?'`))
+	})
+})
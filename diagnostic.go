@@ -0,0 +1,219 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// TokenWarning represents a type of token that contains a warning message as its value.
+const TokenWarning TokenType = -2
+
+// Severity represents the severity of a diagnostic reported by the lexer.
+type Severity int
+
+const (
+	// SeverityError represents a diagnostic that halts the lexer.
+	SeverityError Severity = iota
+
+	// SeverityWarning represents a diagnostic that does not halt the lexer.
+	SeverityWarning
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// MarshalText returns the name of the severity.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText sets the severity from its name.
+func (s *Severity) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "error":
+		*s = SeverityError
+	case "warning":
+		*s = SeverityWarning
+	default:
+		return fmt.Errorf("lexer: unknown severity %q", text)
+	}
+	return nil
+}
+
+// WithErrorFormatter formats the value of every error and warning token reported by the
+// lexer (e.g. by Errorf or ErrorCodef) with the function, so that projects can enforce a
+// house diagnostic format (e.g. JSON or GCC-style) across every lexer they build.
+//
+// The diagnostics themselves remain available from Diagnostics.
+func WithErrorFormatter(format func(LexError) string) Option {
+	return func(l *Lexer) {
+		l.errorFormatter = format
+	}
+}
+
+// LexError represents an error or warning reported by the lexer.
+//
+// The code, when present, is a stable identifier (e.g. E0012) downstream tools can use to
+// suppress, document, or map diagnostics programmatically. The state, when present, is the
+// name of the state the diagnostic was reported in (see Named).
+type LexError struct {
+	Code     string       `json:"code,omitempty"`
+	Message  string       `json:"message"`
+	Position RunePosition `json:"position"`
+	Severity Severity     `json:"severity"`
+	State    string       `json:"state,omitempty"`
+}
+
+// Error returns the diagnostic code, if any, followed by the message and the state, if any.
+func (e LexError) Error() string {
+	s := e.Message
+	if e.Code != "" {
+		s = e.Code + " " + s
+	}
+	if e.State != "" {
+		s += " (in state '" + e.State + "')"
+	}
+	return s
+}
+
+// LexError returns the diagnostic carried by an error or warning token.
+//
+// Tokens emitted by Errorf and Warningf carry their message as a plain string; it is
+// converted into a LexError without a code or position. Returns false if the token is
+// neither an error nor a warning.
+func (t Token) LexError() (LexError, bool) {
+	var severity Severity
+	switch t.Type {
+	case TokenError:
+		severity = SeverityError
+	case TokenWarning:
+		severity = SeverityWarning
+	default:
+		return LexError{}, false
+	}
+	switch v := t.Value.(type) {
+	case LexError:
+		return v, true
+	case string:
+		return LexError{Message: v, Severity: severity}, true
+	}
+	return LexError{Message: fmt.Sprint(t.Value), Severity: severity}, true
+}
+
+// ErrorCodef emits an error token with a LexError, identified by the specified code, as its
+// value.
+//
+// If the lexer was created with WithErrorFormatter the value is the formatted diagnostic
+// instead, as it is for every error and warning token reported by the lexer.
+func (l *Lexer) ErrorCodef(code string, format string, args ...interface{}) StateFunc {
+	l.report(l.lexError(SeverityError, code, format, args...))
+	return nil
+}
+
+// ReportError emits an error token for the error, as Errorf does. The code and position of
+// a LexError, e.g. one returned by ScanNumber, are preserved.
+func (l *Lexer) ReportError(err error) StateFunc {
+	var e LexError
+	if !errors.As(err, &e) {
+		e = l.lexError(SeverityError, "", "%s", err)
+	}
+	l.report(e)
+	return nil
+}
+
+// Warningf emits a warning token with the specified warning message as its value.
+//
+// Unlike Errorf, the lexer is expected to continue in its current state. If the current
+// state is named (see Named) the value is a LexError identifying the state instead.
+func (l *Lexer) Warningf(format string, args ...interface{}) {
+	l.report(l.lexError(SeverityWarning, "", format, args...))
+}
+
+// WarningCodef emits a warning token with a LexError, identified by the specified code, as
+// its value.
+func (l *Lexer) WarningCodef(code string, format string, args ...interface{}) {
+	l.report(l.lexError(SeverityWarning, code, format, args...))
+}
+
+func (l *Lexer) lexError(severity Severity, code string, format string, args ...interface{}) LexError {
+	return LexError{
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+		Position: l.startPosition + l.origin.Position,
+		Severity: severity,
+		State:    l.stateName,
+	}
+}
+
+// report emits an error or warning token for the diagnostic.
+//
+// The token's value is the diagnostic formatted by the lexer's error formatter, if any, or
+// otherwise its message if it has neither a code nor a state.
+func (l *Lexer) report(e LexError) {
+	l.checkDeadline()
+	l.emitMutex.Lock()
+	defer l.emitMutex.Unlock()
+	l.reportLocked(e, e.Code == "" && e.State == "")
+}
+
+func (l *Lexer) reportLocked(e LexError, plain bool) {
+	t := Token{Type: TokenError, Value: e}
+	if e.Severity == SeverityWarning {
+		t.Type = TokenWarning
+	}
+	switch {
+	case l.errorFormatter != nil:
+		t.Value = l.errorFormatter(e)
+	case plain:
+		t.Value = e.Message
+	}
+	l.reported = &e
+	l.sendLocked(t)
+}
+
+// Diagnostics returns the diagnostics reported by, or carried by, every error and warning
+// token emitted by the lexer so far, ordered by position (see Token.LexError); diagnostics
+// without a position of their own are given the position of their token.
+//
+// The report is complete once the state machine has finished.
+func (l *Lexer) Diagnostics() []LexError {
+	l.diagnosticMutex.Lock()
+	diagnostics := append([]LexError(nil), l.diagnostics...)
+	l.diagnosticMutex.Unlock()
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Position < diagnostics[j].Position
+	})
+	return diagnostics
+}
+
+func (l *Lexer) collectDiagnostic(t Token) {
+	var e LexError
+	if l.reported != nil {
+		e, l.reported = *l.reported, nil
+	} else {
+		var ok bool
+		if e, ok = t.LexError(); !ok {
+			return
+		}
+		if _, coded := t.Value.(LexError); !coded {
+			e.Position = t.Position
+		}
+	}
+	l.diagnosticMutex.Lock()
+	l.diagnostics = append(l.diagnostics, e)
+	l.diagnosticMutex.Unlock()
+	if t.Type == TokenError {
+		for _, hook := range l.errorHooks() {
+			hook(e)
+		}
+	}
+}
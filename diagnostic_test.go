@@ -0,0 +1,113 @@
+package lexer_test
+
+import (
+	"fmt"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diagnostics", func() {
+	It("should emit an error token with a coded diagnostic as its value (i.e. ErrorCodef)", func() {
+		l := lexer.NewLexer(`x = "abc`, func(l *lexer.Lexer) lexer.StateFunc {
+			l.IgnoreUpTo(func(r rune) bool {
+				return r == '"'
+			})
+			return l.ErrorCodef("E0012", "unterminated string")
+		})
+		t := l.NextToken()
+		Expect(t.Type).To(Equal(lexer.TokenError))
+		Expect(t.Value).To(Equal(lexer.LexError{
+			Code:     "E0012",
+			Message:  "unterminated string",
+			Position: 4,
+			Severity: lexer.SeverityError,
+		}))
+		Expect(t.Value.(error).Error()).To(Equal("E0012 unterminated string"))
+	})
+
+	It("should emit warning tokens without halting the lexer (i.e. Warningf and WarningCodef)", func() {
+		l := lexer.NewLexer("a", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Warningf("deprecated %s", "syntax")
+			l.WarningCodef("W0001", "shadowed identifier")
+			l.Next()
+			l.Emit(Token)
+			return nil
+		})
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: lexer.TokenWarning, Value: "deprecated syntax"}))
+		t := l.NextToken()
+		Expect(t.Type).To(Equal(lexer.TokenWarning))
+		Expect(t.Value.(lexer.LexError).Code).To(Equal("W0001"))
+		Expect(t.Value.(lexer.LexError).Severity).To(Equal(lexer.SeverityWarning))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "a"}))
+	})
+
+	It("should return the diagnostic carried by an error or warning token (i.e. Token.LexError)", func() {
+		e, ok := lexer.Token{Type: lexer.TokenError, Value: "Unexpected input"}.LexError()
+		Expect(ok).To(BeTrue())
+		Expect(e).To(Equal(lexer.LexError{Message: "Unexpected input", Severity: lexer.SeverityError}))
+		e, ok = lexer.Token{Type: lexer.TokenWarning, Value: lexer.LexError{Code: "W1", Message: "m"}}.LexError()
+		Expect(ok).To(BeTrue())
+		Expect(e.Error()).To(Equal("W1 m"))
+		_, ok = lexer.Token{Type: Token, Value: "E"}.LexError()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should report every diagnostic ordered by position once lexing completes (i.e. Diagnostics)", func() {
+		l := lexer.NewLexer("ab\ncd", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Next()
+			l.EmitAll(lexer.Token{Type: lexer.TokenWarning, Value: "late", Position: 4, Line: 2, Column: 2})
+			l.Next()
+			l.Warningf("line break")
+			return l.ErrorCodef("E1", "unexpected %q", 'c')
+		})
+		for range l.All() {
+		}
+		Expect(l.Diagnostics()).To(Equal([]lexer.LexError{
+			{Message: "line break", Position: 2, Severity: lexer.SeverityWarning},
+			{Code: "E1", Message: "unexpected 'c'", Position: 2, Severity: lexer.SeverityError},
+			{Message: "late", Position: 4, Severity: lexer.SeverityWarning},
+		}))
+	})
+
+	It("should report diagnostics while the lexer waits for its tokens to be read (i.e. Diagnostics)", func() {
+		l := lexer.NewLexer("abc", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Warningf("early")
+			for l.Next() != lexer.EOF {
+				l.Emit(Token)
+			}
+			return nil
+		})
+		defer l.Close()
+		Expect(l.NextToken().Type).To(Equal(lexer.TokenWarning))
+		reported := make(chan []lexer.LexError)
+		go func() {
+			reported <- l.Diagnostics()
+		}()
+		Eventually(reported).Should(Receive(Equal([]lexer.LexError{
+			{Message: "early", Position: 0, Severity: lexer.SeverityWarning},
+		})))
+	})
+
+	It("should format the value of every diagnostic token (i.e. WithErrorFormatter)", func() {
+		gcc := func(e lexer.LexError) string {
+			return fmt.Sprintf("%d: %s: %s", e.Position, e.Severity, e.Error())
+		}
+		l := lexer.NewLexer("ab", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Warningf("odd %q", 'a')
+			l.Emit(Token)
+			return l.ErrorCodef("E2", "unexpected %q", 'b')
+		}, lexer.WithErrorFormatter(gcc))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: lexer.TokenWarning, Value: "0: warning: odd 'a'"}))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "a"}))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: lexer.TokenError, Value: "1: error: E2 unexpected 'b'"}))
+		for range l.All() {
+		}
+		Expect(l.Diagnostics()).To(HaveLen(2))
+		Expect(l.Diagnostics()[1].Code).To(Equal("E2"))
+	})
+})
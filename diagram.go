@@ -0,0 +1,274 @@
+package lexer
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// WriteDOT writes the table as a Graphviz DOT graph, e.g. to document the lexical grammar of
+// a language: a node for each mode, linked to a node for each of its rules, labeled with the
+// token type and pattern of the rule. Rules beginning a mode are linked to the mode, and
+// inclusive modes to ModeInitial, whose rules they inherit.
+func (r *Rules) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph rules {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	for _, mode := range r.sortedModes() {
+		rules, exclusive := r.rules, false
+		if mode != ModeInitial {
+			rules, exclusive = r.modes[mode].rules.rules, r.modes[mode].exclusive
+		}
+		label := fmt.Sprintf("mode %d", mode)
+		if exclusive {
+			label += " (exclusive)"
+		}
+		fmt.Fprintf(&b, "\tmode%d [shape=ellipse, label=%s];\n", mode, strconv.Quote(label))
+		if mode != ModeInitial && !exclusive {
+			fmt.Fprintf(&b, "\tmode%d -> mode0 [style=dotted, label=\"inherits\"];\n", mode)
+		}
+		for i, u := range rules {
+			var label string
+			switch {
+			case u.match != nil:
+				label = "matcher"
+			case u.skip:
+				label = "skip\n" + u.pattern.String()
+			default:
+				label = u.tokenType.String() + "\n" + u.pattern.String()
+			}
+			fmt.Fprintf(&b, "\tmode%d_rule%d [label=%s];\n", mode, i, strconv.Quote(label))
+			fmt.Fprintf(&b, "\tmode%d -> mode%d_rule%d;\n", mode, mode, i)
+			if u.begin {
+				fmt.Fprintf(&b, "\tmode%d_rule%d -> mode%d [style=dashed, label=\"begin\"];\n", mode, i, u.mode)
+			}
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteEBNF writes the table as a grammar in the W3C EBNF notation, from which railroad
+// diagram generators render a diagram of each production, e.g. to document the lexical
+// grammar of a language.
+//
+// The production of a token type, named after the name registered for it (see
+// RegisterTokenType) if it is a valid identifier, lists the patterns of its rules as
+// alternatives; rules added with Skip are listed as the production Skip. The productions of
+// modes other than ModeInitial are suffixed with the mode (e.g. Text_mode1). Assertions and
+// trailing context, which the notation cannot express, as well as matchers, are written as
+// comments. Returns an error if a pattern is not a valid regular expression.
+func (r *Rules) WriteEBNF(w io.Writer) error {
+	var b strings.Builder
+	for _, mode := range r.sortedModes() {
+		table, classes := r.rules, r.classes
+		if mode != ModeInitial {
+			table, classes = r.modes[mode].rules.rules, r.mergeClasses(r.modes[mode].rules)
+		}
+		rules, err := compileRules(table, classes)
+		if err != nil {
+			if mode != ModeInitial {
+				return fmt.Errorf("lexer: mode %d: %w", mode, err)
+			}
+			return err
+		}
+		var names []string
+		productions := map[string][]string{}
+		for i, u := range rules {
+			if u.match != nil {
+				fmt.Fprintf(&b, "/* rule %d is a matcher */\n", i)
+				continue
+			}
+			name := ebnfName(u, mode)
+			if _, ok := productions[name]; !ok {
+				names = append(names, name)
+			}
+			productions[name] = append(productions[name], ebnfPattern(u.pattern))
+		}
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s ::= %s\n", name, strings.Join(productions[name], "\n\t| "))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// sortedModes returns ModeInitial followed by the modes of the table, in order.
+func (r *Rules) sortedModes() []Mode {
+	modes := []Mode{ModeInitial}
+	for mode := range r.modes {
+		if mode != ModeInitial {
+			modes = append(modes, mode)
+		}
+	}
+	sort.Slice(modes, func(i, j int) bool {
+		return modes[i] < modes[j]
+	})
+	return modes
+}
+
+func ebnfName(u rule, mode Mode) string {
+	name := "Skip"
+	if !u.skip {
+		var ok bool
+		if name, ok = u.tokenType.name(); !ok || !token.IsIdentifier(name) {
+			name = fmt.Sprintf("Token%d", u.tokenType)
+		}
+	}
+	if mode != ModeInitial {
+		name += fmt.Sprintf("_mode%d", mode)
+	}
+	return name
+}
+
+func ebnfPattern(p Pattern) string {
+	s := ebnfExpr(p.expr)
+	if p.trailing != "" {
+		s += " /* followed by " + ebnfExpr(p.trailing) + " */"
+	}
+	return s
+}
+
+func ebnfExpr(expr string) string {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return "/* " + expr + " */"
+	}
+	var b strings.Builder
+	writeEBNF(&b, re.Simplify())
+	return b.String()
+}
+
+// writeEBNF writes the regular expression in the W3C EBNF notation.
+func writeEBNF(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		b.WriteString("()")
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			for i, r := range re.Rune {
+				if i > 0 {
+					b.WriteByte(' ')
+				}
+				writeEBNFClass(b, foldRanges(r))
+			}
+			return
+		}
+		writeEBNFString(b, string(re.Rune))
+	case syntax.OpCharClass:
+		var ranges []runeRange
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			ranges = append(ranges, runeRange{re.Rune[i], re.Rune[i+1]})
+		}
+		writeEBNFClass(b, ranges)
+	case syntax.OpAnyCharNotNL:
+		b.WriteString("[^#xA]")
+	case syntax.OpAnyChar:
+		b.WriteString("[#x0-#x10FFFF]")
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		fmt.Fprintf(b, "/* %v */", re)
+	case syntax.OpCapture:
+		writeEBNF(b, re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest:
+		writeEBNFOperand(b, re.Sub[0])
+		b.WriteString(map[syntax.Op]string{syntax.OpStar: "*", syntax.OpPlus: "+", syntax.OpQuest: "?"}[re.Op])
+	case syntax.OpConcat:
+		for i, sub := range re.Sub {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			if sub.Op == syntax.OpAlternate {
+				b.WriteByte('(')
+				writeEBNF(b, sub)
+				b.WriteByte(')')
+			} else {
+				writeEBNF(b, sub)
+			}
+		}
+	case syntax.OpAlternate:
+		for i, sub := range re.Sub {
+			if i > 0 {
+				b.WriteString(" | ")
+			}
+			writeEBNF(b, sub)
+		}
+	default:
+		fmt.Fprintf(b, "/* %v */", re)
+	}
+}
+
+// writeEBNFOperand writes the operand of a postfix operator, parenthesized unless it is a
+// single string or character class.
+func writeEBNFOperand(b *strings.Builder, re *syntax.Regexp) {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	switch {
+	case re.Op == syntax.OpCharClass, re.Op == syntax.OpAnyChar, re.Op == syntax.OpAnyCharNotNL,
+		re.Op == syntax.OpLiteral && (len(re.Rune) == 1 || re.Flags&syntax.FoldCase == 0):
+		writeEBNF(b, re)
+	default:
+		b.WriteByte('(')
+		writeEBNF(b, re)
+		b.WriteByte(')')
+	}
+}
+
+// writeEBNFString writes the string as a quoted string, or as a sequence of characters if it
+// contains both kinds of quotes.
+func writeEBNFString(b *strings.Builder, s string) {
+	switch {
+	case !strings.Contains(s, `"`):
+		b.WriteString(`"` + s + `"`)
+	case !strings.Contains(s, `'`):
+		b.WriteString(`'` + s + `'`)
+	default:
+		for i, r := range s {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			fmt.Fprintf(b, "#x%X", r)
+		}
+	}
+}
+
+// writeEBNFClass writes the ranges as a character class, negated if it contains the last
+// rune.
+func writeEBNFClass(b *strings.Builder, ranges []runeRange) {
+	b.WriteByte('[')
+	if n := len(ranges); n > 0 && ranges[n-1].hi == unicode.MaxRune {
+		b.WriteByte('^')
+		ranges = complementRanges(ranges)
+	}
+	for _, r := range ranges {
+		writeEBNFChar(b, r.lo)
+		if r.hi > r.lo {
+			b.WriteByte('-')
+			writeEBNFChar(b, r.hi)
+		}
+	}
+	b.WriteByte(']')
+}
+
+func writeEBNFChar(b *strings.Builder, r rune) {
+	if unicode.IsPrint(r) && r != ' ' && !strings.ContainsRune(`[]^-#"'`, r) {
+		b.WriteRune(r)
+		return
+	}
+	fmt.Fprintf(b, "#x%X", r)
+}
+
+// foldRanges returns the ranges of the runes equivalent to the rune under simple case
+// folding.
+func foldRanges(r rune) []runeRange {
+	ranges := []runeRange{{r, r}}
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		ranges = append(ranges, runeRange{f, f})
+	}
+	return normalizeRanges(ranges)
+}
@@ -0,0 +1,66 @@
+package lexer_test
+
+import (
+	"strings"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diagram", func() {
+	const (
+		Identifier lexer.TokenType = iota + 1800
+		Operator
+		Quote
+		Text
+	)
+
+	const Quoted lexer.Mode = 1
+
+	BeforeEach(func() {
+		lexer.RegisterTokenType(Identifier, "Identifier")
+		lexer.RegisterTokenType(Operator, "Operator")
+		lexer.RegisterTokenType(Quote, "QUOTE")
+	})
+
+	rules := func() *lexer.Rules {
+		return lexer.NewRules().
+			Skip(lexer.Class(`\s`)).
+			Add(Identifier, lexer.Regexp(`(?i:if)|[a-z_]\w*`)).
+			Add(Operator, lexer.Literal("**")).
+			Add(Operator, lexer.Class(`[-+*/]`).FollowedBy(lexer.Literal("="))).
+			Add(Quote, lexer.Literal(`"`)).Begin(Quoted).
+			Exclusive(Quoted, lexer.NewRules().
+				Add(Text, lexer.Regexp(`[^"]+|\$$`)).
+				Add(Quote, lexer.Literal(`"`)).Begin(lexer.ModeInitial).
+				Match(func(l *lexer.Lexer) bool { return false }))
+	}
+
+	It("should write the rules as a W3C EBNF grammar (i.e. WriteEBNF)", func() {
+		var b strings.Builder
+		Expect(rules().WriteEBNF(&b)).To(Succeed())
+		Expect(b.String()).To(Equal(strings.Join([]string{
+			`Skip ::= [#x9-#xA#xC-#xD#x20]+`,
+			`Identifier ::= [Ii] [Ff] | [_a-z] [0-9A-Z_a-z]*`,
+			`Operator ::= "**"`,
+			`	| [*-+#x2D/]+ /* followed by "=" */`,
+			`QUOTE ::= '"'`,
+			`/* rule 2 is a matcher */`,
+			`Token1803_mode1 ::= [^#x22]+ | "$" /* (?-m:$) */`,
+			`QUOTE_mode1 ::= '"'`,
+			``,
+		}, "\n")))
+	})
+
+	It("should write the rules as a DOT graph (i.e. WriteDOT)", func() {
+		var b strings.Builder
+		Expect(rules().WriteDOT(&b)).To(Succeed())
+		Expect(b.String()).To(HavePrefix("digraph rules {\n"))
+		Expect(b.String()).To(ContainSubstring(`mode0_rule2 [label="Operator\n\\*\\*"];`))
+		Expect(b.String()).To(ContainSubstring(`mode0_rule4 -> mode1 [style=dashed, label="begin"];`))
+		Expect(b.String()).To(ContainSubstring(`mode1 [shape=ellipse, label="mode 1 (exclusive)"];`))
+		Expect(b.String()).To(ContainSubstring(`mode1_rule2 [label="matcher"];`))
+	})
+})
@@ -0,0 +1,118 @@
+package lexer
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Dump writes the tokens to w in a human-readable form, one per line, with their positions,
+// type names, and escaped values aligned in columns.
+//
+// Trivia is written on indented lines below the token it is attached to.
+func Dump(w io.Writer, tokens []Token) error {
+	return dump(NewDumper(w), tokens)
+}
+
+// DumpANSI writes the tokens to w in the same form as Dump, with the value of every token
+// whose type has a style colorized using ANSI escape sequences.
+func DumpANSI(w io.Writer, tokens []Token, styles map[TokenType]ANSIStyle) error {
+	return dump(NewDumperANSI(w, styles), tokens)
+}
+
+func dump(d *Dumper, tokens []Token) error {
+	for _, t := range tokens {
+		d.fit(t)
+	}
+	for _, t := range tokens {
+		if err := d.Dump(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ANSIStyle represents the parameters of an ANSI SGR escape sequence (e.g. "1;31" for bold
+// red text).
+type ANSIStyle string
+
+const (
+	ANSIBold    ANSIStyle = "1"
+	ANSIRed     ANSIStyle = "31"
+	ANSIGreen   ANSIStyle = "32"
+	ANSIYellow  ANSIStyle = "33"
+	ANSIBlue    ANSIStyle = "34"
+	ANSIMagenta ANSIStyle = "35"
+	ANSICyan    ANSIStyle = "36"
+	ANSIGray    ANSIStyle = "90"
+)
+
+// NewDumperANSI creates a dumper that writes to w, colorizing values as DumpANSI does.
+func NewDumperANSI(w io.Writer, styles map[TokenType]ANSIStyle) *Dumper {
+	d := NewDumper(w)
+	d.styles = styles
+	return d
+}
+
+// Dumper writes tokens to an io.Writer in the same form as Dump as they are received.
+//
+// Since upcoming tokens are unknown, columns are only aligned as long as positions and type
+// names do not exceed the widths of those already written.
+type Dumper struct {
+	w             io.Writer
+	positionWidth int
+	typeWidth     int
+	styles        map[TokenType]ANSIStyle
+}
+
+// NewDumper creates a dumper that writes to w.
+func NewDumper(w io.Writer) *Dumper {
+	return &Dumper{w: w, positionWidth: 5, typeWidth: 8}
+}
+
+// Dump writes the token, and its trivia, to the underlying writer.
+func (d *Dumper) Dump(t Token) error {
+	d.fit(t)
+	if err := d.write("", t); err != nil {
+		return err
+	}
+	for _, trivia := range t.Trivia {
+		if err := d.write("  ", trivia); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dumper) fit(t Token) {
+	if n := len(position(t)); n > d.positionWidth {
+		d.positionWidth = n
+	}
+	if n := utf8.RuneCountInString(t.Type.String()); n > d.typeWidth {
+		d.typeWidth = n
+	}
+	for _, trivia := range t.Trivia {
+		d.fit(trivia)
+	}
+}
+
+func (d *Dumper) write(indent string, t Token) error {
+	value := escape(t.Value)
+	if style, ok := d.styles[t.Type]; ok {
+		value = "\x1b[" + string(style) + "m" + value + "\x1b[0m"
+	}
+	_, err := fmt.Fprintf(d.w, "%s%-*s  %-*s  %s\n", indent, d.positionWidth, position(t), d.typeWidth, t.Type, value)
+	return err
+}
+
+func position(t Token) string {
+	return strconv.Itoa(t.Line) + ":" + strconv.Itoa(t.Column)
+}
+
+func escape(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,107 @@
+package lexer_test
+
+import (
+	"bytes"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dump", func() {
+	const Identifier lexer.TokenType = 100
+
+	BeforeEach(func() {
+		lexer.RegisterTokenType(Identifier, "IDENT")
+	})
+
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case r == '"':
+				return l.Errorf("unexpected %q", r)
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Identifier)
+			}
+		}
+	}
+
+	tokens := func(l *lexer.Lexer, n int) []lexer.Token {
+		var tokens []lexer.Token
+		for i := 0; i < n; i++ {
+			tokens = append(tokens, l.NextToken())
+		}
+		return tokens
+	}
+
+	It("should record the position, line, and column of each token", func() {
+		l := lexer.NewLexer("a\n  π b\n\nc", state)
+		t := tokens(l, 4)
+		Expect([]int{t[0].Line, t[0].Column, int(t[0].Position)}).To(Equal([]int{1, 1, 0}))
+		Expect([]int{t[1].Line, t[1].Column, int(t[1].Position)}).To(Equal([]int{2, 3, 4}))
+		Expect([]int{t[2].Line, t[2].Column, int(t[2].Position)}).To(Equal([]int{2, 5, 7}))
+		Expect([]int{t[3].Line, t[3].Column, int(t[3].Position)}).To(Equal([]int{4, 1, 10}))
+	})
+
+	It("should return the registered name of a token type (i.e. RegisterTokenType)", func() {
+		Expect(Identifier.String()).To(Equal("IDENT"))
+		Expect(lexer.TokenError.String()).To(Equal("lexer.ERROR"))
+		Expect(lexer.TokenType(42).String()).To(Equal("42"))
+		t, ok := lexer.TokenTypeByName("IDENT")
+		Expect(ok).To(BeTrue())
+		Expect(t).To(Equal(Identifier))
+		Expect(lexer.TokenIdentifier.String()).To(Equal("lexer.IDENT"))
+	})
+
+	It("should move a registered name to the token type registered with it (i.e. RegisterTokenType)", func() {
+		const First, Second lexer.TokenType = 9100, 9101
+		lexer.RegisterTokenType(First, "MOVED")
+		lexer.RegisterTokenType(Second, "MOVED")
+		Expect(First.String()).To(Equal("9100"))
+		Expect(Second.String()).To(Equal("MOVED"))
+		t, ok := lexer.TokenTypeByName("MOVED")
+		Expect(ok).To(BeTrue())
+		Expect(t).To(Equal(Second))
+	})
+
+	It("should write aligned, human-readable tokens (i.e. Dump)", func() {
+		l := lexer.NewLexer("x\n\tyy \"", state, lexer.WithTrivia(lexer.TriviaLeading))
+		var b bytes.Buffer
+		Expect(lexer.Dump(&b, tokens(l, 3))).To(Succeed())
+		Expect(b.String()).To(Equal("" +
+			"1:1    IDENT         \"x\"\n" +
+			"2:2    IDENT         \"yy\"\n" +
+			"  1:2    lexer.TRIVIA  \"\\n\\t\"\n" +
+			"2:5    lexer.ERROR   \"unexpected '\\\"'\"\n" +
+			"  2:4    lexer.TRIVIA  \" \"\n"))
+	})
+
+	It("should write tokens as they are received (i.e. Dumper)", func() {
+		var b bytes.Buffer
+		d := lexer.NewDumper(&b)
+		Expect(d.Dump(lexer.Token{Type: Identifier, Value: "x", Line: 1, Column: 1})).To(Succeed())
+		Expect(d.Dump(lexer.Token{Type: Identifier, Value: 42, Line: 10, Column: 12})).To(Succeed())
+		Expect(b.String()).To(Equal("" +
+			"1:1    IDENT     \"x\"\n" +
+			"10:12  IDENT     42\n"))
+	})
+
+	It("should colorize values by token type (i.e. DumpANSI)", func() {
+		var b bytes.Buffer
+		styles := map[lexer.TokenType]lexer.ANSIStyle{Identifier: lexer.ANSIBold + ";" + lexer.ANSIBlue}
+		Expect(lexer.DumpANSI(&b, []lexer.Token{
+			{Type: Identifier, Value: "x", Line: 1, Column: 1},
+			{Type: lexer.TokenError, Value: "bad", Line: 1, Column: 3},
+		}, styles)).To(Succeed())
+		Expect(b.String()).To(Equal("" +
+			"1:1    IDENT        \x1b[1;34m\"x\"\x1b[0m\n" +
+			"1:3    lexer.ERROR  \"bad\"\n"))
+	})
+})
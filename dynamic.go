@@ -0,0 +1,131 @@
+package lexer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RuleID identifies a rule added to a DynamicRules table at runtime.
+type RuleID int
+
+// DynamicRules is a table of rules to which rules can be added, and from which they can be
+// removed, while lexers apply it, e.g. for user-defined operators in extensible languages.
+//
+// The rules added at runtime are compiled individually, leaving the compiled table intact,
+// and apply wherever the rules of the table do (i.e. not in exclusive modes), as if added
+// after them. Changes take effect from the next token lexed.
+type DynamicRules struct {
+	mutex   sync.RWMutex
+	base    *ruleTable
+	modes   map[Mode]*ruleTable
+	inherit map[Mode]bool
+	runtime []rule
+	next    RuleID
+	classes map[string][]runeRange
+}
+
+// Dynamic compiles the table into a table to which rules can be added at runtime. Returns
+// an error if the table is not valid (see Validate).
+func (r *Rules) Dynamic() (*DynamicRules, error) {
+	base, err := compileTable(r.rules, len(r.rules), r.classes)
+	if err != nil {
+		return nil, err
+	}
+	d := &DynamicRules{base: base, modes: map[Mode]*ruleTable{}, inherit: map[Mode]bool{}, classes: r.classes}
+	for mode, m := range r.modes {
+		t, err := compileTable(r.modeRules(m), len(m.rules.rules), r.mergeClasses(m.rules))
+		if err != nil {
+			return nil, fmt.Errorf("lexer: mode %d: %w", mode, err)
+		}
+		t.mode = mode
+		d.modes[mode], d.inherit[mode] = t, !m.exclusive
+	}
+	return d, nil
+}
+
+// Add adds a rule emitting a token of the specified type for input matching the pattern,
+// returning its ID. The pattern may refer to the classes of the table (see DefineClass).
+// Returns an error if the pattern is not a valid regular expression.
+func (d *DynamicRules) Add(tokenType TokenType, pattern Pattern) (RuleID, error) {
+	pattern, re, err := compilePattern(pattern, d.classes)
+	if err != nil {
+		return 0, fmt.Errorf("lexer: %w", err)
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	id := d.next
+	d.next++
+	d.runtime = append(d.runtime, rule{index: int(id), tokenType: tokenType, pattern: pattern, re: re})
+	return id, nil
+}
+
+// Remove removes the rule with the specified ID, returning false if there is no such rule.
+func (d *DynamicRules) Remove(id RuleID) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for i, u := range d.runtime {
+		if u.index == int(id) {
+			d.runtime = append(d.runtime[:i:i], d.runtime[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// State returns a state that lexes the input as Rules.State does, applying the rules of the
+// table as they are when each token is lexed.
+func (d *DynamicRules) State() StateFunc {
+	var state StateFunc
+	state = func(l *Lexer) StateFunc {
+		r := l.Peek()
+		if r == EOF {
+			return nil
+		}
+		if d.match(l) {
+			return state
+		}
+		return l.unmatched(r, state)
+	}
+	return state
+}
+
+func (d *DynamicRules) match(l *Lexer) bool {
+	var start time.Time
+	if l.profile != nil {
+		start = time.Now()
+	}
+	input := l.Input[l.CurrentPosition:]
+	d.mutex.RLock()
+	t, inherit := d.base, true
+	if m, ok := d.modes[l.mode]; ok {
+		t, inherit = m, d.inherit[l.mode]
+	}
+	var match rule
+	u, n := t.longest(input)
+	if u != nil {
+		match = *u
+	}
+	runtime := false
+	if inherit {
+		if v, m := longestMatch(d.runtime, input); m > n {
+			match, n, runtime = *v, m, true
+		}
+	}
+	d.mutex.RUnlock()
+	// The rule is applied without holding the lock, so that its action can change the table.
+	if n > 0 {
+		if l.profile != nil && !runtime {
+			t.record(l.profile, &match, time.Since(start))
+		}
+		match.apply(l, n)
+		return true
+	}
+	if u := t.matchers(l); u != nil {
+		if l.profile != nil {
+			t.record(l.profile, u, time.Since(start))
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DynamicRules", func() {
+	const (
+		Identifier lexer.TokenType = iota
+		Operator
+		Declaration
+	)
+
+	var rules *lexer.DynamicRules
+
+	BeforeEach(func() {
+		var err error
+		rules, err = lexer.NewRules().
+			Skip(lexer.Class(`\s`)).
+			Add(Operator, lexer.Regexp(`[-+*/<>=]`)).
+			Add(Identifier, lexer.Class(`[a-z]`)).
+			Add(Declaration, lexer.Regexp(`infix \S+`)).
+			Action(func(l *lexer.Lexer, lexeme string) (interface{}, bool) {
+				_, err := rules.Add(Operator, lexer.Literal(lexeme[len("infix "):]))
+				Expect(err).NotTo(HaveOccurred())
+				return nil, false
+			}).
+			Dynamic()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	types := func(input string) []lexer.TokenType {
+		tokens, err := lexer.Tokenize(input, rules.State())
+		Expect(err).NotTo(HaveOccurred())
+		var types []lexer.TokenType
+		for _, t := range tokens {
+			types = append(types, t.Type)
+		}
+		return types
+	}
+
+	It("should apply rules added while lexing (i.e. Add)", func() {
+		tokens, err := lexer.Tokenize("a <=> b infix <=> a <=> b", rules.State())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(8))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: Operator, Value: "<"}))
+		Expect(tokens[6]).To(EqualToken(lexer.Token{Type: Operator, Value: "<=>"}))
+	})
+
+	It("should stop applying removed rules (i.e. Remove)", func() {
+		id, err := rules.Add(Identifier, lexer.Class(`[a-z_]`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(types("a_b")).To(Equal([]lexer.TokenType{Identifier}))
+		Expect(rules.Remove(id)).To(BeTrue())
+		Expect(rules.Remove(id)).To(BeFalse())
+		_, err = lexer.Tokenize("a_b", rules.State())
+		Expect(err).To(MatchError(`unexpected '_'`))
+	})
+
+	It("should prefer the rules of the table to added rules matching the same input (i.e. Add)", func() {
+		_, err := rules.Add(Identifier, lexer.Literal("+"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(types("+")).To(Equal([]lexer.TokenType{Operator}))
+	})
+
+	It("should report invalid patterns (i.e. Add)", func() {
+		_, err := rules.Add(Identifier, lexer.Regexp(`[a-`))
+		Expect(err).To(HaveOccurred())
+	})
+})
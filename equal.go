@@ -0,0 +1,62 @@
+package lexer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// String returns the type and value of the token.
+func (t Token) String() string {
+	if s, ok := t.Value.(string); ok {
+		return fmt.Sprintf("%v %q", t.Type, s)
+	}
+	return fmt.Sprintf("%v %v", t.Type, t.Value)
+}
+
+// Equal reports whether the token has the same type, value, raw text, and trivia as the
+// specified token.
+//
+// Positions are not compared.
+func (t Token) Equal(u Token) bool {
+	if t.Type != u.Type || t.Raw != u.Raw || !reflect.DeepEqual(t.Value, u.Value) || len(t.Trivia) != len(u.Trivia) {
+		return false
+	}
+	for i := range t.Trivia {
+		if !t.Trivia[i].Equal(u.Trivia[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// TokensEqual returns an error describing the differences between the expected and actual
+// tokens, or nil if they are equal.
+//
+// The error lists the tokens one per line; differing tokens are marked with "!", missing
+// tokens with "-", and unexpected tokens with "+".
+func TokensEqual(expected, actual []Token) error {
+	n := len(expected)
+	if len(actual) > n {
+		n = len(actual)
+	}
+	var b strings.Builder
+	equal := len(expected) == len(actual)
+	for i := 0; i < n; i++ {
+		switch {
+		case i >= len(actual):
+			fmt.Fprintf(&b, "\n- %d: %v", i, expected[i])
+		case i >= len(expected):
+			fmt.Fprintf(&b, "\n+ %d: %v", i, actual[i])
+		case !expected[i].Equal(actual[i]):
+			fmt.Fprintf(&b, "\n! %d: expected %v, actual %v", i, expected[i], actual[i])
+			equal = false
+		default:
+			fmt.Fprintf(&b, "\n  %d: %v", i, actual[i])
+		}
+	}
+	if equal {
+		return nil
+	}
+	return fmt.Errorf("lexer: tokens differ (%d expected, %d actual):%s", len(expected), len(actual), b.String())
+}
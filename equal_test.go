@@ -0,0 +1,37 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Token comparison", func() {
+	token := func(value interface{}) lexer.Token {
+		return lexer.Token{Type: Token, Value: value}
+	}
+
+	It("should report whether two tokens are equal (i.e. Token.Equal)", func() {
+		Expect(token("E").Equal(token("E"))).To(BeTrue())
+		Expect(token("E").Equal(token("m"))).To(BeFalse())
+		Expect(token("E").Equal(lexer.Token{Type: lexer.TokenError, Value: "E"})).To(BeFalse())
+		withTrivia := lexer.Token{Type: Token, Value: "E", Trivia: []lexer.Token{token(" ")}}
+		Expect(withTrivia.Equal(token("E"))).To(BeFalse())
+		Expect(withTrivia.Equal(withTrivia)).To(BeTrue())
+	})
+
+	It("should describe the differences between token streams (i.e. TokensEqual)", func() {
+		expected := []lexer.Token{token("E"), token("="), token("m")}
+		Expect(lexer.TokensEqual(expected, []lexer.Token{token("E"), token("="), token("m")})).To(Succeed())
+		Expect(lexer.TokensEqual(expected, []lexer.Token{token("E"), token("-")})).To(MatchError(
+			"lexer: tokens differ (3 expected, 2 actual):\n" +
+				"  0: 0 \"E\"\n" +
+				"! 1: expected 0 \"=\", actual 0 \"-\"\n" +
+				"- 2: 0 \"m\""))
+		Expect(lexer.TokensEqual(expected[:1], []lexer.Token{token("E"), token(2)})).To(MatchError(
+			"lexer: tokens differ (1 expected, 2 actual):\n" +
+				"  0: 0 \"E\"\n" +
+				"+ 1: 0 2"))
+	})
+})
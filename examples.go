@@ -0,0 +1,92 @@
+package lexer
+
+import (
+	"fmt"
+	"sort"
+)
+
+type example struct {
+	input    string
+	expected []Token
+}
+
+// Example declares that the rules lex the input into the expected tokens, as an example of
+// the rule most recently added, and returns the table. The examples of a mode are lexed in
+// that mode. Positions are not compared (see Token.Equal).
+func (r *Rules) Example(input string, expected ...Token) *Rules {
+	if n := len(r.rules); n > 0 {
+		r.rules[n-1].examples = append(r.rules[n-1].examples, example{input, expected})
+	}
+	return r
+}
+
+// TestingT is the subset of testing.TB used by TestRules.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// TestRules lexes the examples of the rules (see Example), reporting an error for each
+// example the rules do not lex as expected, e.g.
+//
+//	func TestLexer(t *testing.T) {
+//		lexer.TestRules(t, rules)
+//	}
+func TestRules(t TestingT, r *Rules) {
+	t.Helper()
+	if err := r.checkExamples(func(err error) {
+		t.Errorf("%v", err)
+	}); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+// CheckExamples lexes the examples of the rules (see Example), returning an error for the
+// first example the rules do not lex as expected.
+func (r *Rules) CheckExamples() error {
+	var first error
+	if err := r.checkExamples(func(err error) {
+		if first == nil {
+			first = err
+		}
+	}); err != nil {
+		return err
+	}
+	return first
+}
+
+func (r *Rules) checkExamples(report func(error)) error {
+	state, err := r.State()
+	if err != nil {
+		return err
+	}
+	check := func(mode Mode, prefix string, rules []rule) {
+		initial := func(l *Lexer) StateFunc {
+			l.Begin(mode)
+			return state
+		}
+		for i, u := range rules {
+			for _, e := range u.examples {
+				actual, err := Tokenize(e.input, initial)
+				if err == nil {
+					err = TokensEqual(e.expected, actual)
+				}
+				if err != nil {
+					report(fmt.Errorf("lexer: %srule %d: example %q: %w", prefix, i, e.input, err))
+				}
+			}
+		}
+	}
+	check(ModeInitial, "", r.rules)
+	modes := make([]Mode, 0, len(r.modes))
+	for mode := range r.modes {
+		modes = append(modes, mode)
+	}
+	sort.Slice(modes, func(i, j int) bool {
+		return modes[i] < modes[j]
+	})
+	for _, mode := range modes {
+		check(mode, fmt.Sprintf("mode %d: ", mode), r.modes[mode].rules.rules)
+	}
+	return nil
+}
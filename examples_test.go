@@ -0,0 +1,67 @@
+package lexer_test
+
+import (
+	"fmt"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+var _ = Describe("Examples", func() {
+	const (
+		Identifier lexer.TokenType = iota
+		Number
+		Word
+	)
+
+	const Quoted lexer.Mode = 1
+
+	rules := func() *lexer.Rules {
+		return lexer.NewRules().
+			Skip(lexer.Class(`\s`)).
+			Add(Number, lexer.Class(`\d`)).
+			Example("1 23", lexer.Token{Type: Number, Value: "1"}, lexer.Token{Type: Number, Value: "23"}).
+			Add(Identifier, lexer.Regexp(`[a-z]\w*`)).
+			Example("a1", lexer.Token{Type: Identifier, Value: "a1"})
+	}
+
+	It("should lex the examples of the rules (i.e. Example and TestRules)", func() {
+		r := &recorder{}
+		lexer.TestRules(r, rules().Exclusive(Quoted, lexer.NewRules().
+			Add(Word, lexer.Class(`\w`)).
+			Example("1a", lexer.Token{Type: Word, Value: "1a"})))
+		Expect(r.errors).To(BeEmpty())
+		lexer.TestRules(GinkgoT(), rules())
+	})
+
+	It("should report the examples the rules do not lex as expected (i.e. TestRules)", func() {
+		r := &recorder{}
+		lexer.TestRules(r, rules().
+			Example("1a", lexer.Token{Type: Identifier, Value: "1a"}).
+			Example("-"))
+		Expect(r.errors).To(Equal([]string{
+			"lexer: rule 2: example \"1a\": lexer: tokens differ (1 expected, 2 actual):\n" +
+				"! 0: expected 0 \"1a\", actual 1 \"1\"\n" +
+				"+ 1: 0 \"a\"",
+			"lexer: rule 2: example \"-\": unexpected '-'",
+		}))
+	})
+
+	It("should return the first example the rules do not lex as expected (i.e. CheckExamples)", func() {
+		Expect(rules().CheckExamples()).To(Succeed())
+		err := rules().Example("a b", lexer.Token{Type: Identifier, Value: "a"}).CheckExamples()
+		Expect(err).To(MatchError(HavePrefix(`lexer: rule 2: example "a b": lexer: tokens differ`)))
+	})
+})
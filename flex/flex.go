@@ -0,0 +1,315 @@
+// Package flex imports the rules of flex specifications (.l files) as rulespec specs, e.g.
+// to migrate an existing lexer from a C toolchain:
+//
+//	spec, err := flex.Parse(f)
+//	state, types, err := spec.Compile()
+//
+// A subset of flex is supported:
+//
+//   - name definitions, substituted for {name} in patterns
+//   - inclusive (%s) and exclusive (%x) start conditions, mapped to modes
+//   - rules prefixed with start conditions, e.g. <STR>, <A,B>, or <*>
+//   - actions consisting of return and BEGIN statements, or | for the action of the next
+//     rule; other statements are ignored, and rules without a return skip their input
+//   - trailing context, e.g. a/b
+//
+// Patterns with anchors (^ and $) are not supported, and <<EOF>> rules are ignored.
+//
+// Code blocks, %option lines, and the user code section are ignored.
+package flex
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/eczarny/lexer/rulespec"
+)
+
+type parser struct {
+	definitions map[string]string
+	modes       []string
+	exclusive   map[string]bool
+	rules       map[string][]rulespec.Rule
+	line        int
+}
+
+// Parse parses the flex specification read from r.
+func Parse(r io.Reader) (*rulespec.Spec, error) {
+	p := &parser{
+		definitions: map[string]string{},
+		exclusive:   map[string]bool{},
+		rules:       map[string][]rulespec.Rule{},
+	}
+	scanner := bufio.NewScanner(r)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	i, err := p.parseDefinitions(lines)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.parseRules(lines, i); err != nil {
+		return nil, err
+	}
+	spec := &rulespec.Spec{Rules: p.rules[rulespec.InitialMode]}
+	for _, mode := range p.modes {
+		spec.Modes = append(spec.Modes, rulespec.Mode{
+			Name:      mode,
+			Exclusive: p.exclusive[mode],
+			Rules:     p.rules[mode],
+		})
+	}
+	return spec, nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("flex: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// parseDefinitions parses the definitions section, returning the index of the line
+// following it.
+func (p *parser) parseDefinitions(lines []string) (int, error) {
+	code := false
+	for i, line := range lines {
+		p.line = i + 1
+		switch {
+		case strings.HasPrefix(line, "%{"):
+			code = true
+		case strings.HasPrefix(line, "%}"):
+			code = false
+		case code || strings.TrimSpace(line) == "" || line[0] == ' ' || line[0] == '\t' || strings.HasPrefix(line, "/*"):
+		case strings.HasPrefix(line, "%%"):
+			return i + 1, nil
+		case strings.HasPrefix(line, "%s") || strings.HasPrefix(line, "%x"):
+			fields := strings.Fields(line)
+			for _, mode := range fields[1:] {
+				p.modes = append(p.modes, mode)
+				p.exclusive[mode] = strings.HasPrefix(fields[0], "%x")
+			}
+		case strings.HasPrefix(line, "%"):
+		default:
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				return 0, p.errorf("expected a name definition")
+			}
+			expr, trailing, err := p.translate(strings.TrimSpace(line[len(fields[0]):]))
+			if err != nil {
+				return 0, err
+			}
+			if trailing != "" {
+				return 0, p.errorf("trailing context is not supported in definition %q", fields[0])
+			}
+			p.definitions[fields[0]] = expr
+		}
+	}
+	p.line = len(lines)
+	return 0, p.errorf("expected %%%%")
+}
+
+func (p *parser) parseRules(lines []string, start int) error {
+	var pending []pendingRule
+	for i := start; i < len(lines); i++ {
+		p.line = i + 1
+		line := lines[i]
+		if strings.HasPrefix(line, "%%") {
+			break
+		}
+		if strings.TrimSpace(line) == "" || line[0] == ' ' || line[0] == '\t' || strings.HasPrefix(line, "%{") {
+			if strings.HasPrefix(line, "%{") {
+				for i < len(lines) && !strings.HasPrefix(lines[i], "%}") {
+					i++
+				}
+			}
+			continue
+		}
+		modes, rest, err := p.startConditions(line)
+		if err != nil {
+			return err
+		}
+		pattern, action := splitPattern(rest)
+		for depth := braceDepth(action); depth > 0 && i+1 < len(lines); depth = braceDepth(action) {
+			i++
+			action += "\n" + lines[i]
+		}
+		if pattern == "<<EOF>>" {
+			continue
+		}
+		expr, trailing, err := p.translate(pattern)
+		if err != nil {
+			return err
+		}
+		pending = append(pending, pendingRule{modes, expr, trailing})
+		if strings.TrimSpace(action) == "|" {
+			continue
+		}
+		token, begin := parseAction(action)
+		for _, r := range pending {
+			for _, mode := range r.modes {
+				p.rules[mode] = append(p.rules[mode], rulespec.Rule{Token: token, Pattern: r.expr, Lookahead: r.trailing, Begin: begin})
+			}
+		}
+		pending = nil
+	}
+	if len(pending) > 0 {
+		return p.errorf("expected an action")
+	}
+	return nil
+}
+
+type pendingRule struct {
+	modes    []string
+	expr     string
+	trailing string
+}
+
+// startConditions returns the modes a rule applies to, and the rest of the rule.
+func (p *parser) startConditions(line string) ([]string, string, error) {
+	end := strings.IndexByte(line, '>')
+	if !strings.HasPrefix(line, "<") || strings.HasPrefix(line, "<<EOF>>") || end < 0 {
+		return []string{rulespec.InitialMode}, line, nil
+	}
+	names := strings.Split(line[1:end], ",")
+	if len(names) == 1 && names[0] == "*" {
+		return append([]string{rulespec.InitialMode}, p.modes...), line[end+1:], nil
+	}
+	for _, name := range names {
+		if _, ok := p.exclusive[name]; !ok && name != rulespec.InitialMode {
+			return nil, "", p.errorf("undeclared start condition %q", name)
+		}
+	}
+	return names, line[end+1:], nil
+}
+
+// splitPattern splits a rule into its pattern, which ends at the first whitespace outside
+// quotes and brackets, and its action.
+func splitPattern(rule string) (string, string) {
+	quoted, class := false, false
+	for i := 0; i < len(rule); i++ {
+		switch c := rule[i]; {
+		case c == '\\':
+			i++
+		case quoted:
+			quoted = c != '"'
+		case class:
+			class = c != ']'
+		case c == '"':
+			quoted = true
+		case c == '[':
+			class = true
+			if strings.HasPrefix(rule[i+1:], "]") || strings.HasPrefix(rule[i+1:], "^]") {
+				i += strings.IndexByte(rule[i+1:], ']') + 1
+			}
+		case c == ' ' || c == '\t':
+			return rule[:i], strings.TrimSpace(rule[i:])
+		}
+	}
+	return rule, ""
+}
+
+func braceDepth(action string) int {
+	return strings.Count(action, "{") - strings.Count(action, "}")
+}
+
+var (
+	returnStatement = regexp.MustCompile(`^return\s*\(?\s*([^\s()]+)\s*\)?$`)
+	beginStatement  = regexp.MustCompile(`^BEGIN\s*\(?\s*(\w+)\s*\)?$`)
+	comments        = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`)
+)
+
+// parseAction returns the name of the token returned by the action, if any, and the mode
+// it begins, if any.
+func parseAction(action string) (string, string) {
+	action = strings.TrimSpace(comments.ReplaceAllString(action, ""))
+	action = strings.TrimSuffix(strings.TrimPrefix(action, "{"), "}")
+	var token, begin string
+	for _, statement := range strings.Split(action, ";") {
+		statement = strings.TrimSpace(statement)
+		if m := returnStatement.FindStringSubmatch(statement); m != nil {
+			token = m[1]
+		} else if m := beginStatement.FindStringSubmatch(statement); m != nil {
+			begin = m[1]
+			if begin == "0" {
+				begin = rulespec.InitialMode
+			}
+		}
+	}
+	return token, begin
+}
+
+// translate translates a flex pattern into a regular expression, and its trailing context,
+// if any.
+func (p *parser) translate(pattern string) (string, string, error) {
+	var expr strings.Builder
+	trailing := -1
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '\\':
+			if i+1 < len(pattern) {
+				expr.WriteString(pattern[i : i+2])
+				i++
+			}
+		case '"':
+			end := strings.IndexByte(pattern[i+1:], '"')
+			if end < 0 {
+				return "", "", p.errorf("unterminated string in pattern %q", pattern)
+			}
+			expr.WriteString(regexp.QuoteMeta(pattern[i+1 : i+1+end]))
+			i += end + 1
+		case '[':
+			end := i + 1
+			if strings.HasPrefix(pattern[end:], "^") {
+				end++
+			}
+			if strings.HasPrefix(pattern[end:], "]") {
+				end++
+			}
+			for end < len(pattern) && pattern[end] != ']' {
+				if strings.HasPrefix(pattern[end:], "[:") {
+					end += strings.Index(pattern[end:], ":]") + 1
+				} else if pattern[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(pattern) {
+				return "", "", p.errorf("unterminated character class in pattern %q", pattern)
+			}
+			expr.WriteString(pattern[i : end+1])
+			i = end
+		case '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				return "", "", p.errorf("unterminated braces in pattern %q", pattern)
+			}
+			name := pattern[i+1 : i+end]
+			if definition, ok := p.definitions[name]; ok {
+				expr.WriteString("(?:" + definition + ")")
+			} else if strings.Trim(name, "0123456789,") == "" {
+				expr.WriteString(pattern[i : i+end+1])
+			} else {
+				return "", "", p.errorf("undefined name %q", name)
+			}
+			i += end
+		case '/':
+			if trailing >= 0 {
+				return "", "", p.errorf("multiple trailing contexts in pattern %q", pattern)
+			}
+			trailing = expr.Len()
+		case '^', '$':
+			return "", "", p.errorf("anchors are not supported in pattern %q", pattern)
+		default:
+			expr.WriteByte(c)
+		}
+	}
+	if trailing >= 0 {
+		return expr.String()[:trailing], expr.String()[trailing:], nil
+	}
+	return expr.String(), "", nil
+}
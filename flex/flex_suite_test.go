@@ -0,0 +1,13 @@
+package flex_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestFlex(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Flex Suite")
+}
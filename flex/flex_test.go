@@ -0,0 +1,92 @@
+package flex_test
+
+import (
+	"strings"
+
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/flex"
+	"github.com/eczarny/lexer/rulespec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Flex", func() {
+	const calc = `%{
+#include "y.tab.h"
+%}
+%option noyywrap
+DIGIT    [0-9]
+ID       [a-z][a-z0-9]*
+%x STR
+%%
+{DIGIT}+("."{DIGIT}+)?  { yylval.f = atof(yytext); return NUMBER; }
+"if"|"else"             return KEYWORD;
+{ID}                    return(IDENT);
+"**"                    |
+[-+*/]                  return OP;
+\"                      BEGIN(STR); return QUOTE;
+<STR>[^"\n]+            return TEXT;
+<STR>\"                 {
+                            BEGIN(INITIAL);
+                            return QUOTE;
+                        }
+[ \t\n]+                /* skip whitespace */
+<<EOF>>                 return 0;
+%%
+int main() { return yylex(); }
+`
+
+	It("should import rules, definitions, and start conditions (i.e. Parse)", func() {
+		spec, err := flex.Parse(strings.NewReader(calc))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.Rules).To(HaveLen(7))
+		Expect(spec.Rules[0]).To(Equal(rulespec.Rule{Token: "NUMBER", Pattern: `(?:[0-9])+(\.(?:[0-9])+)?`}))
+		Expect(spec.Rules[3]).To(Equal(rulespec.Rule{Token: "OP", Pattern: `\*\*`}))
+		Expect(spec.Rules[5]).To(Equal(rulespec.Rule{Token: "QUOTE", Pattern: `\"`, Begin: "STR"}))
+		Expect(spec.Rules[6]).To(Equal(rulespec.Rule{Pattern: `[ \t\n]+`}))
+		Expect(spec.Modes).To(Equal([]rulespec.Mode{{Name: "STR", Exclusive: true, Rules: []rulespec.Rule{
+			{Token: "TEXT", Pattern: `[^"\n]+`},
+			{Token: "QUOTE", Pattern: `\"`, Begin: "INITIAL"},
+		}}}))
+	})
+
+	It("should lex the input as the imported rules do (i.e. Parse)", func() {
+		spec, err := flex.Parse(strings.NewReader(calc))
+		Expect(err).NotTo(HaveOccurred())
+		state, types, err := spec.Compile()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize(`if x1 ** 2.5 "a b"`, state)
+		Expect(err).NotTo(HaveOccurred())
+		var values []string
+		for _, t := range tokens {
+			Expect(t.Type).To(BeElementOf(types["KEYWORD"], types["IDENT"], types["OP"], types["NUMBER"], types["QUOTE"], types["TEXT"]))
+			values = append(values, t.Value.(string))
+		}
+		Expect(values).To(Equal([]string{"if", "x1", "**", "2.5", `"`, "a b", `"`}))
+	})
+
+	It("should import trailing context (i.e. Parse)", func() {
+		spec, err := flex.Parse(strings.NewReader("%%\n[a-z]+/\"(\" return CALL;\n[a-z]+ return IDENT;\n\"(\" return OP;\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.Rules[0]).To(Equal(rulespec.Rule{Token: "CALL", Pattern: `[a-z]+`, Lookahead: `\(`}))
+		state, types, err := spec.Compile()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize("f(", state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(2))
+		Expect(tokens[0].Type).To(Equal(types["CALL"]))
+		Expect(tokens[0].Value).To(Equal("f"))
+	})
+
+	It("should report unsupported specifications (i.e. Parse)", func() {
+		_, err := flex.Parse(strings.NewReader("%%\na/b/c return A;\n"))
+		Expect(err).To(MatchError(`flex: line 2: multiple trailing contexts in pattern "a/b/c"`))
+		_, err = flex.Parse(strings.NewReader("%%\n<S>a return A;\n"))
+		Expect(err).To(MatchError(`flex: line 2: undeclared start condition "S"`))
+		_, err = flex.Parse(strings.NewReader("%%\n{X} return A;\n"))
+		Expect(err).To(MatchError(`flex: line 2: undefined name "X"`))
+		_, err = flex.Parse(strings.NewReader("D [0-9]\n"))
+		Expect(err).To(MatchError(`flex: line 1: expected %%`))
+	})
+})
@@ -0,0 +1,273 @@
+package lexer
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"text/template"
+)
+
+// Generate writes the source of a standalone Go lexer, in the specified package, emitting
+// the same tokens as the state returned by State: a DFA compiled from the rules as tables,
+// and a driver depending only on the standard library.
+//
+// The generated token types are named after the names registered for them (see
+// RegisterTokenType), if they are valid identifiers not otherwise declared or used by the
+// generated lexer (e.g. Token, Error, or Lex) or by other token types, and otherwise
+// numbered, e.g. Token3. Returns an error if the table is not valid, has modes, contains a
+// Matcher or a rule beginning a mode, with an action, or with trailing context, emits a
+// negative token type, or cannot be compiled into a DFA.
+func (r *Rules) Generate(w io.Writer, pkg string) error {
+	rules, err := r.compile()
+	if err != nil {
+		return err
+	}
+	if err := validate(rules); err != nil {
+		return err
+	}
+	if len(r.modes) > 0 {
+		return fmt.Errorf("lexer: rules with modes cannot be generated")
+	}
+	for i, u := range rules {
+		if u.match != nil {
+			return fmt.Errorf("lexer: rule %d is a matcher and cannot be generated", i)
+		}
+		if u.begin {
+			return fmt.Errorf("lexer: rule %d begins a mode and cannot be generated", i)
+		}
+		if u.action != nil {
+			return fmt.Errorf("lexer: rule %d has an action and cannot be generated", i)
+		}
+		if u.pattern.trailing != "" {
+			return fmt.Errorf("lexer: rule %d has trailing context and cannot be generated", i)
+		}
+	}
+	d, ok := compileDFA(rules)
+	if !ok {
+		return fmt.Errorf("lexer: rules cannot be compiled into a DFA")
+	}
+	data := generatedLexer{Package: pkg}
+	seen := map[TokenType]bool{}
+	declared := map[string]bool{}
+	for _, name := range generatedIdentifiers {
+		declared[name] = true
+	}
+	for i, u := range rules {
+		if u.skip || seen[u.tokenType] {
+			continue
+		}
+		if u.tokenType < 0 {
+			// The generated lexer uses negative token types as sentinels.
+			return fmt.Errorf("lexer: rule %d emits a negative token type and cannot be generated", i)
+		}
+		seen[u.tokenType] = true
+		name, ok := u.tokenType.name()
+		if !ok || !token.IsIdentifier(name) || declared[name] {
+			name = fmt.Sprintf("Token%d", u.tokenType)
+		}
+		if declared[name] {
+			return fmt.Errorf("lexer: token type %d cannot be named %s, which is already declared", u.tokenType, name)
+		}
+		declared[name] = true
+		data.Types = append(data.Types, generatedType{name, int(u.tokenType)})
+	}
+	for _, s := range d.states {
+		state := generatedState{Accept: -1}
+		if s.accept >= 0 {
+			if u := rules[s.accept]; !u.skip {
+				state.Accept = int(u.tokenType)
+			} else {
+				state.Skip = true
+			}
+		}
+		for c, next := range s.ascii {
+			if next >= 0 {
+				state.ASCII = append(state.ASCII, [2]int{c, int(next) + 1})
+			}
+		}
+		for _, t := range s.ranges {
+			state.Ranges = append(state.Ranges, generatedRange{int(t.lo), int(t.hi), int(t.next) + 1})
+		}
+		data.States = append(data.States, state)
+	}
+	var source bytes.Buffer
+	if err := generatedLexerTemplate.Execute(&source, data); err != nil {
+		return err
+	}
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// generatedIdentifiers are the identifiers declared or used by the generated lexer at
+// package scope, which the token types cannot be named after.
+var generatedIdentifiers = []string{
+	"Error", "Lex", "Token", "TokenType", "append", "error", "fmt", "int", "len", "lexASCII",
+	"lexAccept", "lexMatch", "lexRange", "lexRanges", "lexStep", "nil", "noToken", "rune",
+	"skipToken", "string", "uint16", "utf8",
+}
+
+type generatedLexer struct {
+	Package string
+	Types   []generatedType
+	States  []generatedState
+}
+
+type generatedType struct {
+	Name  string
+	Value int
+}
+
+type generatedState struct {
+	Accept int
+	Skip   bool
+	ASCII  [][2]int
+	Ranges []generatedRange
+}
+
+type generatedRange struct {
+	Lo, Hi, Next int
+}
+
+var generatedLexerTemplate = template.Must(template.New("lexer").Parse(`// Code generated by lexgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// TokenType identifies the type of a token.
+type TokenType int
+
+const (
+{{- range .Types}}
+	{{.Name}} TokenType = {{.Value}}
+{{- end}}
+)
+
+// Token is a token of the input.
+type Token struct {
+	Type     TokenType
+	Value    string
+	Position int
+	Line     int
+	Column   int
+}
+
+// Error is the error returned by Lex for input no rule matches.
+type Error struct {
+	Message  string
+	Position int
+	Line     int
+	Column   int
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+const (
+	noToken   = -1
+	skipToken = -2
+)
+
+// lexAccept is the token type accepted by each state.
+var lexAccept = [...]int{
+{{- range $i, $s := .States}}
+	{{if $s.Skip}}skipToken{{else if lt $s.Accept 0}}noToken{{else}}{{$s.Accept}}{{end}},
+{{- end}}
+}
+
+// lexASCII is the state following each state on each ASCII rune, plus one.
+var lexASCII = [...][utf8.RuneSelf]uint16{
+{{- range $i, $s := .States}}
+	{ {{- range $s.ASCII}}{{index . 0}}: {{index . 1}}, {{end -}} },
+{{- end}}
+}
+
+type lexRange struct {
+	lo, hi rune
+	next   uint16
+}
+
+// lexRanges is the state following each state on ranges of non-ASCII runes, plus one.
+var lexRanges = [...][]lexRange{
+{{- range $i, $s := .States}}
+	{ {{- range $s.Ranges}}{ {{- .Lo}}, {{.Hi}}, {{.Next -}} }, {{end -}} },
+{{- end}}
+}
+
+// Lex returns the tokens of the input, applying the rule matching the longest prefix of the
+// input at each position. Returns the tokens lexed so far and an *Error at the first input
+// no rule matches.
+func Lex(input string) ([]Token, error) {
+	var tokens []Token
+	line, column := 1, 1
+	for position := 0; position < len(input); {
+		tokenType, length := lexMatch(input[position:])
+		if length == 0 {
+			r, _ := utf8.DecodeRuneInString(input[position:])
+			return tokens, &Error{fmt.Sprintf("unexpected %q", r), position, line, column}
+		}
+		value := input[position : position+length]
+		if tokenType != skipToken {
+			tokens = append(tokens, Token{TokenType(tokenType), value, position, line, column})
+		}
+		for _, r := range value {
+			if r == '\n' {
+				line, column = line+1, 1
+			} else {
+				column++
+			}
+		}
+		position += length
+	}
+	return tokens, nil
+}
+
+func lexMatch(input string) (int, int) {
+	state, tokenType, length := 0, noToken, 0
+	for i := 0; i < len(input); {
+		var next uint16
+		if c := input[i]; c < utf8.RuneSelf {
+			next = lexASCII[state][c]
+			i++
+		} else {
+			r, w := utf8.DecodeRuneInString(input[i:])
+			next = lexStep(lexRanges[state], r)
+			i += w
+		}
+		if next == 0 {
+			break
+		}
+		state = int(next) - 1
+		if accept := lexAccept[state]; accept != noToken {
+			tokenType, length = accept, i
+		}
+	}
+	return tokenType, length
+}
+
+func lexStep(ranges []lexRange, r rune) uint16 {
+	lo, hi := 0, len(ranges)
+	for lo < hi {
+		m := (lo + hi) / 2
+		switch {
+		case r < ranges[m].lo:
+			hi = m
+		case r > ranges[m].hi:
+			lo = m + 1
+		default:
+			return ranges[m].next
+		}
+	}
+	return 0
+}
+`))
@@ -0,0 +1,79 @@
+package lexer_test
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const (
+		Number lexer.TokenType = iota + 9000
+		Operator
+		Plus
+	)
+
+	BeforeEach(func() {
+		lexer.RegisterTokenType(Number, "NUMBER")
+	})
+
+	It("should generate the source of a standalone lexer (i.e. Generate)", func() {
+		var source bytes.Buffer
+		err := lexer.NewRules().
+			Skip(lexer.Class(`\s`)).
+			Add(Number, lexer.Regexp(`\d+`)).
+			Add(Operator, lexer.Regexp(`[-+]`)).
+			Generate(&source, "calc")
+		Expect(err).NotTo(HaveOccurred())
+		f, err := parser.ParseFile(token.NewFileSet(), "lexer.go", source.Bytes(), 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Name.Name).To(Equal("calc"))
+		for _, imported := range f.Imports {
+			Expect(imported.Path.Value).To(BeElementOf(`"fmt"`, `"unicode/utf8"`))
+		}
+		Expect(source.String()).To(HavePrefix("// Code generated by lexgen. DO NOT EDIT."))
+		Expect(source.String()).To(ContainSubstring("NUMBER    TokenType = 9000"))
+		Expect(source.String()).To(ContainSubstring("Token9001 TokenType = 9001"))
+		Expect(source.String()).To(ContainSubstring("func Lex(input string) ([]Token, error)"))
+	})
+
+	It("should number token types named after identifiers of the generated lexer (i.e. Generate)", func() {
+		lexer.RegisterTokenType(Plus, "Token")
+		var source bytes.Buffer
+		err := lexer.NewRules().
+			Add(Number, lexer.Regexp(`\d+`)).
+			Add(Plus, lexer.Regexp(`\+`)).
+			Generate(&source, "calc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source.String()).To(ContainSubstring("Token9002 TokenType = 9002"))
+
+		lexer.RegisterTokenType(Plus, "Token9001")
+		source.Reset()
+		err = lexer.NewRules().
+			Add(Plus, lexer.Regexp(`\+`)).
+			Add(Operator, lexer.Regexp(`-`)).
+			Generate(&source, "calc")
+		Expect(err).To(MatchError("lexer: token type 9001 cannot be named Token9001, which is already declared"))
+	})
+
+	It("should not generate rules emitting negative token types (i.e. Generate)", func() {
+		var source bytes.Buffer
+		err := lexer.NewRules().Add(lexer.TokenText, lexer.Regexp(`\w+`)).Generate(&source, "calc")
+		Expect(err).To(MatchError("lexer: rule 0 emits a negative token type and cannot be generated"))
+		Expect(source.Len()).To(BeZero())
+	})
+
+	It("should not generate rules that cannot be compiled (i.e. Generate)", func() {
+		var source bytes.Buffer
+		err := lexer.NewRules().Match(func(l *lexer.Lexer) bool { return false }).Generate(&source, "calc")
+		Expect(err).To(MatchError("lexer: rule 0 is a matcher and cannot be generated"))
+		err = lexer.NewRules().Add(Number, lexer.Regexp(`\b\d`)).Generate(&source, "calc")
+		Expect(err).To(MatchError("lexer: rules cannot be compiled into a DFA"))
+		Expect(source.Len()).To(BeZero())
+	})
+})
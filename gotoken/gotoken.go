@@ -0,0 +1,88 @@
+// Package gotoken bridges tokens emitted by a lexer to go/token tokens and positions, so
+// that projects embedding Go-like sub-languages can reuse go/parser-style infrastructure
+// (token.FileSet, scanner.ErrorList) downstream.
+package gotoken
+
+import (
+	"go/scanner"
+	"go/token"
+
+	"github.com/eczarny/lexer"
+)
+
+var operators = map[string]token.Token{}
+
+func init() {
+	for t := token.ADD; t <= token.TILDE; t++ {
+		if t.IsOperator() {
+			operators[t.String()] = t
+		}
+	}
+}
+
+// Bridge maps the tokens emitted by a lexer over one input to go/token tokens, and their
+// positions to positions in a token.File added to a file set for that input.
+type Bridge struct {
+	file   *token.File
+	tokens map[lexer.TokenType]token.Token
+}
+
+// NewBridge creates a bridge for the input, adding a file with the specified name to the
+// file set, which maps tokens of each type to the Go token it is mapped to.
+//
+// Tokens of types that are not mapped are identified by their value, which must be a Go
+// keyword, operator, or identifier.
+func NewBridge(fset *token.FileSet, filename string, input string, tokens map[lexer.TokenType]token.Token) *Bridge {
+	file := fset.AddFile(filename, -1, len(input))
+	file.SetLinesForContent([]byte(input))
+	return &Bridge{file: file, tokens: tokens}
+}
+
+// File returns the file the positions of the bridged tokens are in.
+func (b *Bridge) File() *token.File {
+	return b.file
+}
+
+// Pos returns the file set position of a position in the input.
+func (b *Bridge) Pos(p lexer.RunePosition) token.Pos {
+	return b.file.Pos(int(p))
+}
+
+// Token returns the position, Go token, and literal string of the token, with the same
+// meaning as the results of go/scanner.Scanner.Scan.
+//
+// Error tokens, and tokens that cannot be mapped to a Go token, are token.ILLEGAL.
+func (b *Bridge) Token(t lexer.Token) (pos token.Pos, tok token.Token, lit string) {
+	pos = b.Pos(t.Position)
+	lit, _ = t.Value.(string)
+	if t.Type == lexer.TokenError || t.Type == lexer.TokenWarning {
+		if e, ok := t.LexError(); ok {
+			lit = e.Error()
+		}
+		return pos, token.ILLEGAL, lit
+	}
+	tok, ok := b.tokens[t.Type]
+	if !ok {
+		if tok, ok = operators[lit]; !ok {
+			if tok = token.Lookup(lit); !token.IsIdentifier(lit) && !tok.IsKeyword() {
+				tok = token.ILLEGAL
+			}
+		}
+	}
+	switch {
+	case tok.IsLiteral(), tok.IsKeyword(), tok == token.COMMENT, tok == token.SEMICOLON, tok == token.ILLEGAL:
+		return pos, tok, lit
+	}
+	return pos, tok, ""
+}
+
+// Error returns the diagnostic carried by an error or warning token as a go/scanner error,
+// so that diagnostics can be collected in a scanner.ErrorList. Returns false if the token
+// is neither an error nor a warning.
+func (b *Bridge) Error(t lexer.Token) (*scanner.Error, bool) {
+	e, ok := t.LexError()
+	if !ok {
+		return nil, false
+	}
+	return &scanner.Error{Pos: b.file.Position(b.Pos(t.Position)), Msg: e.Error()}, true
+}
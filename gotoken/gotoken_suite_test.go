@@ -0,0 +1,13 @@
+package gotoken_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGotoken(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gotoken Suite")
+}
@@ -0,0 +1,107 @@
+package gotoken_test
+
+import (
+	"go/scanner"
+	"go/token"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/gotoken"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	Ident lexer.TokenType = iota
+	Number
+	Punct
+)
+
+var _ = Describe("Gotoken", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case unicode.IsLetter(r):
+				l.NextUpTo(func(r rune) bool {
+					return !unicode.IsLetter(r)
+				})
+				l.Emit(Ident)
+			case unicode.IsDigit(r):
+				l.NextUpTo(func(r rune) bool {
+					return !unicode.IsDigit(r)
+				})
+				l.Emit(Number)
+			case r == '$':
+				return l.Errorf("unexpected %q", r)
+			default:
+				l.NextUpTo(func(r rune) bool {
+					return unicode.IsSpace(r) || unicode.IsLetter(r) || unicode.IsDigit(r) || r == lexer.EOF
+				})
+				l.Emit(Punct)
+			}
+		}
+	}
+
+	type scanned struct {
+		pos token.Position
+		tok token.Token
+		lit string
+	}
+
+	scan := func(fset *token.FileSet, b *gotoken.Bridge, input string) []scanned {
+		var results []scanned
+		for t := range lexer.NewLexer(input, state).All() {
+			pos, tok, lit := b.Token(t)
+			results = append(results, scanned{fset.Position(pos), tok, lit})
+		}
+		return results
+	}
+
+	It("should map tokens to Go tokens and positions (i.e. Token)", func() {
+		input := "if x\n  := 42"
+		fset := token.NewFileSet()
+		b := gotoken.NewBridge(fset, "a.go", input, map[lexer.TokenType]token.Token{Number: token.INT})
+		Expect(scan(fset, b, input)).To(Equal([]scanned{
+			{token.Position{Filename: "a.go", Offset: 0, Line: 1, Column: 1}, token.IF, "if"},
+			{token.Position{Filename: "a.go", Offset: 3, Line: 1, Column: 4}, token.IDENT, "x"},
+			{token.Position{Filename: "a.go", Offset: 7, Line: 2, Column: 3}, token.DEFINE, ""},
+			{token.Position{Filename: "a.go", Offset: 10, Line: 2, Column: 6}, token.INT, "42"},
+		}))
+	})
+
+	It("should map positions of several inputs into one file set (i.e. Pos and File)", func() {
+		fset := token.NewFileSet()
+		a := gotoken.NewBridge(fset, "a.go", "x", nil)
+		b := gotoken.NewBridge(fset, "b.go", "y\nz", nil)
+		Expect(fset.Position(b.Pos(2)).String()).To(Equal("b.go:2:1"))
+		Expect(fset.File(a.Pos(0))).To(BeIdenticalTo(a.File()))
+		Expect(b.File().Name()).To(Equal("b.go"))
+	})
+
+	It("should map error tokens and unknown tokens as illegal (i.e. Token and Error)", func() {
+		input := "@ $"
+		fset := token.NewFileSet()
+		b := gotoken.NewBridge(fset, "a.go", input, nil)
+		tokens, err := lexer.ReadTokens(lexer.NewLexer(input, state))
+		Expect(err).NotTo(HaveOccurred())
+		_, tok, lit := b.Token(tokens[0])
+		Expect(tok).To(Equal(token.ILLEGAL))
+		Expect(lit).To(Equal("@"))
+		_, tok, lit = b.Token(tokens[1])
+		Expect(tok).To(Equal(token.ILLEGAL))
+		Expect(lit).To(Equal(`unexpected '$'`))
+
+		var errors scanner.ErrorList
+		e, ok := b.Error(tokens[1])
+		Expect(ok).To(BeTrue())
+		errors = append(errors, e)
+		Expect(errors.Err()).To(MatchError(`a.go:1:3: unexpected '$'`))
+		_, ok = b.Error(tokens[0])
+		Expect(ok).To(BeFalse())
+	})
+})
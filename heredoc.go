@@ -0,0 +1,162 @@
+package lexer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// HeredocIndent determines how the indentation of a heredoc is handled.
+type HeredocIndent int
+
+const (
+	// HeredocVerbatim preserves the body of a heredoc introduced by <<; its delimiter line
+	// must not be indented.
+	HeredocVerbatim HeredocIndent = iota
+
+	// HeredocStripTabs strips the leading tabs from each line of the body, and from the
+	// delimiter line, of a heredoc introduced by <<- (e.g. the shell).
+	HeredocStripTabs
+
+	// HeredocDedent removes the indentation common to the non-blank lines of the body of a
+	// heredoc introduced by <<~, whose delimiter line may be indented (e.g. Ruby).
+	HeredocDedent
+)
+
+// Heredoc is a heredoc introduced by an operator consumed by AcceptHeredoc.
+type Heredoc struct {
+	Delimiter string
+	Indent    HeredocIndent
+
+	// Quoted is true if the delimiter was quoted, e.g. <<'EOF', which in many languages
+	// disables interpolation in the body.
+	Quoted bool
+}
+
+// AcceptHeredoc consumes a heredoc operator at the current position, << followed by an
+// optional - or ~ and a delimiter identifier, optionally quoted (e.g. <<-'EOF'), and
+// returns the heredoc it introduces. Returns false, consuming nothing, if there is none.
+//
+// The body of the heredoc is not consumed; the rest of the line is lexed as usual and the
+// bodies of the heredocs introduced on it are scanned by ScanHeredocs.
+func (l *Lexer) AcceptHeredoc() (Heredoc, bool) {
+	rest := l.Input[l.CurrentPosition:]
+	if !strings.HasPrefix(rest, "<<") {
+		return Heredoc{}, false
+	}
+	var h Heredoc
+	i := 2
+	if i < len(rest) && (rest[i] == '-' || rest[i] == '~') {
+		h.Indent = map[byte]HeredocIndent{'-': HeredocStripTabs, '~': HeredocDedent}[rest[i]]
+		i++
+	}
+	var quote byte
+	if i < len(rest) && (rest[i] == '\'' || rest[i] == '"') {
+		quote, h.Quoted = rest[i], true
+		i++
+	}
+	start := i
+	for i < len(rest) {
+		r, w := utf8.DecodeRuneInString(rest[i:])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			break
+		}
+		i += w
+	}
+	h.Delimiter = rest[start:i]
+	if h.Quoted {
+		if i >= len(rest) || rest[i] != quote {
+			return Heredoc{}, false
+		}
+		i++
+	}
+	if h.Delimiter == "" {
+		return Heredoc{}, false
+	}
+	l.advance(i)
+	l.heredocs = append(l.heredocs, h)
+	return h, true
+}
+
+// ScanHeredocs consumes the bodies of the heredocs introduced since the last call, in
+// order, emitting a token of the specified type for each with its body as value. It should
+// be called once the line break ending the line of their operators has been consumed, and
+// does nothing if no heredoc is pending.
+//
+// Each body extends up to, but not including, a line consisting of its delimiter; the
+// delimiter line is consumed with it. Emits an error token, and returns false, if the end
+// of the input is encountered first.
+func (l *Lexer) ScanHeredocs(tokenType TokenType) bool {
+	pending := l.heredocs
+	l.heredocs = nil
+	for _, h := range pending {
+		body, ok := l.scanHeredoc(h)
+		if !ok {
+			l.report(l.lexError(SeverityError, "", "unterminated heredoc %q", h.Delimiter))
+			return false
+		}
+		t := Token{Type: tokenType, Value: body}
+		if l.lossless {
+			t.Raw = l.Input[l.startPosition:l.CurrentPosition]
+		}
+		l.send(t)
+		l.startPosition = l.CurrentPosition
+	}
+	return true
+}
+
+func (l *Lexer) scanHeredoc(h Heredoc) (string, bool) {
+	var lines []string
+	for position := int(l.CurrentPosition); position < len(l.Input); {
+		end := strings.IndexByte(l.Input[position:], '\n') + 1
+		if end == 0 {
+			end = len(l.Input) - position
+		}
+		line := l.Input[position : position+end]
+		if isHeredocDelimiter(h, strings.TrimRight(line, "\r\n")) {
+			l.advance(position + end - int(l.CurrentPosition))
+			return dedentHeredoc(h, lines), true
+		}
+		lines = append(lines, line)
+		position += end
+	}
+	l.CurrentPosition = RunePosition(len(l.Input))
+	return "", false
+}
+
+func isHeredocDelimiter(h Heredoc, line string) bool {
+	switch h.Indent {
+	case HeredocStripTabs:
+		line = strings.TrimLeft(line, "\t")
+	case HeredocDedent:
+		line = strings.TrimLeft(line, " \t")
+	}
+	return line == h.Delimiter
+}
+
+func dedentHeredoc(h Heredoc, lines []string) string {
+	switch h.Indent {
+	case HeredocStripTabs:
+		for i, line := range lines {
+			lines[i] = strings.TrimLeft(line, "\t")
+		}
+	case HeredocDedent:
+		indent := -1
+		for _, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if n := len(line) - len(strings.TrimLeft(line, " \t")); indent < 0 || n < indent {
+				indent = n
+			}
+		}
+		for i, line := range lines {
+			if n := len(line) - len(strings.TrimLeft(line, " \t")); n < indent {
+				lines[i] = line[n:]
+			} else if indent > 0 {
+				lines[i] = line[indent:]
+			}
+		}
+	}
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,72 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Heredoc", func() {
+	const (
+		Word lexer.TokenType = iota
+		Operator
+		Newline
+		Body
+	)
+
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case r == '\n':
+				l.Next()
+				l.Emit(Newline)
+				if !l.ScanHeredocs(Body) {
+					return nil
+				}
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case r == '<':
+				if _, ok := l.AcceptHeredoc(); !ok {
+					l.Next()
+				}
+				l.Emit(Operator)
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Word)
+			}
+		}
+	}
+
+	values := func(tokens []lexer.Token) []interface{} {
+		var values []interface{}
+		for _, t := range tokens {
+			values = append(values, t.Value)
+		}
+		return values
+	}
+
+	It("should scan the bodies of heredocs after the line introducing them (i.e. AcceptHeredoc and ScanHeredocs)", func() {
+		tokens, err := lexer.Tokenize("cat <<A <<'B' x\na\nA\nb\n  B\nB\necho\n", state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values(tokens)).To(Equal([]interface{}{"cat", "<<A", "<<'B'", "x", "\n", "a\n", "b\n  B\n", "echo", "\n"}))
+		Expect(tokens[5]).To(Equal(lexer.Token{Type: Body, Value: "a\n", Position: 16, Line: 2, Column: 1}))
+	})
+
+	It("should strip the indentation of heredocs (i.e. HeredocStripTabs and HeredocDedent)", func() {
+		tokens, err := lexer.Tokenize("<<-A\n\ta\n\t\tb\n\tA\n<<~B\n    c\n\n      d\n  B\n", state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values(tokens)).To(Equal([]interface{}{"<<-A", "\n", "a\nb\n", "<<~B", "\n", "c\n\n  d\n"}))
+	})
+
+	It("should report unterminated heredocs (i.e. ScanHeredocs)", func() {
+		_, err := lexer.Tokenize("<<EOF\na\nEO", state)
+		Expect(err).To(MatchError(`unterminated heredoc "EOF"`))
+		_, err = lexer.Tokenize(`<<"EOF`+"\n", state)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
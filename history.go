@@ -0,0 +1,32 @@
+package lexer
+
+// WithHistory retains the specified number of tokens most recently returned by NextToken,
+// and friends, for History. At least two tokens are always retained.
+func WithHistory(depth int) Option {
+	return func(l *Lexer) {
+		if depth < 2 {
+			depth = 2
+		}
+		l.history = make([]Token, depth)
+	}
+}
+
+// History returns up to n of the tokens most recently returned by NextToken, oldest first,
+// including the token most recently returned.
+//
+// The number of tokens retained is configured with WithHistory.
+func (l *Lexer) History(n int) []Token {
+	l.tokenMutex.Lock()
+	defer l.tokenMutex.Unlock()
+	if n > l.historyLength {
+		n = l.historyLength
+	}
+	if n <= 0 {
+		return nil
+	}
+	tokens := make([]Token, n)
+	for i := range tokens {
+		tokens[i] = l.history[(l.historyStart+l.historyLength-n+i)%len(l.history)]
+	}
+	return tokens
+}
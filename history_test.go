@@ -0,0 +1,47 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("History", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for l.Peek() != lexer.EOF {
+			l.Next()
+			l.Emit(Token)
+		}
+		return nil
+	}
+
+	values := func(tokens []lexer.Token) []interface{} {
+		var values []interface{}
+		for _, t := range tokens {
+			values = append(values, t.Value)
+		}
+		return values
+	}
+
+	It("should return the most recently returned tokens (i.e. History)", func() {
+		l := lexer.NewLexer("abcdef", state, lexer.WithHistory(4), lexer.WithSynchronous())
+		Expect(l.History(3)).To(BeEmpty())
+		l.NextToken()
+		l.NextToken()
+		Expect(values(l.History(3))).To(Equal([]interface{}{"a", "b"}))
+		l.NextToken()
+		l.NextToken()
+		l.NextToken()
+		Expect(values(l.History(3))).To(Equal([]interface{}{"c", "d", "e"}))
+		Expect(values(l.History(10))).To(Equal([]interface{}{"b", "c", "d", "e"}))
+	})
+
+	It("should retain two tokens by default", func() {
+		l := lexer.NewLexer("abc", state, lexer.WithSynchronous())
+		l.NextToken()
+		l.NextToken()
+		l.NextToken()
+		Expect(values(l.History(3))).To(Equal([]interface{}{"b", "c"}))
+	})
+})
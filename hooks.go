@@ -0,0 +1,25 @@
+package lexer
+
+// OnEmit registers a callback invoked with every token emitted by the lexer from now on, as
+// transformed by any middleware already in use (see Use), e.g. to collect metrics or drive
+// a live visualization.
+func (l *Lexer) OnEmit(hook func(Token)) {
+	l.Use(func(t Token) []Token {
+		hook(t)
+		return []Token{t}
+	})
+}
+
+// OnError registers a callback invoked with the diagnostic of every error token emitted by
+// the lexer from now on, before the token is transformed by any middleware.
+func (l *Lexer) OnError(hook func(LexError)) {
+	l.middlewareMutex.Lock()
+	defer l.middlewareMutex.Unlock()
+	l.onError = append(l.onError[:len(l.onError):len(l.onError)], hook)
+}
+
+func (l *Lexer) errorHooks() []func(LexError) {
+	l.middlewareMutex.RLock()
+	defer l.middlewareMutex.RUnlock()
+	return l.onError
+}
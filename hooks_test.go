@@ -0,0 +1,43 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hooks", func() {
+	var state lexer.StateFunc
+	state = func(l *lexer.Lexer) lexer.StateFunc {
+		switch r := l.Peek(); r {
+		case lexer.EOF:
+			return nil
+		case '?':
+			l.ErrorCodef("E1", "unexpected %q", r)
+			l.Ignore()
+		default:
+			l.Next()
+			l.Emit(Token)
+		}
+		return state
+	}
+
+	It("should invoke the callbacks on every emitted token and error (i.e. OnEmit and OnError)", func() {
+		l := lexer.NewLexer("a?b", state, lexer.WithSynchronous(), lexer.WithErrorFormatter(func(e lexer.LexError) string {
+			return "error: " + e.Error()
+		}))
+		var emitted []interface{}
+		var errors []lexer.LexError
+		l.OnEmit(func(t lexer.Token) {
+			emitted = append(emitted, t.Value)
+		})
+		l.OnError(func(e lexer.LexError) {
+			errors = append(errors, e)
+		})
+		for range l.All() {
+		}
+		Expect(emitted).To(Equal([]interface{}{"a", "error: E1 unexpected '?'", "b"}))
+		Expect(errors).To(Equal([]lexer.LexError{{Code: "E1", Message: "unexpected '?'", Position: 1, Severity: lexer.SeverityError}}))
+	})
+})
@@ -0,0 +1,72 @@
+package lexer
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// RenderHTML writes the input to w as HTML, wrapping the text of each token, and of its
+// trivia, in a span whose class is the Pygments CSS class of the token's category (e.g.
+// <span class="kd">var</span>), so that stylesheets written for Pygments or chroma apply.
+//
+// The text of a token is taken from the input: its raw text, its value if the input
+// contains it at the token's position, or else the input up to the next token, excluding
+// trailing whitespace. Input not covered by any token, such as runes skipped without
+// WithTrivia, is written unwrapped, so the original whitespace is preserved, and tokens
+// overlapping the text already written are skipped. Tokens whose category has no class are
+// written unwrapped as well.
+func RenderHTML(w io.Writer, input string, tokens []Token, categories CategoryMap) error {
+	flattened := flattenTrivia(tokens)
+	cursor := 0
+	for i, t := range flattened {
+		p := int(t.Position)
+		if p < cursor || p > len(input) {
+			continue
+		}
+		end := sourceEnd(input, flattened[i+1:], t.Token)
+		if end == p {
+			continue
+		}
+		if _, err := io.WriteString(w, html.EscapeString(input[cursor:p])); err != nil {
+			return err
+		}
+		text := html.EscapeString(input[p:end])
+		var err error
+		if class := categories.Categorize(t.Type).Class(); class != "" {
+			_, err = fmt.Fprintf(w, `<span class="%s">%s</span>`, class, text)
+		} else {
+			_, err = io.WriteString(w, text)
+		}
+		if err != nil {
+			return err
+		}
+		cursor = end
+	}
+	if cursor < len(input) {
+		_, err := io.WriteString(w, html.EscapeString(input[cursor:]))
+		return err
+	}
+	return nil
+}
+
+// sourceEnd returns the end of the text of the token in the input, followed by the tokens.
+func sourceEnd(input string, following []flatToken, t Token) int {
+	p := int(t.Position)
+	if t.Raw != "" {
+		return min(p+len(t.Raw), len(input))
+	}
+	if value, ok := t.Value.(string); ok && strings.HasPrefix(input[p:], value) {
+		return p + len(value)
+	}
+	end := len(input)
+	for _, next := range following {
+		if n := int(next.Position); n > p && n <= len(input) {
+			end = n
+			break
+		}
+	}
+	return p + len(strings.TrimRightFunc(input[p:end], unicode.IsSpace))
+}
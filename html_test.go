@@ -0,0 +1,116 @@
+package lexer_test
+
+import (
+	"bytes"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTML rendering", func() {
+	const (
+		Keyword lexer.TokenType = iota + 103
+		Identifier
+		Operator
+	)
+
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case r == '#':
+				l.NextUpTo(func(r rune) bool {
+					return r == '\n'
+				})
+				l.EmitTrivia(Comment)
+			case r == '<':
+				l.Next()
+				l.Emit(Operator)
+			default:
+				start := l.CurrentPosition
+				l.NextUpTo(func(r rune) bool {
+					return !unicode.IsLetter(r)
+				})
+				if l.Input[start:l.CurrentPosition] == "var" {
+					l.Emit(Keyword)
+				} else {
+					l.Emit(Identifier)
+				}
+			}
+		}
+	}
+
+	categories := lexer.CategoryMap{
+		Keyword:    lexer.CategoryDeclaration,
+		Identifier: lexer.CategoryName,
+		Operator:   lexer.CategoryOperator,
+		Comment:    lexer.CategorySingle,
+	}
+
+	tokens := func(l *lexer.Lexer, n int) []lexer.Token {
+		var tokens []lexer.Token
+		for i := 0; i < n; i++ {
+			tokens = append(tokens, l.NextToken())
+		}
+		return tokens
+	}
+
+	It("should wrap tokens in spans while preserving whitespace (i.e. RenderHTML)", func() {
+		input := "var x <  y # note\n"
+		l := lexer.NewLexer(input, state)
+		var b bytes.Buffer
+		Expect(lexer.RenderHTML(&b, input, tokens(l, 4), categories)).To(Succeed())
+		Expect(b.String()).To(Equal(`<span class="kd">var</span> <span class="n">x</span> <span class="o">&lt;</span>  <span class="n">y</span> # note` + "\n"))
+	})
+
+	It("should render trivia captured by the lexer", func() {
+		input := "x # note\n"
+		l := lexer.NewLexer(input, state, lexer.WithTrivia(lexer.TriviaTrailing))
+		var b bytes.Buffer
+		Expect(lexer.RenderHTML(&b, input, tokens(l, 1), categories)).To(Succeed())
+		Expect(b.String()).To(Equal(`<span class="n">x</span><span class="w"> </span><span class="c1"># note</span><span class="w">` + "\n" + `</span>`))
+	})
+	It("should render the source text of tokens whose value differs from it (i.e. RenderHTML)", func() {
+		input := `x "a\tb" y`
+		l := lexer.NewLexer(input, func(l *lexer.Lexer) lexer.StateFunc {
+			for {
+				switch r := l.Peek(); {
+				case r == lexer.EOF:
+					return nil
+				case r == ' ':
+					l.Ignore()
+				case r == '"':
+					value, err := lexer.ScanString(l, '"', lexer.GoEscapes)
+					if err != nil {
+						return l.ReportError(err)
+					}
+					l.EmitValue(Keyword, value)
+				default:
+					l.Next()
+					l.Emit(Identifier)
+				}
+			}
+		})
+		var b bytes.Buffer
+		Expect(lexer.RenderHTML(&b, input, tokens(l, 3), categories)).To(Succeed())
+		Expect(b.String()).To(Equal(`<span class="n">x</span> <span class="kd">&#34;a\tb&#34;</span> <span class="n">y</span>`))
+	})
+
+	It("should skip tokens overlapping the text already rendered (i.e. RenderHTML)", func() {
+		input := "abc d"
+		overlapping := []lexer.Token{
+			{Type: Identifier, Value: "abc", Position: 0},
+			{Type: Identifier, Value: "bc", Position: 1},
+			{Type: Identifier, Value: "d", Position: 4},
+		}
+		var b bytes.Buffer
+		Expect(lexer.RenderHTML(&b, input, overlapping, categories)).To(Succeed())
+		Expect(b.String()).To(Equal(`<span class="n">abc</span> <span class="n">d</span>`))
+	})
+})
@@ -0,0 +1,122 @@
+package lexer
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenIdentifier represents a type of token that contains an identifier (see
+// ScanIdentifier).
+const TokenIdentifier TokenType = -11
+
+// IdentifierSyntax is the syntax of the identifiers of a language (see ScanIdentifier).
+type IdentifierSyntax struct {
+	// Connectors are the runes other than those of XID_Start allowed to start identifiers,
+	// and like those of XID_Continue allowed to continue them (e.g. "_$" in JavaScript).
+	Connectors string
+
+	// ASCII restricts identifiers to ASCII letters, digits, and connectors.
+	ASCII bool
+}
+
+// defaultIdentifiers is the default identifier syntax of Unicode Standard Annex #31, with
+// '_' allowed to start identifiers.
+var defaultIdentifiers = IdentifierSyntax{Connectors: "_"}
+
+// ScanIdentifier scans an identifier at the current position of the lexer, following the
+// default identifier syntax of Unicode Standard Annex #31: a rune with the XID_Start
+// property, or '_', followed by runes with the XID_Continue property. The identifier is
+// consumed, not emitted; emit it with Emit, or with EmitIdentifier to recognize keywords.
+// Identifiers are not normalized.
+//
+// Returns an error, a LexError, if there is no identifier at the current position.
+func ScanIdentifier(l *Lexer) error {
+	return defaultIdentifiers.Scan(l)
+}
+
+// Scan scans an identifier in the syntax at the current position of the lexer, as
+// ScanIdentifier does.
+func (s IdentifierSyntax) Scan(l *Lexer) error {
+	if !s.IsStart(l.Peek()) {
+		return l.errorAt(l.CurrentPosition, "expected an identifier")
+	}
+	l.Next()
+	for s.IsContinue(l.Peek()) {
+		l.Next()
+	}
+	return nil
+}
+
+// IsStart reports whether the rune may start an identifier in the syntax.
+func (s IdentifierSyntax) IsStart(r rune) bool {
+	if s.isConnector(r) {
+		return true
+	}
+	if s.ASCII {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z'
+	}
+	return isXIDStart(r)
+}
+
+// IsContinue reports whether the rune may continue an identifier in the syntax.
+func (s IdentifierSyntax) IsContinue(r rune) bool {
+	if s.ASCII {
+		return s.IsStart(r) || isDecimal(r)
+	}
+	return s.isConnector(r) || isXIDContinue(r)
+}
+
+func (s IdentifierSyntax) isConnector(r rune) bool {
+	for _, c := range s.Connectors {
+		if r == c {
+			return true
+		}
+	}
+	return false
+}
+
+// isXIDStart reports whether the rune has the XID_Start property, derived as Unicode does
+// from the properties of the unicode package.
+func isXIDStart(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z'
+	}
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start) && !isPattern(r) && !notXIDStart(r)
+}
+
+// isXIDContinue reports whether the rune has the XID_Continue property.
+func isXIDContinue(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || isDecimal(r) || r == '_'
+	}
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue) &&
+		!isPattern(r) && !notXIDContinue(r)
+}
+
+func isPattern(r rune) bool {
+	return unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space)
+}
+
+// notXIDStart reports whether the rune has the ID_Start property but not XID_Start, which
+// excludes the runes whose NFKC normalizations are not identifiers.
+func notXIDStart(r rune) bool {
+	switch r {
+	case 0x0E33, 0x0EB3, 0xFF9E, 0xFF9F:
+		return true
+	}
+	return notXIDContinue(r)
+}
+
+// notXIDContinue reports whether the rune has the ID_Continue property but not
+// XID_Continue.
+func notXIDContinue(r rune) bool {
+	switch {
+	case r == 0x037A, r == 0x309B, r == 0x309C, r == 0xFDFA, r == 0xFDFB:
+		return true
+	case 0xFC5E <= r && r <= 0xFC63:
+		return true
+	case 0xFE70 <= r && r <= 0xFE7E:
+		return r%2 == 0
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Identifier", func() {
+	scan := func(input string, s lexer.IdentifierSyntax) string {
+		l := lexer.NewLexer(input, nil)
+		s.Scan(l)
+		return input[:l.CurrentPosition]
+	}
+
+	It("should scan Unicode identifiers (i.e. ScanIdentifier)", func() {
+		for _, input := range []string{"x", "_x1", "αβγ", "naïve", "日本語", "áb", "ⅷx", "x·y"} {
+			l := lexer.NewLexer(input+"+", nil)
+			Expect(lexer.ScanIdentifier(l)).To(Succeed(), input)
+			Expect(l.Input[:l.CurrentPosition]).To(Equal(input))
+		}
+		for _, input := range []string{"1x", "́", "·", "+", "ﱞ", ""} {
+			l := lexer.NewLexer(input, nil)
+			Expect(lexer.ScanIdentifier(l)).To(MatchError("expected an identifier"), input)
+		}
+		l := lexer.NewLexer("a$b", nil)
+		Expect(lexer.ScanIdentifier(l)).To(Succeed())
+		Expect(l.CurrentPosition).To(Equal(lexer.RunePosition(1)))
+	})
+
+	It("should scan the identifiers of other syntaxes (i.e. IdentifierSyntax)", func() {
+		js := lexer.IdentifierSyntax{Connectors: "_$"}
+		Expect(scan("$a$b", js)).To(Equal("$a$b"))
+		Expect(scan("_x", lexer.IdentifierSyntax{})).To(Equal(""))
+		Expect(scan("x_y", lexer.IdentifierSyntax{})).To(Equal("x_y"))
+		Expect(scan("a-b c", lexer.IdentifierSyntax{Connectors: "-"})).To(Equal("a-b"))
+		Expect(scan("ab1é", lexer.IdentifierSyntax{ASCII: true})).To(Equal("ab1"))
+		Expect(scan("_a_", lexer.IdentifierSyntax{ASCII: true, Connectors: "_"})).To(Equal("_a_"))
+		Expect(js.IsStart('1')).To(BeFalse())
+		Expect(js.IsContinue('1')).To(BeTrue())
+	})
+})
@@ -0,0 +1,24 @@
+package lexer
+
+// TokenIllegal represents a type of token emitted, by lexers created with WithIllegal, for
+// a rune of the input no rule matches.
+const TokenIllegal TokenType = -5
+
+// WithIllegal makes the states of rule tables and mode sets (see Rules and Modes) emit a
+// TokenIllegal token for a rune of the input no rule matches, and continue, instead of
+// emitting an error token and stopping.
+func WithIllegal() Option {
+	return func(l *Lexer) {
+		l.illegal = true
+	}
+}
+
+// unmatched handles the next rune of the input, r, matched by no rule of the state.
+func (l *Lexer) unmatched(r rune, state StateFunc) StateFunc {
+	if !l.illegal {
+		return l.Errorf("unexpected %q", r)
+	}
+	l.Next()
+	l.Emit(TokenIllegal)
+	return state
+}
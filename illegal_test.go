@@ -0,0 +1,39 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Illegal", func() {
+	const Word lexer.TokenType = iota
+
+	It("should emit illegal tokens for input no rule matches and continue (i.e. WithIllegal)", func() {
+		state, err := lexer.NewRules().Skip(lexer.Class(`\s`)).Add(Word, lexer.Class(`\w`)).State()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize("a ?é b", state, lexer.WithIllegal())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(4))
+		Expect(tokens[1]).To(Equal(lexer.Token{Type: lexer.TokenIllegal, Value: "?", Position: 2, Line: 1, Column: 3}))
+		Expect(tokens[2]).To(Equal(lexer.Token{Type: lexer.TokenIllegal, Value: "é", Position: 3, Line: 1, Column: 4}))
+		Expect(tokens[3]).To(EqualToken(lexer.Token{Type: Word, Value: "b"}))
+		Expect(lexer.TokenIllegal.String()).To(Equal("lexer.ILLEGAL"))
+	})
+
+	It("should emit illegal tokens for input no rule of a mode applies to (i.e. WithIllegal)", func() {
+		word := func(l *lexer.Lexer) bool {
+			if l.Peek() != 'a' {
+				return false
+			}
+			l.Next()
+			l.Emit(Word)
+			return true
+		}
+		tokens, err := lexer.Tokenize("a!a", lexer.NewModes(word).State(), lexer.WithIllegal())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(3))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: lexer.TokenIllegal, Value: "!"}))
+	})
+})
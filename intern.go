@@ -0,0 +1,59 @@
+package lexer
+
+import (
+	"strings"
+	"sync"
+)
+
+// Interner maps equal strings to a single shared copy.
+//
+// An interner is safe for concurrent use and may be shared by several lexers so that
+// repeated lexemes across inputs share one string; interned strings do not retain the
+// input they were lexed from.
+type Interner struct {
+	mutex   sync.Mutex
+	strings map[string]string
+}
+
+// NewInterner creates an empty interner.
+func NewInterner() *Interner {
+	return &Interner{strings: make(map[string]string)}
+}
+
+// Intern returns the shared copy of the specified string.
+func (i *Interner) Intern(s string) string {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if interned, ok := i.strings[s]; ok {
+		return interned
+	}
+	interned := strings.Clone(s)
+	i.strings[interned] = interned
+	return interned
+}
+
+// Len returns the number of distinct strings held by the interner.
+func (i *Interner) Len() int {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return len(i.strings)
+}
+
+// WithInterner interns the value of every token of the specified types emitted by Emit,
+// or of every token if no types are specified.
+func WithInterner(interner *Interner, types ...TokenType) Option {
+	return func(l *Lexer) {
+		l.interner = interner
+		l.internTypes = types
+	}
+}
+
+func (l *Lexer) intern(tokenType TokenType, value string) string {
+	if l.interner == nil {
+		return value
+	}
+	if len(l.internTypes) == 0 || containsTokenType(l.internTypes, tokenType) {
+		return l.interner.Intern(value)
+	}
+	return value
+}
@@ -0,0 +1,55 @@
+package lexer_test
+
+import (
+	"unicode"
+	"unsafe"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Interning", func() {
+	const Number lexer.TokenType = 1
+
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case unicode.IsDigit(r):
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Number)
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Token)
+			}
+		}
+	}
+
+	data := func(s interface{}) uintptr {
+		return uintptr(unsafe.Pointer(unsafe.StringData(s.(string))))
+	}
+
+	It("should share one string between repeated lexemes (i.e. WithInterner)", func() {
+		interner := lexer.NewInterner()
+		l := lexer.NewLexer("x y x", state, lexer.WithInterner(interner))
+		x1, _, x2 := l.NextToken(), l.NextToken(), l.NextToken()
+		Expect(x1.Value).To(Equal("x"))
+		Expect(data(x1.Value)).To(Equal(data(x2.Value)))
+		Expect(interner.Len()).To(Equal(2))
+	})
+
+	It("should only intern tokens of the specified types", func() {
+		interner := lexer.NewInterner()
+		l := lexer.NewLexer("x 1 x 1", state, lexer.WithInterner(interner, Token))
+		for i := 0; i < 4; i++ {
+			l.NextToken()
+		}
+		Expect(interner.Len()).To(Equal(1))
+		Expect(interner.Intern("x")).To(Equal("x"))
+	})
+})
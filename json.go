@@ -0,0 +1,148 @@
+package lexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type jsonToken struct {
+	Type     json.RawMessage `json:"type"`
+	Value    json.RawMessage `json:"value"`
+	Raw      string          `json:"raw,omitempty"`
+	Trivia   []Token         `json:"trivia,omitempty"`
+	Position RunePosition    `json:"position"`
+	Line     int             `json:"line"`
+	Column   int             `json:"column"`
+}
+
+// MarshalJSON encodes the token as a JSON object.
+//
+// The type of the token is encoded as its registered name (see RegisterTokenType), or as a
+// number if it has none.
+func (t Token) MarshalJSON() ([]byte, error) {
+	var tokenType interface{} = int(t.Type)
+	if name, ok := t.Type.name(); ok {
+		tokenType = name
+	}
+	typeJSON, err := json.Marshal(tokenType)
+	if err != nil {
+		return nil, err
+	}
+	valueJSON, err := json.Marshal(t.Value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonToken{
+		Type:     typeJSON,
+		Value:    valueJSON,
+		Raw:      t.Raw,
+		Trivia:   t.Trivia,
+		Position: t.Position,
+		Line:     t.Line,
+		Column:   t.Column,
+	})
+}
+
+// UnmarshalJSON decodes a token from a JSON object produced by MarshalJSON.
+//
+// Values of error and warning tokens encoded as objects are decoded as a LexError; other
+// values are decoded as by json.Unmarshal into an interface{}.
+func (t *Token) UnmarshalJSON(data []byte) error {
+	var j jsonToken
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	var tokenType TokenType
+	if bytes.HasPrefix(j.Type, []byte(`"`)) {
+		var name string
+		if err := json.Unmarshal(j.Type, &name); err != nil {
+			return err
+		}
+		var ok bool
+		if tokenType, ok = TokenTypeByName(name); !ok {
+			return fmt.Errorf("lexer: unknown token type %q", name)
+		}
+	} else if err := json.Unmarshal(j.Type, &tokenType); err != nil {
+		return err
+	}
+	var value interface{}
+	if (tokenType == TokenError || tokenType == TokenWarning) && bytes.HasPrefix(j.Value, []byte("{")) {
+		var e LexError
+		if err := json.Unmarshal(j.Value, &e); err != nil {
+			return err
+		}
+		value = e
+	} else if len(j.Value) > 0 {
+		if err := json.Unmarshal(j.Value, &value); err != nil {
+			return err
+		}
+	}
+	*t = Token{
+		Type:     tokenType,
+		Value:    value,
+		Raw:      j.Raw,
+		Trivia:   j.Trivia,
+		Position: j.Position,
+		Line:     j.Line,
+		Column:   j.Column,
+	}
+	return nil
+}
+
+// TokenJSONEncoder writes tokens to an io.Writer as the elements of a JSON array as they are
+// received.
+type TokenJSONEncoder struct {
+	w     io.Writer
+	count int
+}
+
+// NewTokenJSONEncoder creates an encoder that writes to w.
+func NewTokenJSONEncoder(w io.Writer) *TokenJSONEncoder {
+	return &TokenJSONEncoder{w: w}
+}
+
+// Encode writes the token as the next element of the array.
+func (e *TokenJSONEncoder) Encode(t Token) error {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	separator := ","
+	if e.count == 0 {
+		separator = "["
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(b)
+	return err
+}
+
+// Close terminates the array.
+func (e *TokenJSONEncoder) Close() error {
+	terminator := "]\n"
+	if e.count == 0 {
+		terminator = "[]\n"
+	}
+	_, err := io.WriteString(e.w, terminator)
+	return err
+}
+
+// TokenJSONLinesWriter writes tokens to an io.Writer as JSON Lines, one JSON object per
+// token, as they are received.
+type TokenJSONLinesWriter struct {
+	encoder *json.Encoder
+}
+
+// NewTokenJSONLinesWriter creates a writer that writes to w.
+func NewTokenJSONLinesWriter(w io.Writer) *TokenJSONLinesWriter {
+	return &TokenJSONLinesWriter{encoder: json.NewEncoder(w)}
+}
+
+// WriteToken writes the token as a single line.
+func (w *TokenJSONLinesWriter) WriteToken(t Token) error {
+	return w.encoder.Encode(t)
+}
@@ -0,0 +1,69 @@
+package lexer_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON", func() {
+	const Number lexer.TokenType = 101
+
+	BeforeEach(func() {
+		lexer.RegisterTokenType(Number, "NUMBER")
+	})
+
+	It("should encode tokens with their type names (i.e. Token.MarshalJSON)", func() {
+		b, err := json.Marshal(lexer.Token{Type: Number, Value: "2.0", Position: 7, Line: 1, Column: 8})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(`{"type":"NUMBER","value":"2.0","position":7,"line":1,"column":8}`))
+		b, err = json.Marshal(lexer.Token{Type: 7, Value: "x", Trivia: []lexer.Token{{Type: lexer.TokenTrivia, Value: " "}}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(`{"type":7,"value":"x","trivia":[{"type":"lexer.TRIVIA","value":" ","position":0,"line":0,"column":0}],"position":0,"line":0,"column":0}`))
+	})
+
+	It("should decode encoded tokens (i.e. Token.UnmarshalJSON)", func() {
+		tokens := []lexer.Token{
+			{Type: Number, Value: "2.0", Raw: " 2.0", Position: 7, Line: 1, Column: 8},
+			{Type: lexer.TokenError, Value: lexer.LexError{Code: "E1", Message: "m", Position: 3, Severity: lexer.SeverityError}},
+			{Type: 7, Value: "x", Trivia: []lexer.Token{{Type: lexer.TokenTrivia, Value: " "}}},
+		}
+		b, err := json.Marshal(tokens)
+		Expect(err).NotTo(HaveOccurred())
+		var decoded []lexer.Token
+		Expect(json.Unmarshal(b, &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(tokens))
+		var t lexer.Token
+		Expect(json.Unmarshal([]byte(`{"type":"UNKNOWN"}`), &t)).To(MatchError(`lexer: unknown token type "UNKNOWN"`))
+	})
+
+	It("should stream tokens as a JSON array (i.e. TokenJSONEncoder)", func() {
+		var b bytes.Buffer
+		e := lexer.NewTokenJSONEncoder(&b)
+		Expect(e.Close()).To(Succeed())
+		Expect(b.String()).To(Equal("[]\n"))
+		b.Reset()
+		e = lexer.NewTokenJSONEncoder(&b)
+		Expect(e.Encode(lexer.Token{Type: Number, Value: "1"})).To(Succeed())
+		Expect(e.Encode(lexer.Token{Type: Number, Value: "2"})).To(Succeed())
+		Expect(e.Close()).To(Succeed())
+		var decoded []lexer.Token
+		Expect(json.Unmarshal(b.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded).To(HaveLen(2))
+		Expect(decoded[1]).To(EqualToken(lexer.Token{Type: Number, Value: "2"}))
+	})
+
+	It("should write one JSON object per line (i.e. TokenJSONLinesWriter)", func() {
+		var b bytes.Buffer
+		w := lexer.NewTokenJSONLinesWriter(&b)
+		Expect(w.WriteToken(lexer.Token{Type: Number, Value: "1", Line: 1, Column: 1})).To(Succeed())
+		Expect(w.WriteToken(lexer.Token{Type: lexer.TokenError, Value: "Unexpected input", Line: 1, Column: 3})).To(Succeed())
+		Expect(b.String()).To(Equal("" +
+			`{"type":"NUMBER","value":"1","position":0,"line":1,"column":1}` + "\n" +
+			`{"type":"lexer.ERROR","value":"Unexpected input","position":0,"line":1,"column":3}` + "\n"))
+	})
+})
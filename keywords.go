@@ -0,0 +1,181 @@
+package lexer
+
+import "sort"
+
+// maxKeywordDisplacement is the largest displacement tried for a bucket of keywords before
+// falling back to another hash, or to a map.
+const maxKeywordDisplacement = 1 << 16
+
+// KeywordTable reclassifies identifiers as keywords using a perfect hash of the keywords
+// built by Keywords, e.g. after scanning an identifier (see EmitIdentifier). A lookup hashes
+// the word once, unless its length rules it out, and compares it with at most one keyword.
+//
+// Where the length and the first, middle, and last bytes of the keywords tell them apart,
+// only those are hashed, otherwise every byte is.
+type KeywordTable struct {
+	displacements        []uint32
+	keywords             []string
+	types                []TokenType
+	shift                uint
+	sampled              bool
+	minLength, maxLength int
+	fallback             map[string]TokenType
+}
+
+// Keywords builds a table of the keywords and the types of tokens they are lexed as.
+func Keywords(keywords map[string]TokenType) *KeywordTable {
+	if len(keywords) == 0 {
+		// No word is both at least one byte and at most none long.
+		return &KeywordTable{minLength: 1}
+	}
+	if k, ok := buildKeywordTable(keywords, true); ok {
+		return k
+	}
+	if k, ok := buildKeywordTable(keywords, false); ok {
+		return k
+	}
+	return &KeywordTable{fallback: keywords}
+}
+
+// buildKeywordTable hashes the keywords into as many buckets, and displaces each bucket into
+// as many slots, a power of two at least the number of keywords, reporting whether every
+// keyword was given its own slot.
+func buildKeywordTable(keywords map[string]TokenType, sampled bool) (*KeywordTable, bool) {
+	n, shift := 1, uint(64)
+	for n < len(keywords) {
+		n, shift = n*2, shift-1
+	}
+	k := &KeywordTable{
+		displacements: make([]uint32, n),
+		keywords:      make([]string, n),
+		types:         make([]TokenType, n),
+		shift:         shift,
+		sampled:       sampled,
+		minLength:     -1,
+	}
+	buckets := make([][]string, n)
+	for keyword := range keywords {
+		if sampled && keyword == "" {
+			return nil, false
+		}
+		b := k.hash(keyword) >> shift
+		buckets[b] = append(buckets[b], keyword)
+		if k.minLength < 0 || len(keyword) < k.minLength {
+			k.minLength = len(keyword)
+		}
+		if len(keyword) > k.maxLength {
+			k.maxLength = len(keyword)
+		}
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(buckets[order[i]]) > len(buckets[order[j]])
+	})
+	used := make([]bool, n)
+	for _, b := range order {
+		if len(buckets[b]) == 0 {
+			break
+		}
+		d, slots, ok := k.displace(buckets[b], used)
+		if !ok {
+			return nil, false
+		}
+		k.displacements[b] = d
+		for i, keyword := range buckets[b] {
+			used[slots[i]] = true
+			k.keywords[slots[i]] = keyword
+			k.types[slots[i]] = keywords[keyword]
+		}
+	}
+	return k, true
+}
+
+// displace returns a displacement moving the keywords of a bucket to distinct unused slots.
+func (k *KeywordTable) displace(keywords []string, used []bool) (uint32, []uint64, bool) {
+	slots := make([]uint64, len(keywords))
+	for d := uint32(0); d < maxKeywordDisplacement; d++ {
+		ok := true
+		for i, keyword := range keywords {
+			slots[i] = k.slot(k.hash(keyword), d)
+			if used[slots[i]] {
+				ok = false
+				break
+			}
+			for _, slot := range slots[:i] {
+				if slot == slots[i] {
+					ok = false
+					break
+				}
+			}
+			if !ok {
+				break
+			}
+		}
+		if ok {
+			return d, slots, true
+		}
+	}
+	return 0, nil, false
+}
+
+// hash returns the hash of a word, of its length and its first, middle, and last bytes if
+// the table is sampled, or the FNV-1a hash of its bytes otherwise.
+func (k *KeywordTable) hash(s string) uint64 {
+	if k.sampled {
+		n := len(s)
+		return (uint64(n) | uint64(s[0])<<8 | uint64(s[n/2])<<16 | uint64(s[n-1])<<24) * 0x9e3779b97f4a7c15
+	}
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// slot returns the slot of a keyword with the hash in a bucket with the displacement.
+func (k *KeywordTable) slot(h uint64, d uint32) uint64 {
+	return ((h ^ uint64(d)*0x9e3779b97f4a7c15) * 0xff51afd7ed558ccd) >> k.shift
+}
+
+// Lookup returns the type of tokens the word is lexed as, if it is a keyword.
+func (k *KeywordTable) Lookup(word string) (TokenType, bool) {
+	if k.fallback != nil {
+		t, ok := k.fallback[word]
+		return t, ok
+	}
+	if len(word) < k.minLength || len(word) > k.maxLength {
+		return 0, false
+	}
+	h := k.hash(word)
+	slot := k.slot(h, k.displacements[h>>k.shift])
+	if k.keywords[slot] != word {
+		return 0, false
+	}
+	return k.types[slot], true
+}
+
+// EmitIdentifier emits the runes consumed since the last token was emitted as a token of
+// the keyword's type if they are a keyword of the table, or otherwise of the specified type.
+func (l *Lexer) EmitIdentifier(keywords *KeywordTable, identifier TokenType) {
+	if t, ok := keywords.Lookup(l.Input[l.startPosition:l.CurrentPosition]); ok {
+		identifier = t
+	}
+	l.Emit(identifier)
+}
+
+// Reclassify returns middleware reclassifying tokens of the identifier type that are
+// keywords of the table, e.g. for identifiers lexed by a rule table.
+func (k *KeywordTable) Reclassify(identifier TokenType) Middleware {
+	return func(t Token) []Token {
+		if word, ok := t.Value.(string); ok && t.Type == identifier {
+			if keyword, ok := k.Lookup(word); ok {
+				t.Type = keyword
+			}
+		}
+		return []Token{t}
+	}
+}
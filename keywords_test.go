@@ -0,0 +1,122 @@
+package lexer_test
+
+import (
+	"fmt"
+	"testing"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Keywords", func() {
+	const (
+		Identifier lexer.TokenType = iota
+		If
+		Else
+		Return
+	)
+
+	keywords := lexer.Keywords(map[string]lexer.TokenType{"if": If, "else": Else, "return": Return})
+
+	It("should look up keywords (i.e. Keywords and Lookup)", func() {
+		t, ok := keywords.Lookup("else")
+		Expect(ok).To(BeTrue())
+		Expect(t).To(Equal(Else))
+		_, ok = keywords.Lookup("elsewhere")
+		Expect(ok).To(BeFalse())
+		_, ok = lexer.Keywords(nil).Lookup("if")
+		Expect(ok).To(BeFalse())
+		alike := lexer.Keywords(map[string]lexer.TokenType{"abxc": If, "aaxc": Else, "": Return})
+		for word, expected := range map[string]lexer.TokenType{"abxc": If, "aaxc": Else, "": Return} {
+			t, ok = alike.Lookup(word)
+			Expect(ok).To(BeTrue())
+			Expect(t).To(Equal(expected))
+		}
+		_, ok = alike.Lookup("acxc")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should look up every keyword of large tables (i.e. Keywords and Lookup)", func() {
+		words := map[string]lexer.TokenType{}
+		for i := 0; i < 1000; i++ {
+			words[fmt.Sprintf("keyword%d", i)] = lexer.TokenType(i)
+		}
+		table := lexer.Keywords(words)
+		for word, t := range words {
+			keyword, ok := table.Lookup(word)
+			Expect(ok).To(BeTrue())
+			Expect(keyword).To(Equal(t))
+		}
+		_, ok := table.Lookup("keyword1000")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should reclassify identifiers as keywords (i.e. EmitIdentifier)", func() {
+		state := func(l *lexer.Lexer) lexer.StateFunc {
+			for {
+				switch r := l.Peek(); {
+				case r == lexer.EOF:
+					return nil
+				case unicode.IsSpace(r):
+					l.Ignore()
+				default:
+					l.NextUpTo(unicode.IsSpace)
+					l.EmitIdentifier(keywords, Identifier)
+				}
+			}
+		}
+		tokens, err := lexer.Tokenize("if x else return", state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(4))
+		Expect(tokens[0]).To(EqualToken(lexer.Token{Type: If, Value: "if"}))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: Identifier, Value: "x"}))
+		Expect(tokens[3]).To(EqualToken(lexer.Token{Type: Return, Value: "return"}))
+	})
+
+	It("should reclassify identifiers lexed by rule tables (i.e. Reclassify)", func() {
+		state, err := lexer.NewRules().Skip(lexer.Class(`\s`)).Add(Identifier, lexer.Class(`\w`)).State()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize("if y", state, lexer.WithMiddleware(keywords.Reclassify(Identifier)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens[0]).To(EqualToken(lexer.Token{Type: If, Value: "if"}))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: Identifier, Value: "y"}))
+	})
+})
+
+// benchmarkKeywords are the keywords of Go, and benchmarkWords the keywords and as many
+// identifiers that are not keywords, looked up by the keyword benchmarks.
+var (
+	benchmarkKeywords = map[string]lexer.TokenType{}
+	benchmarkWords    []string
+)
+
+func init() {
+	for i, keyword := range []string{
+		"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough",
+		"for", "func", "go", "goto", "if", "import", "interface", "map", "package", "range",
+		"return", "select", "struct", "switch", "type", "var",
+	} {
+		benchmarkKeywords[keyword] = lexer.TokenType(i)
+		benchmarkWords = append(benchmarkWords, keyword, keyword+"s")
+	}
+}
+
+func BenchmarkKeywordTable(b *testing.B) {
+	keywords := lexer.Keywords(benchmarkKeywords)
+	for i := 0; i < b.N; i++ {
+		for _, word := range benchmarkWords {
+			keywords.Lookup(word)
+		}
+	}
+}
+
+func BenchmarkKeywordMap(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, word := range benchmarkWords {
+			_ = benchmarkKeywords[word]
+		}
+	}
+}
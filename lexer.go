@@ -44,15 +44,31 @@
 package lexer
 
 import (
-	"fmt"
+	"iter"
+	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 	"unicode/utf8"
 )
 
 // Token, consisting of a type and value, represents the output of the lexer.
+//
+// The position of a token is the position of its first rune in the input; its line and
+// column, both starting at 1, are derived from that position.
+//
+// When the lexer was created with WithTrivia the runes it skipped around the token are
+// attached to it as trivia. When the lexer was created with WithLossless the exact input
+// text of the token is retained as its raw text.
 type Token struct {
-	Type  TokenType
-	Value interface{}
+	Type     TokenType
+	Value    interface{}
+	Raw      string
+	Trivia   []Token
+	Position RunePosition
+	Line     int
+	Column   int
 }
 
 // TokenType represents the type of a given token.
@@ -82,34 +98,166 @@ type Lexer struct {
 	CurrentPosition  RunePosition
 	CurrentRuneWidth RuneWidth
 	initialState     StateFunc
+	state            StateFunc
+	states           []StateFunc
+	delimiters       []delimiter
+	delimiterPairs   map[rune]rune
+	heredocs         []Heredoc
+	stateName        string
+	coverage         *Coverage
+	profile          *Profile
+	pauseMutex       sync.Mutex
+	resume           chan struct{}
+	mode             Mode
+	modeStack        []Mode
+	origin           Token
+	failed           bool
+	errorCount       int
+	maxErrors        int
+	maxStalls        int
+	illegal          bool
+	stalls           int
+	sent             int
+	diagnostics      []LexError
+	reported         *LexError
+	errorFormatter   func(LexError) string
+	finished         bool
+	synchronous      bool
+	queue            []Token
+	lookahead        []Token
+	tokensOnce       sync.Once
 	startPosition    RunePosition
-	currentToken     Token
 	previousToken    Token
+	currentToken     Token
+	history          []Token
+	historyStart     int
+	historyLength    int
 	tokenMutex       sync.Mutex
+	emitMutex        sync.Mutex
+	diagnosticMutex  sync.Mutex
 	tokens           chan Token
+	done             chan struct{}
+	closeOnce        sync.Once
+	stopContext      func() bool
+	trimCutset       string
+	triviaMode       TriviaMode
+	trivia           []Token
+	triviaEnd        RunePosition
+	heldToken        *Token
+	lossless         bool
+	interner         *Interner
+	internTypes      []TokenType
+	allocator        Allocator
+	deadline         time.Time
+	middlewareMutex  sync.RWMutex
+	middlewares      []Middleware
+	onError          []func(LexError)
+	subscribeMutex   sync.Mutex
+	subscribers      []*subscriber
+	lines            []RunePosition
+	linesScanned     RunePosition
 }
 
+// Option configures optional behavior of a lexer.
+type Option func(*Lexer)
+
 // NewLexer creates a lexer from the input and initial state.
-func NewLexer(input string, initialState StateFunc) *Lexer {
+func NewLexer(input string, initialState StateFunc, options ...Option) *Lexer {
 	l := &Lexer{
 		Input:        input,
 		initialState: initialState,
+		state:        initialState,
 		tokens:       make(chan Token, 1),
+		done:         make(chan struct{}),
+		history:      make([]Token, 2),
+	}
+	for _, option := range options {
+		option(l)
+	}
+	if l.lossless && l.triviaMode == TriviaDiscard {
+		l.triviaMode = TriviaLeading
+	}
+	if !l.synchronous {
+		go func() {
+			defer close(l.tokens)
+			for l.step() {
+			}
+		}()
 	}
-	go func() {
-		for s := l.initialState; s != nil; {
-			s = s(l)
-		}
-	}()
 	return l
 }
 
 // NextToken returns the next token emitted by the lexer.
+//
+// Returns an empty token if the state machine has finished and every token has been
+// returned, or if the lexer has been closed.
 func (l *Lexer) NextToken() Token {
-	return <-l.tokens
+	t, _ := l.receive()
+	return t
+}
+
+// NextSignificantToken returns the next token emitted by the lexer that is not of one of
+// the specified types (e.g. whitespace or comments), discarding any tokens that are.
+func (l *Lexer) NextSignificantToken(skip ...TokenType) Token {
+	for {
+		t, ok := l.receive()
+		if !ok || !containsTokenType(skip, t.Type) {
+			return t
+		}
+	}
+}
+
+// TryNextToken returns the next token emitted by the lexer if one is ready, without
+// waiting for the state machine to emit one.
+//
+// Returns false if no token is ready, which includes once the state machine has finished
+// and every token has been returned. A lexer created with WithSynchronous only has tokens
+// ready that were emitted by Step or alongside a token returned by NextToken.
+func (l *Lexer) TryNextToken() (Token, bool) {
+	t, ok := l.popLookahead()
+	if !ok {
+		t, ok = l.fetch(false)
+	}
+	if ok {
+		l.track(t)
+	}
+	return t, ok
+}
+
+// All returns an iterator over the tokens emitted by the lexer.
+//
+// Breaking out of the iteration closes the lexer.
+func (l *Lexer) All() iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		for {
+			t, ok := l.receive()
+			if !ok {
+				return
+			}
+			if !yield(t) {
+				l.Close()
+				return
+			}
+		}
+	}
+}
+
+// Tokens returns the channel tokens emitted by the lexer are sent on.
+//
+// The channel is closed once the state machine has finished, so that callers can range over
+// it. Tokens received from the channel are not tracked by PreviousToken, and tokens already
+// buffered by PeekToken are not sent on it.
+//
+// If the lexer was created with WithSynchronous a goroutine is started to drive the state
+// machine; the lexer must not otherwise be used once Tokens has been called.
+func (l *Lexer) Tokens() <-chan Token {
+	if l.synchronous {
+		l.tokensOnce.Do(l.forwardTokens)
+	}
+	return l.tokens
 }
 
-// PreviousToken returns the most recently emitted token.
+// PreviousToken returns the token emitted before the one most recently emitted.
 func (l *Lexer) PreviousToken() Token {
 	l.tokenMutex.Lock()
 	defer l.tokenMutex.Unlock()
@@ -156,8 +304,11 @@ func (l *Lexer) Previous() rune {
 }
 
 // Ignore skips and returns the next rune from the input.
+//
+// If the lexer was created with WithTrivia the skipped runes are captured as trivia.
 func (l *Lexer) Ignore() rune {
 	r := l.Next()
+	l.captureTrivia(TokenTrivia)
 	l.startPosition = l.CurrentPosition
 	return r
 }
@@ -170,22 +321,281 @@ func (l *Lexer) IgnoreUpTo(predicate RunePredicate) rune {
 }
 
 // Emit emits a token of the specified type.
+//
+// If the lexer was created with WithTrimCutset the cutset is stripped from the token's
+// value.
 func (l *Lexer) Emit(tokenType TokenType) {
-	t := Token{tokenType, l.Input[l.startPosition:l.CurrentPosition]}
-	l.tokens <- t
-	l.tokenMutex.Lock()
-	l.previousToken = l.currentToken
-	l.currentToken = t
-	l.tokenMutex.Unlock()
+	l.EmitTrimmed(tokenType, l.trimCutset)
+}
+
+// EmitTrimmed emits a token of the specified type with all leading and trailing runes
+// contained in the cutset stripped from its value.
+func (l *Lexer) EmitTrimmed(tokenType TokenType, cutset string) {
+	lexeme := l.Input[l.startPosition:l.CurrentPosition]
+	t := Token{Type: tokenType, Value: l.value(tokenType, strings.Trim(lexeme, cutset))}
+	if l.lossless {
+		t.Raw = lexeme
+	}
+	l.send(t)
+	l.startPosition = l.CurrentPosition
+}
+
+// EmitValue emits a token of the specified type with the specified value (e.g. the value of
+// a string literal, see ScanString) in place of the runes consumed since the last token was
+// emitted, which are retained as its raw text if the lexer was created with WithLossless.
+func (l *Lexer) EmitValue(tokenType TokenType, value interface{}) {
+	t := Token{Type: tokenType, Value: value}
+	if l.lossless {
+		t.Raw = l.Input[l.startPosition:l.CurrentPosition]
+	}
+	l.send(t)
+	l.startPosition = l.CurrentPosition
+}
+
+// EmitNonEmpty emits a token of the specified type only if the lexer has consumed runes
+// since the last token was emitted.
+//
+// Returns true if a token was emitted.
+func (l *Lexer) EmitNonEmpty(tokenType TokenType) bool {
+	if l.startPosition >= l.CurrentPosition {
+		return false
+	}
+	l.Emit(tokenType)
+	return true
+}
+
+// EmitAll emits the specified tokens, in order, in place of the runes consumed since the
+// last token was emitted.
+//
+// The tokens are emitted atomically; no other token can be emitted between them. If the
+// lexer was created with WithLossless the tokens must carry their own raw text.
+func (l *Lexer) EmitAll(tokens ...Token) {
+	l.checkDeadline()
+	l.emitMutex.Lock()
+	defer l.emitMutex.Unlock()
+	for _, t := range tokens {
+		l.sendLocked(t)
+	}
 	l.startPosition = l.CurrentPosition
 }
 
 // Errorf emits an error token with the specified error message as its value.
+//
+// If the current state is named (see Named) the value is a LexError identifying the state
+// instead.
 func (l *Lexer) Errorf(format string, args ...interface{}) StateFunc {
-	l.tokens <- Token{TokenError, fmt.Sprintf(format, args...)}
+	l.report(l.lexError(SeverityError, "", format, args...))
 	return nil
 }
 
+// Close stops the lexer; any token not yet returned by NextToken is discarded.
+//
+// The goroutine running the state machine exits as soon as it emits another token or
+// transitions to another state, releasing the lexer's input. Close is only required if the
+// caller stops consuming tokens before the state machine has finished.
+func (l *Lexer) Close() {
+	l.closeOnce.Do(func() {
+		close(l.done)
+		l.releaseContext()
+	})
+}
+
+func (l *Lexer) closed() bool {
+	select {
+	case <-l.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// step executes the current state, returning false once the state machine has finished.
+func (l *Lexer) step() bool {
+	if l.finished {
+		return false
+	}
+	l.waitResume()
+	if l.state == nil || l.closed() {
+		l.finished = true
+		l.releaseContext()
+		if !l.closed() {
+			l.finish()
+		}
+		return false
+	}
+	defer l.recoverState()
+	l.checkDeadline()
+	l.stateName = ""
+	position, start, sent := l.CurrentPosition, l.startPosition, l.sent
+	l.state = l.state(l)
+	l.detectLoop(position, start, sent)
+	return true
+}
+
+// halt stops the state machine from within the current state.
+func (l *Lexer) halt() {
+	panic(errHalt)
+}
+
+func (l *Lexer) receive() (Token, bool) {
+	t, ok := l.popLookahead()
+	if !ok {
+		t, ok = l.fetch(true)
+	}
+	if ok {
+		l.track(t)
+	}
+	return t, ok
+}
+
+// fetch returns the next token emitted by the state machine, optionally waiting for one to
+// be emitted.
+func (l *Lexer) fetch(wait bool) (Token, bool) {
+	var t Token
+	if l.closed() {
+		return t, false
+	}
+	if l.synchronous {
+		if !wait && len(l.queue) == 0 {
+			return t, false
+		}
+		return l.fetchSynchronously()
+	}
+	if !wait {
+		select {
+		case next, ok := <-l.tokens:
+			return next, ok
+		default:
+			return t, false
+		}
+	}
+	select {
+	case next, ok := <-l.tokens:
+		return next, ok
+	case <-l.done:
+		return t, false
+	}
+}
+
+func containsTokenType(types []TokenType, tokenType TokenType) bool {
+	for _, t := range types {
+		if t == tokenType {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Lexer) track(t Token) {
+	l.tokenMutex.Lock()
+	defer l.tokenMutex.Unlock()
+	if l.historyLength < len(l.history) {
+		l.history[(l.historyStart+l.historyLength)%len(l.history)] = t
+		l.historyLength++
+		return
+	}
+	l.history[l.historyStart] = t
+	l.historyStart = (l.historyStart + 1) % len(l.history)
+}
+
+func (l *Lexer) send(t Token) {
+	l.checkDeadline()
+	l.emitMutex.Lock()
+	defer l.emitMutex.Unlock()
+	l.sendLocked(t)
+}
+
+func (l *Lexer) sendLocked(t Token) {
+	l.sent++
+	if t.Type == TokenError {
+		l.failed = true
+		l.errorCount++
+	}
+	if t.Line == 0 {
+		l.setPosition(&t, l.startPosition)
+	}
+	l.collectDiagnostic(t)
+	if l.triviaMode != TriviaDiscard {
+		l.attachTrivia(t)
+	} else {
+		l.deliver(t)
+	}
+	if t.Type == TokenError && l.errorCount == l.maxErrors {
+		l.tooManyErrors()
+	}
+}
+
+func (l *Lexer) setPosition(t *Token, position RunePosition) {
+	for ; l.linesScanned < position && int(l.linesScanned) < len(l.Input); l.linesScanned++ {
+		if l.Input[l.linesScanned] == '\n' {
+			l.lines = append(l.lines, l.linesScanned+1)
+		}
+	}
+	line := sort.Search(len(l.lines), func(i int) bool {
+		return l.lines[i] > position
+	})
+	lineStart := RunePosition(0)
+	if line > 0 {
+		lineStart = l.lines[line-1]
+	}
+	t.Position = position
+	t.Line = line + 1
+	t.Column = utf8.RuneCountInString(l.Input[lineStart:position]) + 1
+	if l.origin.Line != 0 {
+		if line == 0 {
+			t.Column += l.origin.Column - 1
+		}
+		t.Position += l.origin.Position
+		t.Line += l.origin.Line - 1
+	}
+}
+
+func (l *Lexer) value(tokenType TokenType, lexeme string) interface{} {
+	lexeme = l.intern(tokenType, lexeme)
+	if l.allocator != nil {
+		return l.allocator.Value(tokenType, lexeme)
+	}
+	return lexeme
+}
+
+func (l *Lexer) finish() {
+	l.emitMutex.Lock()
+	defer l.emitMutex.Unlock()
+	l.reportUnclosedDelims()
+	if l.lossless {
+		l.CurrentPosition = RunePosition(len(l.Input))
+		l.captureTrivia(TokenTrivia)
+		l.startPosition = l.CurrentPosition
+	}
+	l.flushTrivia()
+}
+
+func (l *Lexer) deliver(t Token) {
+	if middleware := l.middleware(); len(middleware) > 0 {
+		for _, u := range applyMiddleware(middleware, t) {
+			l.enqueue(u)
+		}
+		return
+	}
+	l.enqueue(t)
+}
+
+func (l *Lexer) enqueue(t Token) {
+	if l.synchronous {
+		l.queue = append(l.queue, t)
+	} else {
+		select {
+		case l.tokens <- t:
+		case <-l.done:
+			runtime.Goexit()
+		}
+	}
+	l.tokenMutex.Lock()
+	l.previousToken = l.currentToken
+	l.currentToken = t
+	l.tokenMutex.Unlock()
+}
+
 func (l *Lexer) consumeUpTo(predicate RunePredicate, consumer func() rune) rune {
 	var r rune
 	for {
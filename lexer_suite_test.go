@@ -1,8 +1,13 @@
 package lexer_test
 
 import (
+	"fmt"
+
+	"github.com/eczarny/lexer"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
 
 	"testing"
 )
@@ -11,3 +16,29 @@ func TestLexer(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Lexer Suite")
 }
+
+// EqualToken succeeds if the actual token is equal to the expected token, ignoring
+// positions (i.e. Token.Equal).
+func EqualToken(expected lexer.Token) types.GomegaMatcher {
+	return &tokenMatcher{expected}
+}
+
+type tokenMatcher struct {
+	expected lexer.Token
+}
+
+func (m *tokenMatcher) Match(actual interface{}) (bool, error) {
+	t, ok := actual.(lexer.Token)
+	if !ok {
+		return false, fmt.Errorf("EqualToken expects a lexer.Token, got %T", actual)
+	}
+	return m.expected.Equal(t), nil
+}
+
+func (m *tokenMatcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n\t%v\nto equal\n\t%v", actual, m.expected)
+}
+
+func (m *tokenMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n\t%v\nnot to equal\n\t%v", actual, m.expected)
+}
@@ -17,7 +17,7 @@ var _ = Describe("Lexer", func() {
 	}
 
 	assertToken := func(token lexer.Token, tokenType lexer.TokenType, tokenValue interface{}) {
-		Expect(token).To(Equal(lexer.Token{tokenType, tokenValue}))
+		Expect(token).To(EqualToken(lexer.Token{Type: tokenType, Value: tokenValue}))
 	}
 
 	It("should return the next token emitted by the lexer (i.e. NextToken and Emit)", func() {
@@ -29,6 +29,59 @@ var _ = Describe("Lexer", func() {
 		assertToken(l.NextToken(), Token, "E")
 	})
 
+	It("should emit a token with the cutset stripped from its value (i.e. EmitTrimmed)", func() {
+		l := lexer.NewLexer(`"quoted"  `, func(l *lexer.Lexer) lexer.StateFunc {
+			l.NextUpTo(func(r rune) bool {
+				return r == lexer.EOF
+			})
+			l.EmitTrimmed(Token, `" `)
+			return nil
+		})
+		assertToken(l.NextToken(), Token, "quoted")
+	})
+
+	It("should strip the lexer-wide cutset from every emitted token (i.e. WithTrimCutset)", func() {
+		l := lexer.NewLexer(" E = m ", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Next()
+			l.Emit(Token)
+			l.Next()
+			l.Next()
+			l.Emit(Token)
+			return nil
+		}, lexer.WithTrimCutset(" "))
+		assertToken(l.NextToken(), Token, "E")
+		assertToken(l.NextToken(), Token, "=")
+	})
+
+	It("should emit a token only if runes were consumed (i.e. EmitNonEmpty)", func() {
+		e := make(chan bool)
+		l := lexer.NewLexer("2.71", func(l *lexer.Lexer) lexer.StateFunc {
+			e <- l.EmitNonEmpty(Token)
+			l.NextUpTo(func(r rune) bool {
+				return !numeric(r)
+			})
+			e <- l.EmitNonEmpty(Token)
+			return nil
+		})
+		Expect(<-e).To(BeFalse())
+		Expect(<-e).To(BeTrue())
+		assertToken(l.NextToken(), Token, "2.71")
+	})
+
+	It("should emit several tokens in place of the consumed runes (i.e. EmitAll)", func() {
+		l := lexer.NewLexer(">>", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Next()
+			l.EmitAll(lexer.Token{Type: Token, Value: ">"}, lexer.Token{Type: Token, Value: ">"})
+			l.Emit(Token)
+			return nil
+		})
+		assertToken(l.NextToken(), Token, ">")
+		assertToken(l.NextToken(), Token, ">")
+		assertToken(l.NextToken(), Token, "")
+	})
+
 	It("should return the next rune from the input and moves the current position of the lexer ahead (i.e. Next)", func(done Done) {
 		r := make(chan rune)
 		p := make(chan lexer.RunePosition)
@@ -165,38 +218,120 @@ var _ = Describe("Lexer", func() {
 		close(done)
 	})
 
-	It("should return the most recently emitted token (i.e. PreviousToken)", func(done Done) {
-		l := lexer.NewLexer("a^2 + b^2 = c^2", func(l *lexer.Lexer) lexer.StateFunc {
-			p := func(r rune) bool {
-				return !(r == 'a' || r == 'b' || r == 'c' || r == '^' || r == '2')
+	It("should return the token emitted before the one most recently emitted (i.e. PreviousToken)", func() {
+		var previous []interface{}
+		l := lexer.NewLexer("a^2 + b^2", func(l *lexer.Lexer) lexer.StateFunc {
+			for _, n := range []int{3, 1, 3} {
+				l.IgnoreUpTo(func(r rune) bool { return r != ' ' })
+				for i := 0; i < n; i++ {
+					l.Next()
+				}
+				l.Emit(Token)
+				previous = append(previous, l.PreviousToken().Value)
 			}
-			l.NextUpTo(p)
-			l.Emit(Token)
-			l.Ignore()
-			l.Next()
-			l.Emit(Token)
-			l.Ignore()
-			l.NextUpTo(p)
-			l.Emit(Token)
-			l.Ignore()
+			return nil
+		}, lexer.WithSynchronous())
+		assertToken(l.PreviousToken(), 0, nil)
+		assertToken(l.NextToken(), Token, "a^2")
+		Expect(previous).To(Equal([]interface{}{nil, "a^2", "+"}))
+	})
+
+	It("should close the channel of emitted tokens once the state machine finishes (i.e. Tokens)", func(done Done) {
+		l := lexer.NewLexer("a^2 + b^2", func(l *lexer.Lexer) lexer.StateFunc {
+			for l.Peek() != lexer.EOF {
+				l.IgnoreUpTo(func(r rune) bool {
+					return r != ' '
+				})
+				l.NextUpTo(func(r rune) bool {
+					return r == ' '
+				})
+				l.Emit(Token)
+			}
+			return nil
+		})
+		var values []interface{}
+		for t := range l.Tokens() {
+			values = append(values, t.Value)
+		}
+		Expect(values).To(Equal([]interface{}{"a^2", "+", "b^2"}))
+		assertToken(l.NextToken(), Token, nil)
+		close(done)
+	})
+
+	It("should discard tokens of the specified types (i.e. NextSignificantToken)", func() {
+		const Whitespace lexer.TokenType = 1
+		l := lexer.NewLexer("E = m", func(l *lexer.Lexer) lexer.StateFunc {
+			for l.Peek() != lexer.EOF {
+				if l.Next() == ' ' {
+					l.Emit(Whitespace)
+				} else {
+					l.Emit(Token)
+				}
+			}
+			return nil
+		})
+		assertToken(l.NextSignificantToken(Whitespace), Token, "E")
+		assertToken(l.NextSignificantToken(Whitespace), Token, "=")
+		assertToken(l.NextToken(), Whitespace, " ")
+		assertToken(l.NextSignificantToken(Whitespace), Token, "m")
+		assertToken(l.NextSignificantToken(Whitespace), Token, nil)
+	})
+
+	It("should return the next token only if one is ready (i.e. TryNextToken)", func(done Done) {
+		proceed := make(chan struct{})
+		l := lexer.NewLexer("E = m * c^2", func(l *lexer.Lexer) lexer.StateFunc {
+			<-proceed
 			l.Next()
 			l.Emit(Token)
-			l.Ignore()
-			l.NextUpTo(p)
-			l.Emit(Token)
 			return nil
 		})
-		assertToken(l.PreviousToken(), Token, nil)
-		assertToken(l.NextToken(), Token, "a^2")
-		assertToken(l.PreviousToken(), Token, nil)
-		assertToken(l.NextToken(), Token, "+")
-		assertToken(l.PreviousToken(), Token, "a^2")
-		assertToken(l.NextToken(), Token, "b^2")
-		assertToken(l.PreviousToken(), Token, "+")
-		assertToken(l.NextToken(), Token, "=")
-		assertToken(l.PreviousToken(), Token, "b^2")
-		assertToken(l.NextToken(), Token, "c^2")
-		assertToken(l.PreviousToken(), Token, "=")
+		_, ok := l.TryNextToken()
+		Expect(ok).To(BeFalse())
+		close(proceed)
+		Eventually(func() bool {
+			t, ok := l.TryNextToken()
+			if ok {
+				assertToken(t, Token, "E")
+			}
+			return ok
+		}).Should(BeTrue())
+		close(done)
+	})
+
+	It("should iterate over the emitted tokens (i.e. All)", func(done Done) {
+		exited := make(chan struct{})
+		l := lexer.NewLexer("E = m * c^2", func(l *lexer.Lexer) lexer.StateFunc {
+			defer close(exited)
+			for l.Peek() != lexer.EOF {
+				l.Next()
+				l.Emit(Token)
+			}
+			return nil
+		})
+		var values []interface{}
+		for t := range l.All() {
+			values = append(values, t.Value)
+			if len(values) == 3 {
+				break
+			}
+		}
+		Expect(values).To(Equal([]interface{}{"E", " ", "="}))
+		<-exited
+		close(done)
+	})
+
+	It("should stop the goroutine running the state machine (i.e. Close)", func(done Done) {
+		exited := make(chan struct{})
+		l := lexer.NewLexer("E = m * c^2", func(l *lexer.Lexer) lexer.StateFunc {
+			defer close(exited)
+			for {
+				l.Emit(Token)
+			}
+		})
+		assertToken(l.NextToken(), Token, "")
+		l.Close()
+		<-exited
+		assertToken(l.NextToken(), Token, nil)
 		close(done)
 	})
 
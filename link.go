@@ -0,0 +1,62 @@
+package lexer
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// TokenURL represents a type of token that contains a URL (see ScanURL).
+	TokenURL TokenType = -18
+
+	// TokenEmail represents a type of token that contains an email address (see ScanEmail).
+	TokenEmail TokenType = -19
+)
+
+var (
+	urlRe   = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*://[^\s<>"]+`)
+	emailRe = regexp.MustCompile("^[A-Za-z0-9.!#$%&'*+/=?^_`{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?(?:\\.[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?)+")
+)
+
+// ScanURL scans a URL with a scheme and an authority (e.g. https://example.com/a?b#c) at
+// the current position of the lexer, as text is commonly autolinked: the URL extends up to
+// whitespace, '<', '>', or '"', excluding trailing punctuation (e.g. a period ending a
+// sentence) and unbalanced closing parentheses or brackets (e.g. of a parenthetical). The
+// URL is consumed, not emitted.
+//
+// Returns an error, a LexError, if there is no URL at the current position, having consumed
+// nothing.
+func ScanURL(l *Lexer) error {
+	url := urlRe.FindString(l.Input[l.CurrentPosition:])
+	for url != "" {
+		n := len(url)
+		switch c := url[n-1]; {
+		case strings.IndexByte(".,:;!?'*", c) >= 0,
+			c == ')' && strings.Count(url, "(") < strings.Count(url, ")"),
+			c == ']' && strings.Count(url, "[") < strings.Count(url, "]"):
+			url = url[:n-1]
+			continue
+		}
+		break
+	}
+	if url == "" || strings.HasSuffix(url, "://") {
+		return l.errorAt(l.CurrentPosition, "expected a URL")
+	}
+	l.advance(len(url))
+	return nil
+}
+
+// ScanEmail scans an email address at the current position of the lexer, as the HTML
+// standard defines valid email addresses, except that the domain must have at least two
+// labels (e.g. user+tag@example.com). The address is consumed, not emitted.
+//
+// Returns an error, a LexError, if there is no email address at the current position,
+// having consumed nothing.
+func ScanEmail(l *Lexer) error {
+	email := emailRe.FindString(l.Input[l.CurrentPosition:])
+	if email == "" {
+		return l.errorAt(l.CurrentPosition, "expected an email address")
+	}
+	l.advance(len(email))
+	return nil
+}
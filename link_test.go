@@ -0,0 +1,48 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Link", func() {
+	It("should scan URLs excluding trailing punctuation (i.e. ScanURL)", func() {
+		for input, url := range map[string]string{
+			"https://example.com/a?b=1#c":                  "https://example.com/a?b=1#c",
+			"http://example.com.":                          "http://example.com",
+			"ftp://example.com/a, b":                       "ftp://example.com/a",
+			"https://en.wikipedia.org/wiki/Go_(language))": "https://en.wikipedia.org/wiki/Go_(language)",
+			"svn+ssh://host/repo<b>":                       "svn+ssh://host/repo",
+			"https://example.com/\"x\"":                    "https://example.com/",
+			"https://example.com/[a]]":                     "https://example.com/[a]",
+		} {
+			l := lexer.NewLexer(input, nil)
+			Expect(lexer.ScanURL(l)).To(Succeed(), input)
+			Expect(l.Input[:l.CurrentPosition]).To(Equal(url), input)
+		}
+		for _, input := range []string{"example.com", "https://", "https://.", "1http://example.com", "x"} {
+			l := lexer.NewLexer(input, nil)
+			Expect(lexer.ScanURL(l)).To(MatchError("expected a URL"), input)
+			Expect(l.CurrentPosition).To(BeZero())
+		}
+	})
+
+	It("should scan email addresses (i.e. ScanEmail)", func() {
+		for input, email := range map[string]string{
+			"user@example.com":           "user@example.com",
+			"first.last+tag@mail.co.uk.": "first.last+tag@mail.co.uk",
+			"a@b-c.org, d@e.org":         "a@b-c.org",
+		} {
+			l := lexer.NewLexer(input, nil)
+			Expect(lexer.ScanEmail(l)).To(Succeed(), input)
+			Expect(l.Input[:l.CurrentPosition]).To(Equal(email), input)
+		}
+		for _, input := range []string{"user@localhost", "@example.com", "user@-example.com", "user"} {
+			l := lexer.NewLexer(input, nil)
+			Expect(lexer.ScanEmail(l)).To(MatchError("expected an email address"), input)
+			Expect(l.CurrentPosition).To(BeZero())
+		}
+	})
+})
@@ -0,0 +1,128 @@
+package lexer
+
+// LiteralMatcher matches many literals (e.g. operators or reserved words) in a single scan
+// of the input, using an Aho-Corasick automaton.
+type LiteralMatcher struct {
+	nodes     []literalNode
+	maxLength int
+}
+
+type literalNode struct {
+	next  map[byte]int32
+	fail  int32
+	depth int
+
+	// terminal is true if a literal ends at the node; output is the nearest node, following
+	// failure links, at which a literal ends, or -1.
+	terminal bool
+	output   int32
+}
+
+// NewLiteralMatcher creates a matcher of the literals; empty literals are ignored.
+func NewLiteralMatcher(literals ...string) *LiteralMatcher {
+	m := &LiteralMatcher{nodes: []literalNode{{next: map[byte]int32{}, output: -1}}}
+	for _, literal := range literals {
+		if literal == "" {
+			continue
+		}
+		n := int32(0)
+		for i := 0; i < len(literal); i++ {
+			next, ok := m.nodes[n].next[literal[i]]
+			if !ok {
+				next = int32(len(m.nodes))
+				m.nodes = append(m.nodes, literalNode{next: map[byte]int32{}, depth: i + 1, output: -1})
+				m.nodes[n].next[literal[i]] = next
+			}
+			n = next
+		}
+		m.nodes[n].terminal = true
+		if len(literal) > m.maxLength {
+			m.maxLength = len(literal)
+		}
+	}
+	queue := []int32{0}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for c, child := range m.nodes[n].next {
+			fail := int32(0)
+			if n != 0 {
+				for fail = m.nodes[n].fail; ; fail = m.nodes[fail].fail {
+					if next, ok := m.nodes[fail].next[c]; ok {
+						fail = next
+						break
+					}
+					if fail == 0 {
+						break
+					}
+				}
+			}
+			m.nodes[child].fail = fail
+			if m.nodes[fail].terminal {
+				m.nodes[child].output = fail
+			} else {
+				m.nodes[child].output = m.nodes[fail].output
+			}
+			queue = append(queue, child)
+		}
+	}
+	return m
+}
+
+// Match returns the longest of the literals the string starts with.
+func (m *LiteralMatcher) Match(s string) (string, bool) {
+	length := 0
+	for n, i := int32(0), 0; i < len(s); i++ {
+		next, ok := m.nodes[n].next[s[i]]
+		if !ok {
+			break
+		}
+		if n = next; m.nodes[n].terminal {
+			length = i + 1
+		}
+	}
+	return s[:length], length > 0
+}
+
+// Index returns the position of the leftmost occurrence of any of the literals in the
+// string, and the longest literal occurring there, or -1 if none occurs.
+func (m *LiteralMatcher) Index(s string) (int, string) {
+	start := -1
+	for n, i := int32(0), 0; i < len(s); i++ {
+		if start >= 0 && i-start >= m.maxLength {
+			break
+		}
+		for n != 0 {
+			if _, ok := m.nodes[n].next[s[i]]; ok {
+				break
+			}
+			n = m.nodes[n].fail
+		}
+		if next, ok := m.nodes[n].next[s[i]]; ok {
+			n = next
+		}
+		for out := n; out > 0; out = m.nodes[out].output {
+			if !m.nodes[out].terminal {
+				continue
+			}
+			if p := i + 1 - m.nodes[out].depth; start < 0 || p < start {
+				start = p
+			}
+		}
+	}
+	if start < 0 {
+		return -1, ""
+	}
+	literal, _ := m.Match(s[start:])
+	return start, literal
+}
+
+// AcceptAnyLiteral consumes the longest of the matcher's literals at the current position
+// of the lexer, returning false without consuming input if none matches.
+func (l *Lexer) AcceptAnyLiteral(m *LiteralMatcher) (string, bool) {
+	literal, ok := m.Match(l.Input[l.CurrentPosition:])
+	if ok {
+		l.advance(len(literal))
+	}
+	return literal, ok
+}
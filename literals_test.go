@@ -0,0 +1,71 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Literals", func() {
+	const (
+		Operator lexer.TokenType = iota
+		Word
+	)
+
+	operators := lexer.NewLiteralMatcher("+", "++", "+=", "<", "<<", "<<=", "<=", "=", "==", "")
+
+	It("should consume the longest literal at the current position (i.e. AcceptAnyLiteral)", func() {
+		state := func(l *lexer.Lexer) lexer.StateFunc {
+			for {
+				switch r := l.Peek(); {
+				case r == lexer.EOF:
+					return nil
+				case unicode.IsSpace(r):
+					l.Ignore()
+				default:
+					if _, ok := l.AcceptAnyLiteral(operators); ok {
+						l.Emit(Operator)
+						continue
+					}
+					l.NextUpTo(func(r rune) bool {
+						return !unicode.IsLetter(r)
+					})
+					if !l.EmitNonEmpty(Word) {
+						return l.Errorf("unexpected %q", r)
+					}
+				}
+			}
+		}
+		tokens, err := lexer.Tokenize("a<<=b ++c<d==e+= f", state)
+		Expect(err).NotTo(HaveOccurred())
+		var values []interface{}
+		for _, t := range tokens {
+			values = append(values, t.Value)
+		}
+		Expect(values).To(Equal([]interface{}{"a", "<<=", "b", "++", "c", "<", "d", "==", "e", "+=", "f"}))
+	})
+
+	It("should find the leftmost occurrence of any literal (i.e. Index)", func() {
+		position, literal := operators.Index("abc <<= d")
+		Expect(position).To(Equal(4))
+		Expect(literal).To(Equal("<<="))
+		position, literal = operators.Index("ab=<<x")
+		Expect(position).To(Equal(2))
+		Expect(literal).To(Equal("="))
+		position, literal = lexer.NewLiteralMatcher("bcd", "abcde", "c").Index("xabcdy")
+		Expect(position).To(Equal(2))
+		Expect(literal).To(Equal("bcd"))
+		position, _ = operators.Index("abc")
+		Expect(position).To(Equal(-1))
+	})
+
+	It("should not match if no literal does (i.e. Match)", func() {
+		_, ok := operators.Match("-")
+		Expect(ok).To(BeFalse())
+		_, ok = lexer.NewLiteralMatcher().Match("a")
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,49 @@
+package lexer
+
+// PeekToken returns the next token emitted by the lexer without consuming it.
+//
+// Returns an empty token if the state machine has finished and every token has been
+// returned, or if the lexer has been closed.
+func (l *Lexer) PeekToken() Token {
+	return l.PeekTokenN(1)
+}
+
+// PeekTokenN returns the k-th upcoming token emitted by the lexer, starting at 1, without
+// consuming it or any token before it; k must be at least 1.
+//
+// Upcoming tokens are buffered until they are returned by NextToken. Returns an empty
+// token if fewer than k tokens remain, or if k is less than 1.
+func (l *Lexer) PeekTokenN(k int) Token {
+	if k < 1 {
+		return Token{}
+	}
+	for len(l.lookahead) < k {
+		t, ok := l.fetch(true)
+		if !ok {
+			return Token{}
+		}
+		l.lookahead = append(l.lookahead, t)
+	}
+	return l.lookahead[k-1]
+}
+
+// UnreadToken pushes a token back onto the lexer so that it is returned by the next call to
+// NextToken or PeekToken.
+//
+// Tokens are returned in the reverse order they were pushed back in, so that a parser can
+// unread the tokens it consumed during a failed speculative parse, most recent first.
+func (l *Lexer) UnreadToken(t Token) {
+	l.lookahead = append(l.lookahead, Token{})
+	copy(l.lookahead[1:], l.lookahead)
+	l.lookahead[0] = t
+}
+
+func (l *Lexer) popLookahead() (Token, bool) {
+	if len(l.lookahead) == 0 {
+		return Token{}, false
+	}
+	t := l.lookahead[0]
+	l.lookahead[0] = Token{}
+	l.lookahead = l.lookahead[1:]
+	return t, true
+}
@@ -0,0 +1,64 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lookahead", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Token)
+			}
+		}
+	}
+
+	token := func(value interface{}) lexer.Token {
+		return lexer.Token{Type: Token, Value: value}
+	}
+
+	for _, options := range [][]lexer.Option{nil, {lexer.WithSynchronous()}} {
+		options := options
+
+		It("should return upcoming tokens without consuming them (i.e. PeekToken and PeekTokenN)", func() {
+			l := lexer.NewLexer("a + b", state, options...)
+			Expect(l.PeekToken()).To(EqualToken(token("a")))
+			Expect(l.PeekTokenN(3)).To(EqualToken(token("b")))
+			Expect(l.PeekTokenN(4)).To(EqualToken(lexer.Token{}))
+			Expect(l.PeekTokenN(0)).To(EqualToken(lexer.Token{}))
+			Expect(l.PeekTokenN(-1)).To(EqualToken(lexer.Token{}))
+			Expect(l.NextToken()).To(EqualToken(token("a")))
+			Expect(l.PeekToken()).To(EqualToken(token("+")))
+			Expect(l.NextToken()).To(EqualToken(token("+")))
+			t, ok := l.TryNextToken()
+			Expect(ok).To(BeTrue())
+			Expect(t).To(EqualToken(token("b")))
+			Expect(l.PeekToken()).To(EqualToken(lexer.Token{}))
+		})
+
+		It("should return tokens pushed back onto the lexer first (i.e. UnreadToken)", func() {
+			l := lexer.NewLexer("a + b", state, options...)
+			a, plus := l.NextToken(), l.NextToken()
+			l.UnreadToken(plus)
+			l.UnreadToken(a)
+			Expect(l.PeekToken()).To(EqualToken(token("a")))
+			Expect(l.NextToken()).To(EqualToken(token("a")))
+			Expect(l.NextToken()).To(EqualToken(token("+")))
+			Expect(l.NextToken()).To(EqualToken(token("b")))
+			l.UnreadToken(token("c"))
+			Expect(l.NextToken()).To(EqualToken(token("c")))
+			Expect(l.NextToken()).To(EqualToken(lexer.Token{}))
+		})
+	}
+})
@@ -0,0 +1,27 @@
+package lexer
+
+// WithLoopDetection halts the lexer with an error token once its state functions have
+// returned n times in a row without consuming, ignoring, or emitting anything, instead of
+// spinning forever (e.g. a state that returns itself without advancing at an unexpected
+// rune).
+func WithLoopDetection(n int) Option {
+	return func(l *Lexer) {
+		l.maxStalls = n
+	}
+}
+
+// detectLoop counts the states that returned without making progress since the position,
+// start position, and number of tokens sent were recorded.
+func (l *Lexer) detectLoop(position, start RunePosition, sent int) {
+	if l.maxStalls <= 0 || l.state == nil {
+		return
+	}
+	if l.CurrentPosition != position || l.startPosition != start || l.sent != sent {
+		l.stalls = 0
+		return
+	}
+	if l.stalls++; l.stalls >= l.maxStalls {
+		l.report(l.lexError(SeverityError, "", "no progress after %d iterations at %q", l.stalls, l.Peek()))
+		l.state = nil
+	}
+}
@@ -0,0 +1,38 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Loop", func() {
+	var state lexer.StateFunc
+	state = func(l *lexer.Lexer) lexer.StateFunc {
+		switch r := l.Peek(); {
+		case r == lexer.EOF:
+			return nil
+		case unicode.IsLetter(r):
+			l.Next()
+			l.Emit(Token)
+		}
+		return state
+	}
+
+	It("should halt a state that makes no progress (i.e. WithLoopDetection)", func(done Done) {
+		tokens, err := lexer.Tokenize("ab1c", state, lexer.WithLoopDetection(3))
+		Expect(tokens).To(HaveLen(2))
+		Expect(err).To(MatchError("no progress after 3 iterations at '1'"))
+		Expect(err.(lexer.LexError).Position).To(Equal(lexer.RunePosition(2)))
+		close(done)
+	})
+
+	It("should not halt a state that makes progress (i.e. WithLoopDetection)", func() {
+		tokens, err := lexer.Tokenize("abc", state, lexer.WithLoopDetection(1))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(3))
+	})
+})
@@ -0,0 +1,56 @@
+package lexer
+
+import "fmt"
+
+// WithLossless retains the raw text of every token and captures every rune the lexer does
+// not emit as trivia, including runes left pending or unconsumed when the lexer stops, so
+// that Reconstruct reproduces the input byte-for-byte.
+//
+// Implies WithTrivia(TriviaLeading) unless another trivia mode is specified.
+func WithLossless() Option {
+	return func(l *Lexer) {
+		l.lossless = true
+	}
+}
+
+// Reconstruct concatenates the raw text of the tokens, and of their trivia, emitted by a
+// lexer created with WithLossless and the specified trivia mode.
+func Reconstruct(tokens []Token, mode TriviaMode) string {
+	var b []byte
+	for _, t := range tokens {
+		if mode != TriviaTrailing {
+			b = appendTrivia(b, t.Trivia)
+		}
+		b = append(b, t.Raw...)
+		if mode == TriviaTrailing {
+			b = appendTrivia(b, t.Trivia)
+		}
+	}
+	return string(b)
+}
+
+// Verify returns an error describing the first difference between the input and the text
+// reconstructed from the tokens, or nil if the tokens reproduce the input exactly.
+func Verify(input string, tokens []Token, mode TriviaMode) error {
+	output := Reconstruct(tokens, mode)
+	n := len(input)
+	if len(output) < n {
+		n = len(output)
+	}
+	for i := 0; i < n; i++ {
+		if input[i] != output[i] {
+			return fmt.Errorf("lexer: reconstructed input differs at position %d", i)
+		}
+	}
+	if len(input) != len(output) {
+		return fmt.Errorf("lexer: reconstructed input is %d bytes, expected %d", len(output), len(input))
+	}
+	return nil
+}
+
+func appendTrivia(b []byte, trivia []Token) []byte {
+	for _, t := range trivia {
+		b = append(b, t.Raw...)
+	}
+	return b
+}
@@ -0,0 +1,62 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lossless", func() {
+	const input = "  x := y + 2.0 // sum\n"
+
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.IgnoreUpTo(func(r rune) bool {
+					return !unicode.IsSpace(r)
+				})
+			case r == '/':
+				return l.Errorf("Unexpected input")
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.EmitTrimmed(Token, "=:")
+			}
+		}
+	}
+
+	collect := func(l *lexer.Lexer, n int) []lexer.Token {
+		var tokens []lexer.Token
+		for i := 0; i < n; i++ {
+			tokens = append(tokens, l.NextToken())
+		}
+		return tokens
+	}
+
+	It("should reproduce the input from the emitted tokens (i.e. WithLossless and Reconstruct)", func() {
+		l := lexer.NewLexer(input, state, lexer.WithLossless())
+		tokens := collect(l, 7)
+		Expect(tokens[1].Value).To(Equal(""))
+		Expect(tokens[1].Raw).To(Equal(":="))
+		Expect(lexer.Reconstruct(tokens, lexer.TriviaLeading)).To(Equal(input))
+	})
+
+	It("should reproduce the input when trivia trails tokens", func() {
+		l := lexer.NewLexer(input, state, lexer.WithLossless(), lexer.WithTrivia(lexer.TriviaTrailing))
+		tokens := collect(l, 7)
+		Expect(lexer.Verify(input, tokens, lexer.TriviaTrailing)).To(Succeed())
+	})
+
+	It("should report where the tokens differ from the input (i.e. Verify)", func() {
+		l := lexer.NewLexer(input, state, lexer.WithLossless())
+		tokens := collect(l, 7)
+		tokens[2].Raw = "z"
+		Expect(lexer.Verify(input, tokens, lexer.TriviaLeading)).To(MatchError("lexer: reconstructed input differs at position 7"))
+		Expect(lexer.Verify(input, tokens[:5], lexer.TriviaLeading)).To(HaveOccurred())
+	})
+})
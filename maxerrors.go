@@ -0,0 +1,23 @@
+package lexer
+
+import "fmt"
+
+// TokenTooManyErrors represents a type of token emitted, in place of further tokens, once a
+// lexer created with WithMaxErrors has emitted its maximum number of error tokens.
+const TokenTooManyErrors TokenType = -4
+
+// WithMaxErrors halts the lexer once it has emitted n error tokens, emitting a final
+// TokenTooManyErrors token, to prevent floods of cascading errors when lexing badly
+// corrupted input (e.g. with Recover).
+func WithMaxErrors(n int) Option {
+	return func(l *Lexer) {
+		l.maxErrors = n
+	}
+}
+
+func (l *Lexer) tooManyErrors() {
+	l.sendLocked(Token{Type: TokenTooManyErrors, Value: fmt.Sprintf("too many errors (%d)", l.maxErrors)})
+	if l.state != nil {
+		l.halt()
+	}
+}
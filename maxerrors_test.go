@@ -0,0 +1,50 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MaxErrors", func() {
+	var state lexer.StateFunc
+	state = func(l *lexer.Lexer) lexer.StateFunc {
+		switch r := l.Peek(); r {
+		case lexer.EOF:
+			return nil
+		case '?':
+			l.Errorf("unexpected %q", r)
+			l.Ignore()
+			return state
+		default:
+			l.Next()
+			l.Emit(Token)
+			return state
+		}
+	}
+
+	collect := func(l *lexer.Lexer) []lexer.Token {
+		var tokens []lexer.Token
+		for t := range l.All() {
+			tokens = append(tokens, t)
+		}
+		return tokens
+	}
+
+	It("should halt after the maximum number of error tokens (i.e. WithMaxErrors)", func() {
+		tokens := collect(lexer.NewLexer("a??b??c", state, lexer.WithMaxErrors(3)))
+		Expect(tokens).To(HaveLen(6))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: lexer.TokenError, Value: `unexpected '?'`}))
+		Expect(tokens[3]).To(EqualToken(lexer.Token{Type: Token, Value: "b"}))
+		Expect(tokens[4].Type).To(Equal(lexer.TokenError))
+		Expect(tokens[5]).To(EqualToken(lexer.Token{Type: lexer.TokenTooManyErrors, Value: "too many errors (3)"}))
+		Expect(tokens[5].Position).To(Equal(lexer.RunePosition(4)))
+		Expect(lexer.TokenTooManyErrors.String()).To(Equal("lexer.TOO_MANY_ERRORS"))
+	})
+
+	It("should not limit the number of error tokens by default (i.e. WithMaxErrors)", func() {
+		tokens := collect(lexer.NewLexer("????", state))
+		Expect(tokens).To(HaveLen(4))
+	})
+})
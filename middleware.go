@@ -0,0 +1,41 @@
+package lexer
+
+// Middleware transforms a token emitted by the lexer into the tokens delivered in its
+// place; returning no tokens discards it.
+type Middleware func(Token) []Token
+
+// WithMiddleware applies the middleware, in order, to every token emitted by the lexer.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(l *Lexer) {
+		l.middlewares = append(l.middlewares, middleware...)
+	}
+}
+
+// Use applies the middleware to every token emitted by the lexer from now on, after any
+// middleware already in use.
+//
+// Since the state machine may already be running, tokens emitted before Use is called are
+// not transformed; use WithMiddleware to transform every token.
+func (l *Lexer) Use(middleware Middleware) {
+	l.middlewareMutex.Lock()
+	defer l.middlewareMutex.Unlock()
+	l.middlewares = append(l.middlewares[:len(l.middlewares):len(l.middlewares)], middleware)
+}
+
+func (l *Lexer) middleware() []Middleware {
+	l.middlewareMutex.RLock()
+	defer l.middlewareMutex.RUnlock()
+	return l.middlewares
+}
+
+func applyMiddleware(middleware []Middleware, t Token) []Token {
+	tokens := []Token{t}
+	for _, m := range middleware {
+		var next []Token
+		for _, t := range tokens {
+			next = append(next, m(t)...)
+		}
+		tokens = next
+	}
+	return tokens
+}
@@ -0,0 +1,79 @@
+package lexer_test
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Middleware", func() {
+	const Keyword lexer.TokenType = 1
+
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Token)
+			}
+		}
+	}
+
+	values := func(l *lexer.Lexer) []interface{} {
+		var values []interface{}
+		for t := range l.All() {
+			values = append(values, t.Value)
+		}
+		return values
+	}
+
+	keywords := func(t lexer.Token) []lexer.Token {
+		if t.Value == "if" {
+			t.Type = Keyword
+		}
+		return []lexer.Token{t}
+	}
+
+	It("should transform every emitted token (i.e. WithMiddleware)", func() {
+		dropComments := func(t lexer.Token) []lexer.Token {
+			if strings.HasPrefix(t.Value.(string), "#") {
+				return nil
+			}
+			return []lexer.Token{t}
+		}
+		splitShifts := func(t lexer.Token) []lexer.Token {
+			if t.Value == ">>" {
+				return []lexer.Token{{Type: t.Type, Value: ">"}, {Type: t.Type, Value: ">"}}
+			}
+			return []lexer.Token{t}
+		}
+		l := lexer.NewLexer("if x >> #y z", state, lexer.WithMiddleware(dropComments, splitShifts, keywords), lexer.WithSynchronous())
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Keyword, Value: "if"}))
+		Expect(values(l)).To(Equal([]interface{}{"x", ">", ">", "z"}))
+	})
+
+	It("should transform tokens emitted after the middleware is added (i.e. Use)", func() {
+		var word lexer.StateFunc
+		word = func(l *lexer.Lexer) lexer.StateFunc {
+			l.IgnoreUpTo(func(r rune) bool {
+				return !unicode.IsSpace(r)
+			})
+			if l.NextUpTo(unicode.IsSpace); l.EmitNonEmpty(Token) {
+				return word
+			}
+			return nil
+		}
+		l := lexer.NewLexer("if if", word, lexer.WithSynchronous())
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "if"}))
+		l.Use(keywords)
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Keyword, Value: "if"}))
+	})
+})
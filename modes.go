@@ -0,0 +1,100 @@
+package lexer
+
+// Mode represents a start condition of the lexer, determining which rules apply to the
+// input (see Modes).
+type Mode int
+
+// ModeInitial is the mode a lexer starts in.
+const ModeInitial Mode = 0
+
+// Matcher attempts to lex a token at the current position of the lexer, returning false
+// without consuming input if it does not apply.
+type Matcher func(l *Lexer) bool
+
+// Modes is a flex-like set of rules grouped by mode.
+//
+// The rules of an inclusive mode are tried before the rules of ModeInitial, while only the
+// rules of an exclusive mode apply in that mode.
+type Modes struct {
+	rules     map[Mode][]Matcher
+	exclusive map[Mode]bool
+}
+
+// NewModes creates a set of rules with the specified rules for ModeInitial.
+func NewModes(initial ...Matcher) *Modes {
+	return &Modes{
+		rules:     map[Mode][]Matcher{ModeInitial: initial},
+		exclusive: map[Mode]bool{},
+	}
+}
+
+// Inclusive adds the rules for an inclusive mode and returns the set.
+func (m *Modes) Inclusive(mode Mode, rules ...Matcher) *Modes {
+	m.rules[mode] = append(m.rules[mode], rules...)
+	return m
+}
+
+// Exclusive adds the rules for an exclusive mode and returns the set.
+func (m *Modes) Exclusive(mode Mode, rules ...Matcher) *Modes {
+	m.rules[mode] = append(m.rules[mode], rules...)
+	m.exclusive[mode] = true
+	return m
+}
+
+// State returns a state that lexes the input by trying the rules applying to the active
+// mode in order, until the end of the input.
+//
+// An error token is emitted if no rule applies to the input, unless the lexer was created
+// with WithIllegal.
+func (m *Modes) State() StateFunc {
+	var state StateFunc
+	state = func(l *Lexer) StateFunc {
+		r := l.Peek()
+		if r == EOF {
+			return nil
+		}
+		for _, match := range m.rules[l.mode] {
+			if match(l) {
+				return state
+			}
+		}
+		if l.mode != ModeInitial && !m.exclusive[l.mode] {
+			for _, match := range m.rules[ModeInitial] {
+				if match(l) {
+					return state
+				}
+			}
+		}
+		return l.unmatched(r, state)
+	}
+	return state
+}
+
+// Begin switches the lexer to the specified mode.
+func (l *Lexer) Begin(mode Mode) {
+	l.mode = mode
+}
+
+// PushMode switches the lexer to the specified mode, pushing the active mode onto the lexer's
+// mode stack so that PopMode can return to it (as yy_push_state does in flex).
+func (l *Lexer) PushMode(mode Mode) {
+	l.modeStack = append(l.modeStack, l.mode)
+	l.mode = mode
+}
+
+// PopMode switches the lexer to the mode most recently pushed onto its mode stack.
+//
+// Returns false, leaving the active mode unchanged, if the stack is empty.
+func (l *Lexer) PopMode() bool {
+	if len(l.modeStack) == 0 {
+		return false
+	}
+	l.mode = l.modeStack[len(l.modeStack)-1]
+	l.modeStack = l.modeStack[:len(l.modeStack)-1]
+	return true
+}
+
+// Mode returns the active mode of the lexer.
+func (l *Lexer) Mode() Mode {
+	return l.mode
+}
@@ -0,0 +1,130 @@
+package lexer_test
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Modes", func() {
+	const (
+		Text lexer.TokenType = iota
+		Fence
+		Code
+		Word
+	)
+
+	const (
+		CodeMode lexer.Mode = iota + 1
+		EmphasisMode
+	)
+
+	fence := func(l *lexer.Lexer) bool {
+		if !strings.HasPrefix(l.Input[l.CurrentPosition:], "```") {
+			return false
+		}
+		l.Next()
+		l.Next()
+		l.Next()
+		l.Emit(Fence)
+		if l.Mode() == CodeMode {
+			l.Begin(lexer.ModeInitial)
+		} else {
+			l.Begin(CodeMode)
+		}
+		return true
+	}
+	code := func(l *lexer.Lexer) bool {
+		l.NextUpTo(func(r rune) bool {
+			return r == '`' || r == lexer.EOF
+		})
+		return l.EmitNonEmpty(Code)
+	}
+	space := func(l *lexer.Lexer) bool {
+		if !unicode.IsSpace(l.Peek()) {
+			return false
+		}
+		l.Ignore()
+		return true
+	}
+	text := func(l *lexer.Lexer) bool {
+		l.NextUpTo(func(r rune) bool {
+			return r == '`' || r == '*' || unicode.IsSpace(r) || r == lexer.EOF
+		})
+		return l.EmitNonEmpty(Text)
+	}
+	star := func(l *lexer.Lexer) bool {
+		if l.Peek() != '*' {
+			return false
+		}
+		l.Ignore()
+		if l.Mode() == EmphasisMode {
+			l.Begin(lexer.ModeInitial)
+		} else {
+			l.Begin(EmphasisMode)
+		}
+		return true
+	}
+	word := func(l *lexer.Lexer) bool {
+		if !unicode.IsLetter(l.Peek()) {
+			return false
+		}
+		l.NextUpTo(func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		l.Emit(Word)
+		return true
+	}
+
+	modes := lexer.NewModes(fence, space, star, text).
+		Exclusive(CodeMode, fence, code).
+		Inclusive(EmphasisMode, word)
+
+	tokens := func(input string) ([]lexer.Token, error) {
+		return lexer.Tokenize(input, modes.State())
+	}
+
+	It("should apply only the rules of the active exclusive mode (i.e. Begin and Exclusive)", func() {
+		ts, err := tokens("see ```x *y*``` done")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ts).To(HaveLen(5))
+		Expect(ts[1]).To(EqualToken(lexer.Token{Type: Fence, Value: "```"}))
+		Expect(ts[2]).To(EqualToken(lexer.Token{Type: Code, Value: "x *y*"}))
+		Expect(ts[4]).To(EqualToken(lexer.Token{Type: Text, Value: "done"}))
+	})
+
+	It("should apply the rules of an inclusive mode before the initial rules (i.e. Begin and Inclusive)", func() {
+		ts, err := tokens("a *b 1* c")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ts).To(HaveLen(4))
+		Expect(ts[1]).To(EqualToken(lexer.Token{Type: Word, Value: "b"}))
+		Expect(ts[2]).To(EqualToken(lexer.Token{Type: Text, Value: "1"}))
+		Expect(ts[3]).To(EqualToken(lexer.Token{Type: Text, Value: "c"}))
+	})
+
+	It("should emit an error token when no rule applies (i.e. Modes.State)", func() {
+		_, err := lexer.Tokenize("a", lexer.NewModes(space).State())
+		Expect(err).To(MatchError(`unexpected 'a'`))
+	})
+
+	It("should return to pushed modes (i.e. PushMode and PopMode)", func() {
+		l := lexer.NewLexer("", nil)
+		l.PushMode(CodeMode)
+		l.PushMode(EmphasisMode)
+		Expect(l.Mode()).To(Equal(EmphasisMode))
+		Expect(l.PopMode()).To(BeTrue())
+		Expect(l.Mode()).To(Equal(CodeMode))
+		Expect(l.PopMode()).To(BeTrue())
+		Expect(l.PopMode()).To(BeFalse())
+		Expect(l.Mode()).To(Equal(lexer.ModeInitial))
+	})
+
+	It("should start in the initial mode (i.e. Mode)", func() {
+		l := lexer.NewLexer("", nil)
+		Expect(l.Mode()).To(Equal(lexer.ModeInitial))
+	})
+})
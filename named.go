@@ -0,0 +1,18 @@
+package lexer
+
+// Named returns a state that executes the specified state under a name, so that
+// diagnostics reported and panics raised while it executes identify the state (e.g. "in
+// state 'stringLiteral'") rather than an opaque function.
+func Named(name string, s StateFunc) StateFunc {
+	return func(l *Lexer) StateFunc {
+		l.stateName = name
+		l.coverage.hit(name)
+		return s(l)
+	}
+}
+
+// StateName returns the name of the state the lexer is executing, or an empty string if the
+// state is not named.
+func (l *Lexer) StateName() string {
+	return l.stateName
+}
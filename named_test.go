@@ -0,0 +1,56 @@
+package lexer_test
+
+import (
+	"errors"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Named", func() {
+	It("should report the name of the executing state (i.e. Named and StateName)", func() {
+		var names []string
+		var lexString lexer.StateFunc
+		lexText := lexer.Named("text", func(l *lexer.Lexer) lexer.StateFunc {
+			names = append(names, l.StateName())
+			return lexString
+		})
+		lexString = lexer.Named("stringLiteral", func(l *lexer.Lexer) lexer.StateFunc {
+			names = append(names, l.StateName())
+			return func(l *lexer.Lexer) lexer.StateFunc {
+				names = append(names, l.StateName())
+				return nil
+			}
+		})
+		_, err := lexer.Tokenize("", lexText)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names).To(Equal([]string{"text", "stringLiteral", ""}))
+	})
+
+	It("should identify the state diagnostics are reported in (i.e. Named and Errorf)", func() {
+		l := lexer.NewLexer(`"abc`, lexer.Named("stringLiteral", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Warningf("suspicious %s", "quote")
+			return l.ErrorCodef("E0012", "unterminated string")
+		}))
+		Expect(l.NextToken().Value).To(Equal(lexer.LexError{Message: "suspicious quote", Severity: lexer.SeverityWarning, State: "stringLiteral"}))
+		t := l.NextToken()
+		Expect(t.Value.(error).Error()).To(Equal("E0012 unterminated string (in state 'stringLiteral')"))
+
+		l = lexer.NewLexer("", lexer.Named("text", func(l *lexer.Lexer) lexer.StateFunc {
+			return l.Errorf("unexpected input")
+		}))
+		e, ok := l.NextToken().LexError()
+		Expect(ok).To(BeTrue())
+		Expect(e.State).To(Equal("text"))
+	})
+
+	It("should identify the state panics are raised in (i.e. Named)", func() {
+		l := lexer.NewLexer("", lexer.Named("number", func(l *lexer.Lexer) lexer.StateFunc {
+			panic(errors.New("index out of range"))
+		}))
+		t := l.NextToken()
+		Expect(t.Value.(error).Error()).To(Equal("panic: index out of range (in state 'number')"))
+	})
+})
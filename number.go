@@ -0,0 +1,258 @@
+package lexer
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// TokenInt represents a type of token that contains an integer literal (see ScanNumber).
+	TokenInt TokenType = -6
+
+	// TokenFloat represents a type of token that contains a floating-point literal (see
+	// ScanNumber).
+	TokenFloat TokenType = -7
+)
+
+// SignPolicy determines whether a sign preceding a number literal is part of the literal.
+type SignPolicy int
+
+const (
+	// SignNone leaves signs to be lexed as operators.
+	SignNone SignPolicy = iota
+
+	// SignMinus makes a leading '-' part of the literal.
+	SignMinus
+
+	// SignAny makes a leading '+' or '-' part of the literal.
+	SignAny
+)
+
+// NumberSyntax is the syntax of the number literals of a language (see ScanNumber).
+type NumberSyntax struct {
+	// Hex, Octal, and Binary enable integers prefixed with 0x, 0o, and 0b (in either case).
+	Hex, Octal, Binary bool
+
+	// LegacyOctal makes integers with a leading 0 octal, as in C.
+	LegacyOctal bool
+
+	// Underscores allows underscores separating successive digits, or following a prefix.
+	Underscores bool
+
+	Sign SignPolicy
+
+	// Floats enables decimal floating-point literals with a fraction, an exponent (e.g.
+	// 1e-9), or both. LeadingDot allows fractions without an integer part (e.g. .5), and
+	// TrailingDot integer parts without a fraction (e.g. 1.), unless followed by another dot
+	// (e.g. 1..2).
+	Floats, LeadingDot, TrailingDot bool
+
+	// HexFloats enables hexadecimal floating-point literals with a binary exponent (e.g.
+	// 0x1.8p3), if Hex is enabled.
+	HexFloats bool
+
+	// IntSuffixes and FloatSuffixes are the type suffixes that may follow integer and
+	// floating-point literals (e.g. "u" or "f"); an integer followed by a floating-point
+	// suffix (e.g. 1f) is a floating-point literal. The longest matching suffix is consumed.
+	IntSuffixes, FloatSuffixes []string
+}
+
+// goNumbers is the syntax of the number literals of Go.
+var goNumbers = NumberSyntax{
+	Hex:         true,
+	Octal:       true,
+	Binary:      true,
+	LegacyOctal: true,
+	Underscores: true,
+	Floats:      true,
+	LeadingDot:  true,
+	TrailingDot: true,
+	HexFloats:   true,
+}
+
+// ScanNumber scans a number literal in the syntax of Go, e.g. 42, 0x2A, 0o52, 052, 0b101010,
+// 1_000, 2.5, .5, 1e-9, or 0x1.8p3, at the current position of the lexer, returning the type
+// of the literal, TokenInt or TokenFloat. The literal is consumed, not emitted.
+//
+// Returns an error, a LexError positioned at the offending rune, if the literal is malformed
+// (e.g. 0x or 1__0), having consumed it, or if there is no number at the current position.
+func ScanNumber(l *Lexer) (TokenType, error) {
+	return goNumbers.Scan(l)
+}
+
+// Scan scans a number literal in the syntax at the current position of the lexer, as
+// ScanNumber does.
+func (s NumberSyntax) Scan(l *Lexer) (TokenType, error) {
+	start := l.CurrentPosition
+	if r := l.Peek(); (r == '-' && s.Sign != SignNone || r == '+' && s.Sign == SignAny) && s.startsNumber(l, 1) {
+		l.Next()
+	}
+	if !s.startsNumber(l, 0) {
+		return TokenInt, l.errorAt(start, "expected a number")
+	}
+	n := &numberScanner{l: l, syntax: s}
+	base, prefixed := 10, false
+	if l.Peek() == '0' {
+		switch unicode.ToLower(l.peekAt(1)) {
+		case 'x':
+			base, prefixed = 16, s.Hex
+		case 'o':
+			base, prefixed = 8, s.Octal
+		case 'b':
+			base, prefixed = 2, s.Binary
+		}
+		if prefixed {
+			l.Next()
+			l.Next()
+		} else {
+			base = 10
+		}
+	}
+	digitsStart := l.CurrentPosition
+	digits := n.digits(base, prefixed)
+	float := false
+	if base == 10 && s.Floats || base == 16 && s.HexFloats {
+		if l.Peek() == '.' && (isDigit(l.peekAt(1), base) || digits > 0 && s.TrailingDot && l.peekAt(1) != '.') {
+			l.Next()
+			digits += n.digits(base, false)
+			float = true
+		}
+		exponent := "eE"
+		if base == 16 {
+			exponent = "pP"
+		}
+		if strings.ContainsRune(exponent, l.Peek()) && digits > 0 {
+			l.Next()
+			if r := l.Peek(); r == '+' || r == '-' {
+				l.Next()
+			}
+			if n.digits(10, false) == 0 {
+				n.fail(l.CurrentPosition, "exponent has no digits")
+			}
+			float = true
+		} else if base == 16 && float {
+			n.fail(l.CurrentPosition, "hexadecimal mantissa requires a 'p' exponent")
+		}
+	}
+	switch {
+	case digits == 0:
+		n.fail(l.CurrentPosition, "%s literal has no digits", baseName(base))
+	case base == 10 && !float && s.LegacyOctal && l.Input[digitsStart] == '0':
+		// An integer with a leading 0 is octal, unless it turns out to be a float (e.g. 09.5).
+		for i, r := range l.Input[digitsStart:l.CurrentPosition] {
+			if r == '8' || r == '9' {
+				n.fail(digitsStart+RunePosition(i), "invalid digit %q in octal literal", r)
+			}
+		}
+	}
+	t := TokenInt
+	if float {
+		t = TokenFloat
+	}
+	if suffix, isFloat := s.suffix(l.Input[l.CurrentPosition:], float); suffix != "" {
+		l.advance(len(suffix))
+		if isFloat {
+			t = TokenFloat
+		}
+	}
+	return t, n.err
+}
+
+// startsNumber reports whether a number starts at the rune n runes ahead of the current
+// position of the lexer.
+func (s NumberSyntax) startsNumber(l *Lexer, n int) bool {
+	r := l.peekAt(n)
+	return isDecimal(r) || r == '.' && s.Floats && s.LeadingDot && isDecimal(l.peekAt(n+1))
+}
+
+// suffix returns the longest type suffix at the start of the input, and whether it is a
+// floating-point suffix.
+func (s NumberSyntax) suffix(input string, float bool) (string, bool) {
+	longest, isFloat := "", false
+	if !float {
+		for _, suffix := range s.IntSuffixes {
+			if len(suffix) > len(longest) && strings.HasPrefix(input, suffix) {
+				longest = suffix
+			}
+		}
+	}
+	for _, suffix := range s.FloatSuffixes {
+		if len(suffix) > len(longest) && strings.HasPrefix(input, suffix) {
+			longest, isFloat = suffix, true
+		}
+	}
+	return longest, isFloat
+}
+
+// numberScanner scans the parts of a number literal, recording the first error.
+type numberScanner struct {
+	l      *Lexer
+	syntax NumberSyntax
+	err    error
+}
+
+func (n *numberScanner) fail(position RunePosition, format string, args ...interface{}) {
+	if n.err == nil {
+		n.err = n.l.errorAt(position, format, args...)
+	}
+}
+
+// digits consumes the digits of a literal in the base, and the separators between them,
+// returning the number of digits. Decimal digits are consumed in any base, and invalid ones
+// reported.
+func (n *numberScanner) digits(base int, prefixed bool) int {
+	l := n.l
+	digits := 0
+	previous := rune(0)
+	if prefixed {
+		previous = 'x'
+	}
+	for {
+		r := l.Peek()
+		switch {
+		case r == '_' && n.syntax.Underscores && (previous != 0 || prefixed):
+			if previous == '_' {
+				n.fail(l.CurrentPosition, "'_' must separate successive digits")
+			}
+		case isDecimal(r) || base == 16 && isDigit(r, 16):
+			if !isDigit(r, base) {
+				n.fail(l.CurrentPosition, "invalid digit %q in %s literal", r, baseName(base))
+			}
+			digits++
+		default:
+			if previous == '_' {
+				n.fail(l.CurrentPosition-1, "'_' must separate successive digits")
+			}
+			return digits
+		}
+		previous = r
+		l.Next()
+	}
+}
+
+func isDecimal(r rune) bool {
+	return '0' <= r && r <= '9'
+}
+
+// isDigit reports whether the rune is a digit of the base (up to 16).
+func isDigit(r rune, base int) bool {
+	switch {
+	case '0' <= r && r <= '9':
+		return int(r-'0') < base
+	case 'a' <= unicode.ToLower(r) && unicode.ToLower(r) <= 'f':
+		return base == 16
+	}
+	return false
+}
+
+func baseName(base int) string {
+	switch base {
+	case 16:
+		return "hexadecimal"
+	case 8:
+		return "octal"
+	case 2:
+		return "binary"
+	}
+	return "decimal"
+}
@@ -0,0 +1,97 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Number", func() {
+	scan := func(input string, s lexer.NumberSyntax) string {
+		l := lexer.NewLexer(input, nil)
+		s.Scan(l)
+		return input[:l.CurrentPosition]
+	}
+
+	It("should scan integer literals (i.e. ScanNumber)", func() {
+		for _, input := range []string{"42", "0", "0x2A", "0X_2a", "0o52", "052", "0b101010", "1_000", "0_7"} {
+			l := lexer.NewLexer(input+" x", nil)
+			t, err := lexer.ScanNumber(l)
+			Expect(err).NotTo(HaveOccurred(), input)
+			Expect(t).To(Equal(lexer.TokenInt))
+			Expect(l.Input[:l.CurrentPosition]).To(Equal(input))
+		}
+	})
+
+	It("should scan floating-point literals (i.e. ScanNumber)", func() {
+		for _, input := range []string{"2.5", ".5", "1.", "1e-9", "1.5E+3", "1_0.2_5", "09.5", "0x1.8p3", "0x.8p-1", "0X1P4"} {
+			l := lexer.NewLexer(input+" x", nil)
+			t, err := lexer.ScanNumber(l)
+			Expect(err).NotTo(HaveOccurred(), input)
+			Expect(t).To(Equal(lexer.TokenFloat), input)
+			Expect(l.Input[:l.CurrentPosition]).To(Equal(input))
+		}
+		l := lexer.NewLexer("1..2", nil)
+		Expect(lexer.ScanNumber(l)).To(Equal(lexer.TokenInt))
+		Expect(l.CurrentPosition).To(Equal(lexer.RunePosition(1)))
+	})
+
+	It("should report malformed literals (i.e. ScanNumber)", func() {
+		for input, message := range map[string]string{
+			"0x":    "hexadecimal literal has no digits",
+			"0b12":  "invalid digit '2' in binary literal",
+			"089":   "invalid digit '8' in octal literal",
+			"1__0":  "'_' must separate successive digits",
+			"1_":    "'_' must separate successive digits",
+			"x":     "expected a number",
+			"1e":    "exponent has no digits",
+			"0x1.8": "hexadecimal mantissa requires a 'p' exponent",
+		} {
+			l := lexer.NewLexer(input, nil)
+			_, err := lexer.ScanNumber(l)
+			Expect(err).To(MatchError(message), input)
+		}
+		l := lexer.NewLexer("0b12 3", nil)
+		_, err := lexer.ScanNumber(l)
+		Expect(err.(lexer.LexError).Position).To(Equal(lexer.RunePosition(3)))
+		Expect(l.CurrentPosition).To(Equal(lexer.RunePosition(4)))
+	})
+
+	It("should scan the number literals of other syntaxes (i.e. NumberSyntax)", func() {
+		Expect(scan("0x1_0", lexer.NumberSyntax{})).To(Equal("0"))
+		Expect(scan("012", lexer.NumberSyntax{})).To(Equal("012"))
+		Expect(scan("1_0", lexer.NumberSyntax{})).To(Equal("1"))
+		Expect(scan("-1", lexer.NumberSyntax{Sign: lexer.SignMinus})).To(Equal("-1"))
+		Expect(scan("+1", lexer.NumberSyntax{Sign: lexer.SignMinus})).To(Equal(""))
+		Expect(scan("+1", lexer.NumberSyntax{Sign: lexer.SignAny})).To(Equal("+1"))
+		Expect(scan("-x", lexer.NumberSyntax{Sign: lexer.SignAny})).To(Equal(""))
+		Expect(scan("1.5", lexer.NumberSyntax{})).To(Equal("1"))
+		Expect(scan("1.x", lexer.NumberSyntax{Floats: true})).To(Equal("1"))
+		Expect(scan(".5", lexer.NumberSyntax{Floats: true})).To(Equal(""))
+		Expect(scan("0x1p3", lexer.NumberSyntax{Hex: true, Floats: true})).To(Equal("0x1"))
+
+		c := lexer.NumberSyntax{Hex: true, Floats: true, IntSuffixes: []string{"u", "l", "ul"}, FloatSuffixes: []string{"f"}}
+		for input, tokenType := range map[string]lexer.TokenType{"1ul": lexer.TokenInt, "0x1u": lexer.TokenInt, "1.5f": lexer.TokenFloat, "1f": lexer.TokenFloat} {
+			l := lexer.NewLexer(input, nil)
+			Expect(c.Scan(l)).To(Equal(tokenType), input)
+			Expect(l.Input[:l.CurrentPosition]).To(Equal(input))
+		}
+	})
+
+	It("should report errors preserving their position (i.e. ReportError)", func() {
+		state := func(l *lexer.Lexer) lexer.StateFunc {
+			if _, err := lexer.ScanNumber(l); err != nil {
+				return l.ReportError(err)
+			}
+			l.Emit(lexer.TokenInt)
+			return nil
+		}
+		l := lexer.NewLexer("0x", state)
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: lexer.TokenError, Value: "hexadecimal literal has no digits"}))
+		Expect(l.Diagnostics()[0].Position).To(Equal(lexer.RunePosition(2)))
+		tokens, err := lexer.Tokenize("12", state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(Equal([]lexer.Token{{Type: lexer.TokenInt, Value: "12", Line: 1, Column: 1}}))
+	})
+})
@@ -0,0 +1,9 @@
+package lexer
+
+// WithTrimCutset strips all leading and trailing runes contained in the cutset from the
+// value of every token emitted by Emit.
+func WithTrimCutset(cutset string) Option {
+	return func(l *Lexer) {
+		l.trimCutset = cutset
+	}
+}
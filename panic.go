@@ -0,0 +1,27 @@
+package lexer
+
+import "fmt"
+
+// recoverState stops the state machine once the current state has halted or panicked.
+//
+// A panic is converted into an error token carrying the panic value, the current position,
+// and the name of the state, so that a buggy state neither crashes the process nor strands
+// the consumer.
+func (l *Lexer) recoverState() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	l.state = nil
+	if r == errHalt {
+		return
+	}
+	l.emitMutex.Lock()
+	defer l.emitMutex.Unlock()
+	l.reportLocked(LexError{
+		Message:  fmt.Sprintf("panic: %v", r),
+		Position: l.CurrentPosition + l.origin.Position,
+		Severity: SeverityError,
+		State:    l.stateName,
+	}, false)
+}
@@ -0,0 +1,42 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Panic", func() {
+	It("should convert a panic in a state into an error token (i.e. NextToken)", func(done Done) {
+		l := lexer.NewLexer("ab", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Emit(Token)
+			l.Next()
+			var values []string
+			_ = values[1]
+			return nil
+		})
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "a"}))
+		t := l.NextToken()
+		Expect(t.Type).To(Equal(lexer.TokenError))
+		Expect(t.Value).To(Equal(lexer.LexError{
+			Message:  "panic: runtime error: index out of range [1] with length 0",
+			Position: 2,
+			Severity: lexer.SeverityError,
+		}))
+		close(done)
+	})
+
+	It("should stop the state machine after a panic (i.e. Step)", func() {
+		l := lexer.NewLexer("a", func(l *lexer.Lexer) lexer.StateFunc {
+			panic("unreachable")
+		}, lexer.WithSynchronous())
+		emitted, _ := l.Step()
+		Expect(emitted).To(HaveLen(1))
+		Expect(emitted[0].Value.(lexer.LexError).Message).To(Equal("panic: unreachable"))
+		emitted, done := l.Step()
+		Expect(emitted).To(BeEmpty())
+		Expect(done).To(BeTrue())
+	})
+})
@@ -0,0 +1,95 @@
+// Package participle adapts state functions to the lexer.Definition interface of
+// github.com/alecthomas/participle, so that participle grammars can be driven by
+// hand-written lexers:
+//
+//	def := participle.NewDefinition(lexText, []lexer.TokenType{Ident, Number, Punct})
+//	parser := participle.MustBuild[Program](participle.Lexer(def))
+//
+// Token types are identified in the grammar by their registered names (see
+// lexer.RegisterTokenType).
+package participle
+
+import (
+	"fmt"
+	"io"
+
+	plexer "github.com/alecthomas/participle/v2/lexer"
+	"github.com/eczarny/lexer"
+)
+
+// Definition implements participle's lexer.Definition on top of a state function.
+type Definition struct {
+	state   lexer.StateFunc
+	symbols map[string]plexer.TokenType
+	options []lexer.Option
+}
+
+var _ plexer.StringDefinition = (*Definition)(nil)
+
+// NewDefinition creates a definition that lexes input starting in the specified state,
+// creating each lexer with the options, and which exposes the token types as symbols.
+//
+// Error and warning tokens are not symbols: error tokens are returned as participle errors
+// and warning tokens are skipped.
+func NewDefinition(state lexer.StateFunc, types []lexer.TokenType, options ...lexer.Option) *Definition {
+	symbols := map[string]plexer.TokenType{"EOF": plexer.EOF}
+	for _, t := range types {
+		symbols[t.String()] = plexer.TokenType(t)
+	}
+	return &Definition{state: state, symbols: symbols, options: options}
+}
+
+// Symbols returns the names of the token types, and EOF, mapped to their participle token
+// types.
+func (d *Definition) Symbols() map[string]plexer.TokenType {
+	return d.symbols
+}
+
+// Lex returns a lexer over the contents of the reader.
+func (d *Definition) Lex(filename string, r io.Reader) (plexer.Lexer, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return d.LexString(filename, string(b))
+}
+
+// LexString returns a lexer over the input.
+func (d *Definition) LexString(filename string, input string) (plexer.Lexer, error) {
+	return &participleLexer{
+		lexer:    lexer.NewLexer(input, d.state, d.options...),
+		filename: filename,
+	}, nil
+}
+
+type participleLexer struct {
+	lexer    *lexer.Lexer
+	filename string
+}
+
+func (p *participleLexer) Next() (plexer.Token, error) {
+	for {
+		t, err := p.lexer.ReadToken()
+		if err == io.EOF {
+			pos := plexer.Position{Filename: p.filename, Line: 1, Column: 1}
+			pos.Advance(p.lexer.Input)
+			return plexer.EOFToken(pos), nil
+		}
+		if err != nil {
+			return plexer.Token{}, err
+		}
+		pos := plexer.Position{Filename: p.filename, Offset: int(t.Position), Line: t.Line, Column: t.Column}
+		switch t.Type {
+		case lexer.TokenWarning:
+			continue
+		case lexer.TokenError:
+			e, _ := t.LexError()
+			return plexer.Token{}, &plexer.Error{Msg: e.Error(), Pos: pos}
+		}
+		value, ok := t.Value.(string)
+		if !ok {
+			value = fmt.Sprint(t.Value)
+		}
+		return plexer.Token{Type: plexer.TokenType(t.Type), Value: value, Pos: pos}, nil
+	}
+}
@@ -0,0 +1,13 @@
+package participle_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestParticiple(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Participle Suite")
+}
@@ -0,0 +1,89 @@
+package participle_test
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/alecthomas/participle/v2"
+	plexer "github.com/alecthomas/participle/v2/lexer"
+	"github.com/eczarny/lexer"
+	adapter "github.com/eczarny/lexer/participle"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	Ident lexer.TokenType = 200 + iota
+	Punct
+)
+
+func init() {
+	lexer.RegisterTokenType(Ident, "Ident")
+	lexer.RegisterTokenType(Punct, "Punct")
+}
+
+type Assignment struct {
+	Name  string `parser:"@Ident '='"`
+	Value string `parser:"@Ident ';'"`
+}
+
+type Program struct {
+	Assignments []*Assignment `parser:"@@*"`
+}
+
+var _ = Describe("Participle", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case unicode.IsLetter(r):
+				l.NextUpTo(func(r rune) bool {
+					return !unicode.IsLetter(r)
+				})
+				l.Emit(Ident)
+			case r == '=' || r == ';':
+				l.Next()
+				l.Emit(Punct)
+			default:
+				return l.Errorf("unexpected %q", r)
+			}
+		}
+	}
+
+	def := adapter.NewDefinition(state, []lexer.TokenType{Ident, Punct})
+	parser := participle.MustBuild[Program](participle.Lexer(def))
+
+	It("should expose the token types as symbols (i.e. Symbols)", func() {
+		Expect(def.Symbols()).To(Equal(map[string]plexer.TokenType{
+			"EOF":   plexer.EOF,
+			"Ident": plexer.TokenType(Ident),
+			"Punct": plexer.TokenType(Punct),
+		}))
+	})
+
+	It("should drive a participle grammar (i.e. Lex)", func() {
+		program, err := parser.Parse("input", strings.NewReader("a = b;\nc = d;"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(program.Assignments).To(Equal([]*Assignment{{Name: "a", Value: "b"}, {Name: "c", Value: "d"}}))
+	})
+
+	It("should return the positions of tokens (i.e. LexString)", func() {
+		l, err := def.LexString("input", "a\n=")
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := plexer.ConsumeAll(l)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(3))
+		Expect(tokens[1]).To(Equal(plexer.Token{Type: plexer.TokenType(Punct), Value: "=", Pos: plexer.Position{Filename: "input", Offset: 2, Line: 2, Column: 1}}))
+		Expect(tokens[2].EOF()).To(BeTrue())
+		Expect(tokens[2].Pos).To(Equal(plexer.Position{Filename: "input", Offset: 3, Line: 2, Column: 2}))
+	})
+
+	It("should return error tokens as participle errors (i.e. Lex)", func() {
+		_, err := parser.ParseString("input", "a = $;")
+		Expect(err).To(MatchError(`input:1:5: unexpected '$'`))
+	})
+})
@@ -0,0 +1,47 @@
+package lexer
+
+// Pause suspends the state machine before it transitions to its next state, e.g. so that
+// an interactive tool can inspect the lexer, until Resume is called.
+//
+// Pause does not wait for the current state to finish, and has no effect on a lexer
+// created with WithSynchronous, whose state machine only runs when driven by its consumer.
+func (l *Lexer) Pause() {
+	l.pauseMutex.Lock()
+	defer l.pauseMutex.Unlock()
+	if l.resume == nil {
+		l.resume = make(chan struct{})
+	}
+}
+
+// Resume resumes a state machine suspended by Pause.
+func (l *Lexer) Resume() {
+	l.pauseMutex.Lock()
+	defer l.pauseMutex.Unlock()
+	if l.resume != nil {
+		close(l.resume)
+		l.resume = nil
+	}
+}
+
+// Paused returns true if the lexer has been paused and not yet resumed.
+func (l *Lexer) Paused() bool {
+	l.pauseMutex.Lock()
+	defer l.pauseMutex.Unlock()
+	return l.resume != nil
+}
+
+func (l *Lexer) waitResume() {
+	if l.synchronous {
+		return
+	}
+	l.pauseMutex.Lock()
+	resume := l.resume
+	l.pauseMutex.Unlock()
+	if resume == nil {
+		return
+	}
+	select {
+	case <-resume:
+	case <-l.done:
+	}
+}
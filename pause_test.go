@@ -0,0 +1,61 @@
+package lexer_test
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pause", func() {
+	It("should suspend the state machine until resumed (i.e. Pause and Resume)", func(done Done) {
+		var steps int32
+		var state lexer.StateFunc
+		state = func(l *lexer.Lexer) lexer.StateFunc {
+			if atomic.AddInt32(&steps, 1) == 1 {
+				l.Pause()
+			}
+			if l.Next() == lexer.EOF {
+				return nil
+			}
+			l.Emit(Token)
+			return state
+		}
+		l := lexer.NewLexer("abc", state)
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "a"}))
+		Expect(l.Paused()).To(BeTrue())
+		Consistently(func() int32 {
+			return atomic.LoadInt32(&steps)
+		}, 50*time.Millisecond).Should(Equal(int32(1)))
+		l.Resume()
+		Expect(l.Paused()).To(BeFalse())
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "b"}))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "c"}))
+		close(done)
+	})
+
+	It("should stop waiting once the lexer is closed (i.e. Pause and Close)", func(done Done) {
+		l := lexer.NewLexer("abc", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Pause()
+			return nil
+		})
+		Eventually(l.Paused).Should(BeTrue())
+		l.Close()
+		for range l.Tokens() {
+		}
+		close(done)
+	})
+
+	It("should not suspend a synchronous lexer (i.e. Pause)", func() {
+		l := lexer.NewLexer("a", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Emit(Token)
+			return nil
+		}, lexer.WithSynchronous())
+		l.Pause()
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "a"}))
+	})
+})
@@ -0,0 +1,110 @@
+// Package csv tokenizes delimited text, e.g. comma-separated values as specified by RFC
+// 4180, or tab-separated values:
+//
+//	tokens, err := csv.CSV.Tokenize("a,\"b \"\"c\"\"\"\r\n1,2\r\n")
+//
+// Each field is lexed as a Field token, whose value is the field with its quotes removed,
+// and each line break ending a record as a RecordSep token; unlike encoding/csv, line breaks
+// within quoted fields are preserved. Delimiters, and comment lines, are skipped, and
+// captured as trivia if the lexer was created with lexer.WithTrivia.
+package csv
+
+import "github.com/eczarny/lexer"
+
+// The types of the tokens of delimited text.
+const (
+	Field lexer.TokenType = iota + 1
+	RecordSep
+)
+
+// Dialect is a format of delimited text.
+type Dialect struct {
+	// Delimiter separates the fields of a record.
+	Delimiter rune
+
+	// Quote encloses fields containing delimiters, line breaks, or quotes, which are doubled
+	// within them, or is 0 if fields cannot be quoted.
+	Quote rune
+
+	// Comment starts lines that are skipped, or is 0 if there are none.
+	Comment rune
+
+	// TrimLeadingSpace skips the spaces and tabs at the start of fields, unless they are
+	// delimiters.
+	TrimLeadingSpace bool
+}
+
+// The dialects of comma-separated values, as specified by RFC 4180, and of tab-separated
+// values, as registered with IANA as text/tab-separated-values.
+var (
+	CSV = Dialect{Delimiter: ',', Quote: '"'}
+	TSV = Dialect{Delimiter: '\t'}
+)
+
+// Tokenize tokenizes the delimited text, returning its tokens, or the tokens preceding the
+// first error and the error, a lexer.LexError.
+func (d Dialect) Tokenize(input string, options ...lexer.Option) ([]lexer.Token, error) {
+	return lexer.Tokenize(input, d.State(), options...)
+}
+
+// State returns the state lexing delimited text in the dialect, e.g. to create a lexer with
+// lexer.NewLexer.
+func (d Dialect) State() lexer.StateFunc {
+	var record, field lexer.StateFunc
+	record = func(l *lexer.Lexer) lexer.StateFunc {
+		switch r := l.Peek(); {
+		case r == lexer.EOF:
+			return nil
+		case r == d.Comment && d.Comment != 0:
+			l.IgnoreUpTo(isLineBreak)
+			if l.Ignore() == '\r' && l.Peek() == '\n' {
+				l.Ignore()
+			}
+			return record
+		}
+		return field
+	}
+	field = func(l *lexer.Lexer) lexer.StateFunc {
+		for r := l.Peek(); d.TrimLeadingSpace && (r == ' ' || r == '\t') && r != d.Delimiter; r = l.Peek() {
+			l.Ignore()
+		}
+		if r := l.Peek(); r == d.Quote && d.Quote != 0 {
+			q := string(d.Quote)
+			value, err := lexer.ScanRawString(l, q, q, true)
+			if err != nil {
+				e := err.(lexer.LexError)
+				e.Message = "unterminated quoted field"
+				return l.ReportError(e)
+			}
+			if r := l.Peek(); r != d.Delimiter && !isLineBreak(r) && r != lexer.EOF {
+				return l.Errorf("unexpected %q after quoted field", r)
+			}
+			l.EmitValue(Field, value)
+		} else {
+			for r := l.Peek(); r != d.Delimiter && !isLineBreak(r) && r != lexer.EOF; r = l.Peek() {
+				if r == d.Quote && d.Quote != 0 {
+					return l.Errorf("unexpected %q in unquoted field", r)
+				}
+				l.Next()
+			}
+			l.Emit(Field)
+		}
+		switch r := l.Peek(); {
+		case r == d.Delimiter:
+			l.Ignore()
+			return field
+		case r == '\r' || r == '\n':
+			if l.Next() == '\r' && l.Peek() == '\n' {
+				l.Next()
+			}
+			l.Emit(RecordSep)
+			return record
+		}
+		return nil
+	}
+	return record
+}
+
+func isLineBreak(r rune) bool {
+	return r == '\n' || r == '\r'
+}
@@ -0,0 +1,13 @@
+package csv_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestCSV(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CSV Suite")
+}
@@ -0,0 +1,88 @@
+package csv_test
+
+import (
+	stdcsv "encoding/csv"
+	"strings"
+
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/presets/csv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CSV", func() {
+	// records groups the values of the Field tokens into records.
+	records := func(tokens []lexer.Token) [][]string {
+		records := [][]string{nil}
+		for _, t := range tokens {
+			if t.Type == csv.RecordSep {
+				records = append(records, nil)
+				continue
+			}
+			n := len(records) - 1
+			records[n] = append(records[n], t.Value.(string))
+		}
+		if records[len(records)-1] == nil {
+			records = records[:len(records)-1]
+		}
+		return records
+	}
+
+	It("should tokenize comma-separated values as encoding/csv reads them", func() {
+		for _, input := range []string{
+			"a,b,c\r\n1,2,3\r\n",
+			"a,b\n1,2",
+			"\"a,b\",\"c\"\"d\"\n",
+			"\"multi\nline\",x\r\n",
+			"a,,\n,b,\n",
+			"\"\",x",
+		} {
+			tokens, err := csv.CSV.Tokenize(input)
+			Expect(err).NotTo(HaveOccurred(), input)
+			expected, err := stdcsv.NewReader(strings.NewReader(input)).ReadAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records(tokens)).To(Equal(expected), input)
+		}
+	})
+
+	It("should emit a token for each field and record separator", func() {
+		tokens, err := csv.CSV.Tokenize("a,\"b\"\r\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(Equal([]lexer.Token{
+			{Type: csv.Field, Value: "a", Line: 1, Column: 1},
+			{Type: csv.Field, Value: "b", Position: 2, Line: 1, Column: 3},
+			{Type: csv.RecordSep, Value: "\r\n", Position: 5, Line: 1, Column: 6},
+		}))
+		tokens, err = csv.CSV.Tokenize("\"a\r\nb\"")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens[0].Value).To(Equal("a\r\nb"))
+		l := lexer.NewLexer(`"a""b"`, csv.CSV.State(), lexer.WithLossless())
+		t := l.NextToken()
+		Expect(t.Value).To(Equal(`a"b`))
+		Expect(t.Raw).To(Equal(`"a""b"`))
+	})
+
+	It("should tokenize other dialects", func() {
+		tokens, err := csv.TSV.Tokenize("a b\t\"c\"\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records(tokens)).To(Equal([][]string{{"a b", `"c"`}}))
+		d := csv.Dialect{Delimiter: ';', Quote: '\'', Comment: '#', TrimLeadingSpace: true}
+		tokens, err = d.Tokenize("# header\na;  'b;c'\n#\n d")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records(tokens)).To(Equal([][]string{{"a", "b;c"}, {"d"}}))
+	})
+
+	It("should report malformed quoted fields", func() {
+		for input, message := range map[string]string{
+			"a,\"b":    "unterminated quoted field",
+			"\"a\"b":   `unexpected 'b' after quoted field`,
+			"a\"b\",c": `unexpected '"' in unquoted field`,
+		} {
+			_, err := csv.CSV.Tokenize(input)
+			Expect(err).To(MatchError(message), input)
+		}
+		_, err := csv.CSV.Tokenize("a,\"b")
+		Expect(err.(lexer.LexError).Position).To(Equal(lexer.RunePosition(2)))
+	})
+})
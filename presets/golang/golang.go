@@ -0,0 +1,142 @@
+// Package golang tokenizes Go source as go/scanner does, built from the scanners of the
+// lexer package, e.g. as a benchmark of their correctness against the standard library:
+//
+//	tokens, err := golang.Tokenize(src)
+//	for _, t := range tokens {
+//		fmt.Println(token.Token(t.Type), t.Value)
+//	}
+//
+// The type of each token is the go/token.Token go/scanner scans it as, converted to
+// lexer.TokenType, and its value the text it was lexed from. Semicolons are inserted as the
+// Go specification requires (see Semicolons), with the value "\n". Comments are skipped, and
+// captured as trivia of type token.COMMENT if the lexer was created with lexer.WithTrivia,
+// as is other whitespace.
+package golang
+
+import (
+	"go/token"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/eczarny/lexer"
+)
+
+var operators = map[string]lexer.TokenType{}
+
+var operatorMatcher *lexer.LiteralMatcher
+
+func init() {
+	var literals []string
+	for t := token.ADD; t <= token.TILDE; t++ {
+		if t.IsOperator() {
+			operators[t.String()] = lexer.TokenType(t)
+			literals = append(literals, t.String())
+		}
+	}
+	operatorMatcher = lexer.NewLiteralMatcher(literals...)
+}
+
+// Semicolons inserts the semicolons the Go specification requires after the final token of
+// each line, and of the input, e.g. to apply to the tokens emitted by a lexer in State with
+// lexer.Rewrite.
+var Semicolons = lexer.InsertSemicolons(lexer.SemicolonRules{
+	Style:      lexer.SemicolonGo,
+	Terminator: lexer.Token{Type: lexer.TokenType(token.SEMICOLON), Value: "\n"},
+	Ends: func(t lexer.Token) bool {
+		switch token.Token(t.Type) {
+		case token.IDENT, token.INT, token.FLOAT, token.IMAG, token.CHAR, token.STRING,
+			token.BREAK, token.CONTINUE, token.FALLTHROUGH, token.RETURN,
+			token.INC, token.DEC, token.RPAREN, token.RBRACK, token.RBRACE:
+			return true
+		}
+		return false
+	},
+})
+
+// Tokenize tokenizes the Go source, inserting semicolons, returning its tokens, or the
+// tokens preceding the first error and the error, a lexer.LexError.
+func Tokenize(src string, options ...lexer.Option) ([]lexer.Token, error) {
+	l := lexer.NewLexer(src, State, options...)
+	defer l.Close()
+	var tokens []lexer.Token
+	for t := range lexer.Rewrite(l.All(), Semicolons) {
+		if t.Type == lexer.TokenError {
+			e, _ := t.LexError()
+			if e.Position == 0 {
+				e.Position = t.Position
+			}
+			return tokens, e
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, nil
+}
+
+// State is the state lexing Go source, without inserting semicolons, e.g. to create a lexer
+// with lexer.NewLexer.
+func State(l *lexer.Lexer) lexer.StateFunc {
+	start, rest := l.CurrentPosition, l.Input[l.CurrentPosition:]
+	if rest == "" {
+		return nil
+	}
+	var err error
+	switch r := l.Peek(); {
+	case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+		l.Ignore()
+		return State
+	case isLetter(r):
+		l.NextUpTo(func(r rune) bool { return !isLetter(r) && !isDigit(r) })
+		l.Emit(lexer.TokenType(token.Lookup(l.Input[start:l.CurrentPosition])))
+		return State
+	case '0' <= r && r <= '9' || r == '.' && len(rest) > 1 && '0' <= rest[1] && rest[1] <= '9':
+		var t lexer.TokenType
+		if t, err = lexer.ScanNumber(l); err == nil {
+			switch {
+			case l.Peek() == 'i':
+				l.Next()
+				l.Emit(lexer.TokenType(token.IMAG))
+			case t == lexer.TokenFloat:
+				l.Emit(lexer.TokenType(token.FLOAT))
+			default:
+				l.Emit(lexer.TokenType(token.INT))
+			}
+		}
+	case r == '"':
+		if _, err = lexer.ScanString(l, '"', lexer.GoEscapes); err == nil {
+			l.Emit(lexer.TokenType(token.STRING))
+		}
+	case r == '`':
+		if _, err = lexer.ScanRawString(l, "`", "`", false); err == nil {
+			l.Emit(lexer.TokenType(token.STRING))
+		}
+	case r == '\'':
+		if _, err = lexer.ScanCharLiteral(l, lexer.GoEscapes); err == nil {
+			l.Emit(lexer.TokenType(token.CHAR))
+		}
+	case len(rest) > 1 && rest[:2] == "//":
+		lexer.ScanLineComment(l, "//")
+		l.EmitTrivia(lexer.TokenType(token.COMMENT))
+	case len(rest) > 1 && rest[:2] == "/*":
+		if err = lexer.ScanBlockComment(l, "/*", "*/", false); err == nil {
+			l.EmitTrivia(lexer.TokenType(token.COMMENT))
+		}
+	default:
+		op, ok := l.AcceptAnyLiteral(operatorMatcher)
+		if !ok {
+			return l.Errorf("invalid character %#U", r)
+		}
+		l.Emit(operators[op])
+	}
+	if err != nil {
+		return l.ReportError(err)
+	}
+	return State
+}
+
+func isLetter(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || r == '_' || r >= utf8.RuneSelf && unicode.IsLetter(r)
+}
+
+func isDigit(r rune) bool {
+	return '0' <= r && r <= '9' || r >= utf8.RuneSelf && unicode.IsDigit(r)
+}
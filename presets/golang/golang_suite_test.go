@@ -0,0 +1,13 @@
+package golang_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGolang(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Golang Suite")
+}
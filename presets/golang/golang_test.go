@@ -0,0 +1,101 @@
+package golang_test
+
+import (
+	"go/scanner"
+	"go/token"
+	"os"
+	"path/filepath"
+
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/presets/golang"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Golang", func() {
+	type scanned struct {
+		Tok token.Token
+		Lit string
+	}
+
+	// standard returns the tokens and literals go/scanner scans the source as.
+	standard := func(src string) []scanned {
+		var s scanner.Scanner
+		fset := token.NewFileSet()
+		s.Init(fset.AddFile("", -1, len(src)), []byte(src), func(pos token.Position, msg string) {
+			Fail(pos.String() + ": " + msg)
+		}, 0)
+		var tokens []scanned
+		for {
+			_, tok, lit := s.Scan()
+			if tok == token.EOF {
+				return tokens
+			}
+			if tok.IsOperator() && tok != token.SEMICOLON {
+				lit = tok.String()
+			}
+			tokens = append(tokens, scanned{tok, lit})
+		}
+	}
+
+	// preset returns the tokens and literals the preset tokenizes the source as.
+	preset := func(src string) []scanned {
+		tokens, err := golang.Tokenize(src)
+		Expect(err).NotTo(HaveOccurred())
+		var scannedTokens []scanned
+		for _, t := range tokens {
+			scannedTokens = append(scannedTokens, scanned{token.Token(t.Type), t.Value.(string)})
+		}
+		return scannedTokens
+	}
+
+	It("should tokenize Go source as go/scanner does (i.e. Tokenize)", func() {
+		for _, src := range []string{
+			"package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello, \\u4e16\\u754c\")\n}\n",
+			"x := []int{1, 0x2A, 0o52, 052, 0b101010, 1_000}\ny := 2.5 + .5 + 1e-9 + 0x1.8p3 + 3i + 1.5i\n",
+			"c := 'a' + '\\n' + '\\x41' + '\\u00e9'\ns := `raw\nstring` + \"\\\"quoted\\\"\"\n",
+			"a <<= b &^ c\nd &^= e...\nf <- g; h != i && j || !k\nl++\nm--\nn.o[p:q]()\n",
+			"if x { return } else { break }\n// comment\ny /* block */ = z /* multi\nline */\nfor range ch {\n\tcontinue\n}\n",
+			"switch v := x.(type) {\ncase ~int:\n\tfallthrough\n}\n\u00e9t\u00e9 := \u03c0\n",
+			"x // no trailing newline",
+		} {
+			Expect(preset(src)).To(Equal(standard(src)), src)
+		}
+	})
+
+	It("should tokenize the Go source of the lexer package as go/scanner does (i.e. Tokenize)", func() {
+		files, err := filepath.Glob("../../*.go")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).NotTo(BeEmpty())
+		for _, file := range files {
+			src, err := os.ReadFile(file)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(preset(string(src))).To(Equal(standard(string(src))), file)
+		}
+	})
+
+	It("should capture comments as trivia (i.e. State)", func() {
+		l := lexer.NewLexer("// comment\nx", golang.State, lexer.WithTrivia(lexer.TriviaLeading))
+		defer l.Close()
+		t := l.NextToken()
+		Expect(token.Token(t.Type)).To(Equal(token.IDENT))
+		Expect(t.Trivia).To(HaveLen(2))
+		Expect(t.Trivia[0].Type).To(Equal(lexer.TokenType(token.COMMENT)))
+		Expect(t.Trivia[0].Value).To(Equal("// comment"))
+	})
+
+	It("should report malformed literals (i.e. Tokenize)", func() {
+		for src, message := range map[string]string{
+			"x := \"abc": "unterminated string",
+			"x := `abc":  "unterminated raw string",
+			"x := 'ab'":  "more than one character in character literal",
+			"x := 0x":    "hexadecimal literal has no digits",
+			"/* comment": "unterminated comment",
+			"x := a # b": "invalid character U+0023 '#'",
+		} {
+			_, err := golang.Tokenize(src)
+			Expect(err).To(MatchError(ContainSubstring(message)), src)
+		}
+	})
+})
@@ -0,0 +1,199 @@
+// Package ini tokenizes INI files and Java properties files, e.g.
+//
+//	tokens, err := ini.INI.Tokenize("[server]\nhost = example.com\n; comment\n")
+//
+// Each section header is lexed as a Section token, whose value is the name of the section,
+// each key as a Key token, each value as a Value token, and each comment as a Comment
+// token; the values of keys and values have their surrounding whitespace, escape sequences,
+// and line continuations removed. Whitespace, line breaks, and separators are skipped, and
+// captured as trivia if the lexer was created with lexer.WithTrivia.
+//
+// The package can serve as a template for the lexers of other line-based configuration
+// languages.
+package ini
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/eczarny/lexer"
+)
+
+// The types of the tokens of INI and properties files.
+const (
+	Section lexer.TokenType = iota + 1
+	Key
+	Value
+	Comment
+)
+
+// Dialect is a format of INI-like files.
+type Dialect struct {
+	// Separators are the runes separating keys from values. If WhitespaceSeparates is true
+	// whitespace separates them as well.
+	Separators          string
+	WhitespaceSeparates bool
+
+	// Comments are the runes starting comment lines.
+	Comments string
+
+	// Sections enables section headers (e.g. [section]).
+	Sections bool
+
+	// Escapes enables the escape sequences of properties files in keys and values: \t, \n,
+	// \r, \f, \uHHHH, and any other rune escaping itself (e.g. \= or \ ). Values keep their
+	// trailing whitespace.
+	Escapes bool
+
+	// Continuations continues values ending with a backslash on the next line, skipping its
+	// leading whitespace.
+	Continuations bool
+}
+
+// The dialects of INI files, as Python's configparser reads them by default, and of Java
+// properties files, as java.util.Properties loads them.
+var (
+	INI        = Dialect{Separators: "=:", Comments: ";#", Sections: true}
+	Properties = Dialect{Separators: "=:", WhitespaceSeparates: true, Comments: "#!", Escapes: true, Continuations: true}
+)
+
+// Tokenize tokenizes the file, returning its tokens, or the tokens preceding the first error
+// and the error, a lexer.LexError.
+func (d Dialect) Tokenize(input string, options ...lexer.Option) ([]lexer.Token, error) {
+	return lexer.Tokenize(input, d.State(), options...)
+}
+
+// State returns the state lexing files in the dialect, e.g. to create a lexer with
+// lexer.NewLexer.
+func (d Dialect) State() lexer.StateFunc {
+	var line lexer.StateFunc
+	line = func(l *lexer.Lexer) lexer.StateFunc {
+		skipSpace(l)
+		switch r := l.Peek(); {
+		case r == lexer.EOF:
+			return nil
+		case r == '\n' || r == '\r':
+			l.Ignore()
+			return line
+		case strings.ContainsRune(d.Comments, r):
+			l.NextUpTo(isLineBreak)
+			l.Emit(Comment)
+			return line
+		case r == '[' && d.Sections:
+			return d.section(l, line)
+		}
+		return d.entry(l, line)
+	}
+	return line
+}
+
+func (d Dialect) section(l *lexer.Lexer, line lexer.StateFunc) lexer.StateFunc {
+	l.Next()
+	start := l.CurrentPosition
+	if l.NextUpTo(func(r rune) bool { return r == ']' || isLineBreak(r) }) != ']' {
+		return l.Errorf("unterminated section header")
+	}
+	name := strings.TrimSpace(l.Input[start:l.CurrentPosition])
+	l.Next()
+	l.EmitValue(Section, name)
+	skipSpace(l)
+	if r := l.Peek(); !isLineBreak(r) && r != lexer.EOF && !strings.ContainsRune(d.Comments, r) {
+		return l.Errorf("unexpected %q after section header", r)
+	}
+	return line
+}
+
+func (d Dialect) entry(l *lexer.Lexer, line lexer.StateFunc) lexer.StateFunc {
+	start := l.CurrentPosition
+	key, err := d.scan(l, func(r rune) bool {
+		return strings.ContainsRune(d.Separators, r) || d.WhitespaceSeparates && (r == ' ' || r == '\t')
+	})
+	if err != nil {
+		return l.ReportError(err)
+	}
+	if !d.Escapes {
+		// The whitespace preceding the separator is not part of the key.
+		raw := l.Input[start:l.CurrentPosition]
+		l.CurrentPosition -= lexer.RunePosition(len(raw) - len(strings.TrimRight(raw, " \t")))
+		key = strings.TrimRight(key, " \t")
+	}
+	l.EmitValue(Key, key)
+	skipSpace(l)
+	if r := l.Peek(); r != lexer.EOF && strings.ContainsRune(d.Separators, r) {
+		l.Ignore()
+		skipSpace(l)
+	}
+	if r := l.Peek(); isLineBreak(r) || r == lexer.EOF {
+		return line
+	}
+	value, err := d.scan(l, func(rune) bool { return false })
+	if err != nil {
+		return l.ReportError(err)
+	}
+	if !d.Escapes {
+		value = strings.TrimRight(value, " \t")
+	}
+	l.EmitValue(Value, value)
+	return line
+}
+
+var escapes = map[rune]rune{'t': '\t', 'n': '\n', 'r': '\r', 'f': '\f'}
+
+// scan consumes the runes up to the end of the line, or up to an unescaped rune satisfying
+// the predicate, returning them with escape sequences and line continuations removed.
+// Returns an error if a Unicode escape sequence is malformed.
+func (d Dialect) scan(l *lexer.Lexer, end func(rune) bool) (string, error) {
+	var b strings.Builder
+	for {
+		r := l.Peek()
+		if isLineBreak(r) || r == lexer.EOF || end(r) {
+			return b.String(), nil
+		}
+		l.Next()
+		if r != '\\' || !d.Escapes && !d.Continuations {
+			b.WriteRune(r)
+			continue
+		}
+		switch e := l.Peek(); {
+		case isLineBreak(e) && d.Continuations:
+			if l.Next() == '\r' && l.Peek() == '\n' {
+				l.Next()
+			}
+			skipSpace(l)
+		case e == lexer.EOF:
+		case !d.Escapes:
+			b.WriteRune(r)
+		case e == 'u':
+			l.Next()
+			hex := l.Input[l.CurrentPosition:]
+			if len(hex) > 4 {
+				hex = hex[:4]
+			}
+			v, err := strconv.ParseUint(hex, 16, 16)
+			if len(hex) < 4 || err != nil {
+				return b.String(), errors.New(`malformed \uxxxx escape`)
+			}
+			for i := 0; i < 4; i++ {
+				l.Next()
+			}
+			b.WriteRune(rune(v))
+		default:
+			l.Next()
+			if v, ok := escapes[e]; ok {
+				e = v
+			}
+			b.WriteRune(e)
+		}
+	}
+}
+
+func skipSpace(l *lexer.Lexer) {
+	for r := l.Peek(); r == ' ' || r == '\t' || r == '\f'; r = l.Peek() {
+		l.Ignore()
+	}
+}
+
+func isLineBreak(r rune) bool {
+	return r == '\n' || r == '\r'
+}
@@ -0,0 +1,13 @@
+package ini_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestINI(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "INI Suite")
+}
@@ -0,0 +1,70 @@
+package ini_test
+
+import (
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/presets/ini"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("INI", func() {
+	type token struct {
+		Type  lexer.TokenType
+		Value interface{}
+	}
+
+	lex := func(d ini.Dialect, input string) []token {
+		tokens, err := d.Tokenize(input)
+		Expect(err).NotTo(HaveOccurred())
+		var values []token
+		for _, t := range tokens {
+			values = append(values, token{t.Type, t.Value})
+		}
+		return values
+	}
+
+	It("should tokenize INI files", func() {
+		Expect(lex(ini.INI, "; settings\n[ server ]\nhost name = example.com  \nport: 80\n\n# flags\ndebug\n")).To(Equal([]token{
+			{ini.Comment, "; settings"},
+			{ini.Section, "server"},
+			{ini.Key, "host name"},
+			{ini.Value, "example.com"},
+			{ini.Key, "port"},
+			{ini.Value, "80"},
+			{ini.Comment, "# flags"},
+			{ini.Key, "debug"},
+		}))
+		tokens, err := ini.INI.Tokenize("a = b")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens[0].Value).To(Equal("a"))
+		Expect(tokens[1].Column).To(Equal(5))
+	})
+
+	It("should tokenize Java properties files", func() {
+		Expect(lex(ini.Properties, "! comment\nkey value\na\\=b = c\\td\ntext = one \\\n    two\nname:\\u00e9\nempty\n")).To(Equal([]token{
+			{ini.Comment, "! comment"},
+			{ini.Key, "key"},
+			{ini.Value, "value"},
+			{ini.Key, "a=b"},
+			{ini.Value, "c\td"},
+			{ini.Key, "text"},
+			{ini.Value, "one two"},
+			{ini.Key, "name"},
+			{ini.Value, "é"},
+			{ini.Key, "empty"},
+		}))
+	})
+
+	It("should report malformed lines", func() {
+		for input, message := range map[string]string{
+			"[server":    "unterminated section header",
+			"[server] x": `unexpected 'x' after section header`,
+		} {
+			_, err := ini.INI.Tokenize(input)
+			Expect(err).To(MatchError(message), input)
+		}
+		_, err := ini.Properties.Tokenize(`a = \u00g1`)
+		Expect(err).To(MatchError(`malformed \uxxxx escape`))
+	})
+})
@@ -0,0 +1,129 @@
+// Package json tokenizes JSON text as specified by RFC 8259, e.g.
+//
+//	tokens, err := json.Tokenize(`{"a": [1, 2.5e3, "é"]}`)
+//
+// The value of a String token is the string with its escape sequences replaced (including
+// UTF-16 surrogate pairs), and the value of any other token the text it was lexed from; the
+// text of a String token is its raw text if the lexer was created with lexer.WithLossless.
+// Whitespace is skipped, and captured as trivia if the lexer was created with
+// lexer.WithTrivia.
+package json
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eczarny/lexer"
+)
+
+// The types of the tokens of JSON text.
+const (
+	BeginObject lexer.TokenType = iota + 1
+	EndObject
+	BeginArray
+	EndArray
+	NameSeparator
+	ValueSeparator
+	String
+	Number
+	True
+	False
+	Null
+)
+
+var structural = map[rune]lexer.TokenType{
+	'{': BeginObject,
+	'}': EndObject,
+	'[': BeginArray,
+	']': EndArray,
+	':': NameSeparator,
+	',': ValueSeparator,
+}
+
+var literals = map[string]lexer.TokenType{
+	"true":  True,
+	"false": False,
+	"null":  Null,
+}
+
+// numbers is the number grammar of JSON, except that it prohibits leading zeros.
+var numbers = lexer.NumberSyntax{Sign: lexer.SignMinus, Floats: true}
+
+// Tokenize tokenizes the JSON text, returning its tokens, or the tokens preceding the first
+// error and the error, a lexer.LexError.
+func Tokenize(input string, options ...lexer.Option) ([]lexer.Token, error) {
+	return lexer.Tokenize(input, State, options...)
+}
+
+// State is the state lexing JSON text, e.g. to create a lexer with lexer.NewLexer.
+func State(l *lexer.Lexer) lexer.StateFunc {
+	for {
+		switch r := l.Peek(); {
+		case r == lexer.EOF:
+			return nil
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			l.Ignore()
+		case structural[r] != 0:
+			l.Next()
+			l.Emit(structural[r])
+		case r == '"':
+			return lexString
+		case r == '-' || '0' <= r && r <= '9':
+			return lexNumber
+		case 'a' <= r && r <= 'z':
+			return lexLiteral
+		default:
+			return l.Errorf("invalid character %q", r)
+		}
+	}
+}
+
+func lexString(l *lexer.Lexer) lexer.StateFunc {
+	start := l.CurrentPosition
+	value, err := lexer.ScanString(l, '"', lexer.JSONEscapes)
+	if err != nil {
+		return l.ReportError(err)
+	}
+	raw := l.Input[start:l.CurrentPosition]
+	if !utf8.ValidString(raw) {
+		return l.Errorf("invalid UTF-8 in string")
+	}
+	for _, r := range raw {
+		if r < 0x20 {
+			return l.Errorf("invalid control character %U in string", r)
+		}
+	}
+	l.EmitValue(String, value)
+	return State
+}
+
+func lexNumber(l *lexer.Lexer) lexer.StateFunc {
+	start := l.CurrentPosition
+	if _, err := numbers.Scan(l); err != nil {
+		return l.ReportError(err)
+	}
+	number := l.Input[start:l.CurrentPosition]
+	if digits := strings.TrimPrefix(number, "-"); len(digits) > 1 && digits[0] == '0' && isDigit(digits[1]) {
+		return l.Errorf("invalid number %q: leading zeros are not allowed", number)
+	}
+	l.Emit(Number)
+	return State
+}
+
+func lexLiteral(l *lexer.Lexer) lexer.StateFunc {
+	start := l.CurrentPosition
+	for r := l.Peek(); 'a' <= r && r <= 'z'; r = l.Peek() {
+		l.Next()
+	}
+	word := l.Input[start:l.CurrentPosition]
+	t, ok := literals[word]
+	if !ok {
+		return l.Errorf("invalid literal %q", word)
+	}
+	l.Emit(t)
+	return State
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
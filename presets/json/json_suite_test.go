@@ -0,0 +1,13 @@
+package json_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "JSON Suite")
+}
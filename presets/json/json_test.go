@@ -0,0 +1,74 @@
+package json_test
+
+import (
+	stdjson "encoding/json"
+
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/presets/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON", func() {
+	types := func(tokens []lexer.Token) []lexer.TokenType {
+		var types []lexer.TokenType
+		for _, t := range tokens {
+			types = append(types, t.Type)
+		}
+		return types
+	}
+
+	It("should tokenize JSON text", func() {
+		tokens, err := json.Tokenize(`{"a": [1, -2.5e3, true, false, null], "b": {}}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(types(tokens)).To(Equal([]lexer.TokenType{
+			json.BeginObject, json.String, json.NameSeparator, json.BeginArray,
+			json.Number, json.ValueSeparator, json.Number, json.ValueSeparator,
+			json.True, json.ValueSeparator, json.False, json.ValueSeparator, json.Null, json.EndArray,
+			json.ValueSeparator, json.String, json.NameSeparator, json.BeginObject, json.EndObject, json.EndObject,
+		}))
+		Expect(tokens[6].Value).To(Equal("-2.5e3"))
+		Expect(tokens[6].Column).To(Equal(11))
+	})
+
+	It("should replace the escape sequences of strings as encoding/json does", func() {
+		for _, input := range []string{`"abc"`, `"a\"b\\c\/d"`, `"\b\f\n\r\t"`, `"\u00e9\u4E2D"`, `"\ud83d\ude00"`, `"\ud800"`, `"\ud83dx"`, `"é"`} {
+			tokens, err := json.Tokenize(input)
+			Expect(err).NotTo(HaveOccurred(), input)
+			var expected string
+			Expect(stdjson.Unmarshal([]byte(input), &expected)).To(Succeed())
+			Expect(tokens).To(HaveLen(1))
+			Expect(tokens[0].Value).To(Equal(expected), input)
+		}
+		l := lexer.NewLexer(`"\n"`, json.State, lexer.WithLossless())
+		Expect(l.NextToken().Raw).To(Equal(`"\n"`))
+	})
+
+	It("should accept the numbers encoding/json does", func() {
+		for _, input := range []string{"0", "-0", "12", "1.5", "1e5", "1E+5", "-0.5e-3"} {
+			tokens, err := json.Tokenize(input)
+			Expect(err).NotTo(HaveOccurred(), input)
+			Expect(tokens).To(Equal([]lexer.Token{{Type: json.Number, Value: input, Line: 1, Column: 1}}))
+			Expect(stdjson.Valid([]byte(input))).To(BeTrue())
+		}
+	})
+
+	It("should report invalid JSON text", func() {
+		for input, message := range map[string]string{
+			"01":       `invalid number "01": leading zeros are not allowed`,
+			"-":        "expected a number",
+			"1e":       "exponent has no digits",
+			".5":       "invalid character '.'",
+			"nul":      `invalid literal "nul"`,
+			"TRUE":     "invalid character 'T'",
+			`"a`:       "unterminated string",
+			`"\x41"`:   "unknown escape sequence",
+			"\"a\tb\"": "invalid control character U+0009 in string",
+			"\"\xff\"": "invalid UTF-8 in string",
+		} {
+			_, err := json.Tokenize(input)
+			Expect(err).To(MatchError(message), input)
+		}
+	})
+})
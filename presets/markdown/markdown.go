@@ -0,0 +1,339 @@
+// Package markdown tokenizes the inline structure of Markdown text, as specified by
+// CommonMark, e.g. the content of a paragraph, as a base for custom markup processors:
+//
+//	tokens, err := markdown.Tokenize("Some *emphasis*, `code`, and [a link](https://example.com \"title\").")
+//
+// Runs of emphasis delimiters are lexed as DelimiterRun tokens, whose value is a Delimiter
+// reporting whether the run can open or close emphasis, leaving the pairing of openers and
+// closers to the processor, as the CommonMark delimiter algorithm does. The value of an
+// Escape token is the escaped character, of an Autolink token the link without its angle
+// brackets, of a Title token the title without its quotes, and of any other token the text
+// it was lexed from.
+//
+// The contents of code spans, and the destinations and titles of links, are lexed in modes
+// of their own (see ModeCodeSpan and ModeDestination), in which none of the other tokens
+// are lexed. The contents of code spans are not normalized (i.e. their line breaks, and the
+// spaces at either end, are preserved).
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/eczarny/lexer"
+)
+
+// The types of the tokens of Markdown inline text.
+const (
+	Text lexer.TokenType = iota + 1
+	Escape
+	LineBreak
+	DelimiterRun
+	CodeFence
+	Code
+	LinkOpen
+	ImageOpen
+	LinkClose
+	DestinationOpen
+	Destination
+	Title
+	DestinationClose
+	Autolink
+)
+
+// The modes of the lexer, besides lexer.ModeInitial, in which inline text is lexed.
+const (
+	// ModeCodeSpan is the mode in which the contents of a code span are lexed, up to the
+	// CodeFence closing it.
+	ModeCodeSpan lexer.Mode = iota + 1
+
+	// ModeDestination is the mode in which the destination and title of a link are lexed,
+	// up to the DestinationClose closing them.
+	ModeDestination
+)
+
+// Delimiter is the value of a DelimiterRun token, a run of '*' or '_' delimiters.
+type Delimiter struct {
+	Run string
+
+	// Opens and Closes report whether the run can open and close emphasis, as determined by
+	// the runes either side of it.
+	Opens, Closes bool
+}
+
+var (
+	uriAutolink   = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\x00-\x20<>]*)>`)
+	emailAutolink = regexp.MustCompile(`^<([A-Za-z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*)>`)
+	lineBreak     = regexp.MustCompile(`^(?: {2,}|\\)\r?\n`)
+)
+
+// Tokenize tokenizes the Markdown inline text, returning its tokens, or the tokens preceding
+// the first error and the error, a lexer.LexError.
+func Tokenize(input string, options ...lexer.Option) ([]lexer.Token, error) {
+	return lexer.Tokenize(input, State(), options...)
+}
+
+// State returns the state lexing Markdown inline text, e.g. to create a lexer with
+// lexer.NewLexer.
+func State() lexer.StateFunc {
+	m := &markdownLexer{}
+	return lexer.NewModes(m.lineBreak, m.escape, m.autolink, m.delimiterRun, m.codeFence, m.link, m.text).
+		Exclusive(ModeCodeSpan, m.code).
+		Exclusive(ModeDestination, m.destination).
+		State()
+}
+
+// markdownLexer tracks the length of the backtick run opening the code span the lexer is
+// in, the number of link brackets open, and whether a link destination may follow.
+type markdownLexer struct {
+	fence             int
+	brackets          int
+	expectDestination bool
+}
+
+func (m *markdownLexer) lineBreak(l *lexer.Lexer) bool {
+	loc := lineBreak.FindStringIndex(l.Input[l.CurrentPosition:])
+	if loc == nil {
+		return false
+	}
+	advance(l, loc[1])
+	l.Emit(LineBreak)
+	return true
+}
+
+func (m *markdownLexer) escape(l *lexer.Lexer) bool {
+	rest := l.Input[l.CurrentPosition:]
+	if len(rest) < 2 || rest[0] != '\\' || !isASCIIPunct(rune(rest[1])) {
+		return false
+	}
+	advance(l, 2)
+	l.EmitValue(Escape, rest[1:2])
+	return true
+}
+
+func (m *markdownLexer) autolink(l *lexer.Lexer) bool {
+	rest := l.Input[l.CurrentPosition:]
+	loc := uriAutolink.FindStringSubmatchIndex(rest)
+	if loc == nil {
+		loc = emailAutolink.FindStringSubmatchIndex(rest)
+	}
+	if loc == nil {
+		return false
+	}
+	advance(l, loc[1])
+	l.EmitValue(Autolink, rest[loc[2]:loc[3]])
+	return true
+}
+
+func (m *markdownLexer) delimiterRun(l *lexer.Lexer) bool {
+	rest := l.Input[l.CurrentPosition:]
+	if rest == "" || rest[0] != '*' && rest[0] != '_' {
+		return false
+	}
+	n := len(rest) - len(strings.TrimLeft(rest, rest[:1]))
+	before, _ := utf8.DecodeLastRuneInString(l.Input[:l.CurrentPosition])
+	after, _ := utf8.DecodeRuneInString(rest[n:])
+	left := !isSpace(after) && (!isPunct(after) || isSpace(before) || isPunct(before))
+	right := !isSpace(before) && (!isPunct(before) || isSpace(after) || isPunct(after))
+	d := Delimiter{Run: rest[:n], Opens: left, Closes: right}
+	if rest[0] == '_' {
+		// Underscores cannot open or close emphasis within words.
+		d.Opens = left && (!right || isPunct(before))
+		d.Closes = right && (!left || isPunct(after))
+	}
+	advance(l, n)
+	l.EmitValue(DelimiterRun, d)
+	return true
+}
+
+func (m *markdownLexer) codeFence(l *lexer.Lexer) bool {
+	n := backticks(l.Input[l.CurrentPosition:])
+	if n == 0 {
+		return false
+	}
+	advance(l, n)
+	if !closingFence(l.Input[l.CurrentPosition:], n) {
+		// A backtick run that no run of the same length follows is literal text.
+		l.Emit(Text)
+		return true
+	}
+	l.Emit(CodeFence)
+	m.fence = n
+	l.PushMode(ModeCodeSpan)
+	return true
+}
+
+func (m *markdownLexer) code(l *lexer.Lexer) bool {
+	rest := l.Input[l.CurrentPosition:]
+	if n := backticks(rest); n == m.fence {
+		advance(l, n)
+		l.Emit(CodeFence)
+		l.PopMode()
+		return true
+	}
+	end := len(rest)
+	for i := 0; i < len(rest); {
+		n := backticks(rest[i:])
+		if n == m.fence {
+			end = i
+			break
+		}
+		i += n
+		if n == 0 {
+			i++
+		}
+	}
+	advance(l, end)
+	l.Emit(Code)
+	return true
+}
+
+func (m *markdownLexer) link(l *lexer.Lexer) bool {
+	rest := l.Input[l.CurrentPosition:]
+	switch {
+	case strings.HasPrefix(rest, "!["):
+		advance(l, 2)
+		l.Emit(ImageOpen)
+		m.brackets++
+	case strings.HasPrefix(rest, "["):
+		advance(l, 1)
+		l.Emit(LinkOpen)
+		m.brackets++
+	case strings.HasPrefix(rest, "]") && m.brackets > 0:
+		advance(l, 1)
+		l.Emit(LinkClose)
+		m.brackets--
+		if strings.HasPrefix(rest, "](") {
+			advance(l, 1)
+			l.Emit(DestinationOpen)
+			l.PushMode(ModeDestination)
+			m.expectDestination = true
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// text lexes the text up to the next rune that may start another token.
+func (m *markdownLexer) text(l *lexer.Lexer) bool {
+	rest := l.Input[l.CurrentPosition:]
+	end := len(rest)
+	for i, r := range rest {
+		if i > 0 && (strings.ContainsRune("\\<*_`[]!", r) || lineBreak.MatchString(rest[i:])) {
+			end = i
+			break
+		}
+	}
+	advance(l, end)
+	l.Emit(Text)
+	return true
+}
+
+// destination lexes the destination and title of a link, and the parenthesis closing them,
+// leaving the mode at any other rune, so that the rest of the input is lexed as text.
+func (m *markdownLexer) destination(l *lexer.Lexer) bool {
+	l.IgnoreUpTo(func(r rune) bool { return !isSpace(r) })
+	rest := l.Input[l.CurrentPosition:]
+	destination := m.expectDestination
+	m.expectDestination = false
+	switch {
+	case rest == "":
+		l.PopMode()
+	case rest[0] == ')':
+		advance(l, 1)
+		l.Emit(DestinationClose)
+		l.PopMode()
+	case rest[0] == '"' || rest[0] == '\'' || rest[0] == '(':
+		closing := rest[0]
+		if closing == '(' {
+			closing = ')'
+		}
+		end := closingQuote(rest[1:], closing)
+		if end < 0 {
+			l.PopMode()
+			return true
+		}
+		advance(l, end+2)
+		l.EmitValue(Title, rest[1:end+1])
+	case rest[0] == '<':
+		end := strings.IndexAny(rest, ">\n")
+		if end < 0 || rest[end] != '>' {
+			l.PopMode()
+			return true
+		}
+		advance(l, end+1)
+		l.EmitValue(Destination, rest[1:end])
+	default:
+		if !destination {
+			l.PopMode()
+			return true
+		}
+		depth, end := 0, len(rest)
+		for i, r := range rest {
+			if r == '(' {
+				depth++
+			} else if r == ')' && depth > 0 {
+				depth--
+			} else if r == ')' || isSpace(r) || unicode.IsControl(r) {
+				end = i
+				break
+			}
+		}
+		advance(l, end)
+		l.Emit(Destination)
+	}
+	return true
+}
+
+// backticks returns the length of the backtick run at the start of the text.
+func backticks(s string) int {
+	return len(s) - len(strings.TrimLeft(s, "`"))
+}
+
+// closingFence reports whether the text contains a backtick run of length n.
+func closingFence(s string, n int) bool {
+	for i := 0; i < len(s); i++ {
+		if m := backticks(s[i:]); m > 0 {
+			if m == n {
+				return true
+			}
+			i += m - 1
+		}
+	}
+	return false
+}
+
+// closingQuote returns the index of the closing quote of a title in the text, skipping
+// escaped quotes, or -1 if there is none.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+func isSpace(r rune) bool {
+	return r == utf8.RuneError || unicode.IsSpace(r)
+}
+
+func isPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+func isASCIIPunct(r rune) bool {
+	return r < utf8.RuneSelf && isPunct(r)
+}
+
+func advance(l *lexer.Lexer, n int) {
+	for end := l.CurrentPosition + lexer.RunePosition(n); l.CurrentPosition < end; {
+		l.Next()
+	}
+}
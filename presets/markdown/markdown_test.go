@@ -0,0 +1,119 @@
+package markdown_test
+
+import (
+	"fmt"
+
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/presets/markdown"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Markdown", func() {
+	names := map[lexer.TokenType]string{
+		markdown.Text:             "Text",
+		markdown.Escape:           "Escape",
+		markdown.LineBreak:        "LineBreak",
+		markdown.DelimiterRun:     "DelimiterRun",
+		markdown.CodeFence:        "CodeFence",
+		markdown.Code:             "Code",
+		markdown.LinkOpen:         "LinkOpen",
+		markdown.ImageOpen:        "ImageOpen",
+		markdown.LinkClose:        "LinkClose",
+		markdown.DestinationOpen:  "DestinationOpen",
+		markdown.Destination:      "Destination",
+		markdown.Title:            "Title",
+		markdown.DestinationClose: "DestinationClose",
+		markdown.Autolink:         "Autolink",
+	}
+
+	// tokens returns the types and values of the tokens of the input, as strings.
+	tokens := func(input string) []string {
+		tokens, err := markdown.Tokenize(input)
+		Expect(err).NotTo(HaveOccurred(), input)
+		var s []string
+		for _, t := range tokens {
+			value := t.Value
+			if d, ok := value.(markdown.Delimiter); ok {
+				value = d.Run
+			}
+			s = append(s, fmt.Sprintf("%s %q", names[t.Type], value))
+		}
+		return s
+	}
+
+	// delimiters returns the delimiter runs of the input.
+	delimiters := func(input string) []markdown.Delimiter {
+		tokens, err := markdown.Tokenize(input)
+		Expect(err).NotTo(HaveOccurred(), input)
+		var delimiters []markdown.Delimiter
+		for _, t := range tokens {
+			if t.Type == markdown.DelimiterRun {
+				delimiters = append(delimiters, t.Value.(markdown.Delimiter))
+			}
+		}
+		return delimiters
+	}
+
+	It("should lex text and emphasis delimiter runs (i.e. Tokenize)", func() {
+		Expect(tokens("some *emphasis* and __strong__ text")).To(Equal([]string{
+			`Text "some "`, `DelimiterRun "*"`, `Text "emphasis"`, `DelimiterRun "*"`, `Text " and "`,
+			`DelimiterRun "__"`, `Text "strong"`, `DelimiterRun "__"`, `Text " text"`,
+		}))
+		Expect(tokens("wow! a\nb")).To(Equal([]string{`Text "wow"`, `Text "! a\nb"`}))
+	})
+
+	It("should report whether delimiter runs can open or close emphasis (i.e. Tokenize)", func() {
+		Expect(delimiters("*a* _b_")).To(Equal([]markdown.Delimiter{
+			{Run: "*", Opens: true}, {Run: "*", Closes: true}, {Run: "_", Opens: true}, {Run: "_", Closes: true},
+		}))
+		Expect(delimiters("a*b*c")).To(Equal([]markdown.Delimiter{
+			{Run: "*", Opens: true, Closes: true}, {Run: "*", Opens: true, Closes: true},
+		}))
+		Expect(delimiters("snake_case_name")).To(Equal([]markdown.Delimiter{{Run: "_"}, {Run: "_"}}))
+		Expect(delimiters("a * b")).To(Equal([]markdown.Delimiter{{Run: "*"}}))
+		Expect(delimiters(`"*quoted*"`)).To(Equal([]markdown.Delimiter{
+			{Run: "*", Opens: true}, {Run: "*", Closes: true},
+		}))
+	})
+
+	It("should lex code spans in their own mode (i.e. Tokenize)", func() {
+		Expect(tokens("use `*x*` or ``a ` b``")).To(Equal([]string{
+			`Text "use "`, "CodeFence \"`\"", `Code "*x*"`, "CodeFence \"`\"", `Text " or "`,
+			"CodeFence \"``\"", "Code \"a ` b\"", "CodeFence \"``\"",
+		}))
+		Expect(tokens("a ``b` c")).To(Equal([]string{`Text "a "`, "Text \"``\"", `Text "b"`, "Text \"`\"", `Text " c"`}))
+	})
+
+	It("should lex links and images (i.e. Tokenize)", func() {
+		Expect(tokens(`[a *b*](https://example.com "title") ![img](</a b.png>)`)).To(Equal([]string{
+			`LinkOpen "["`, `Text "a "`, `DelimiterRun "*"`, `Text "b"`, `DelimiterRun "*"`, `LinkClose "]"`,
+			`DestinationOpen "("`, `Destination "https://example.com"`, `Title "title"`, `DestinationClose ")"`,
+			`Text " "`, `ImageOpen "!["`, `Text "img"`, `LinkClose "]"`, `DestinationOpen "("`, `Destination "/a b.png"`,
+			`DestinationClose ")"`,
+		}))
+		Expect(tokens("[ref] [x](a(b)c)")).To(Equal([]string{
+			`LinkOpen "["`, `Text "ref"`, `LinkClose "]"`, `Text " "`, `LinkOpen "["`, `Text "x"`, `LinkClose "]"`,
+			`DestinationOpen "("`, `Destination "a(b)c"`, `DestinationClose ")"`,
+		}))
+		Expect(tokens("a] b")).To(Equal([]string{`Text "a"`, `Text "] b"`}))
+	})
+
+	It("should leave the destination mode at unexpected input (i.e. Tokenize)", func() {
+		Expect(tokens("[a](b c) d")).To(Equal([]string{
+			`LinkOpen "["`, `Text "a"`, `LinkClose "]"`, `DestinationOpen "("`, `Destination "b"`, `Text "c) d"`,
+		}))
+	})
+
+	It("should lex escapes, line breaks, and autolinks (i.e. Tokenize)", func() {
+		Expect(tokens("\\*not\\* \\a  \nb\\\nc")).To(Equal([]string{
+			`Escape "*"`, `Text "not"`, `Escape "*"`, `Text " "`, `Text "\\a"`, `LineBreak "  \n"`, `Text "b"`,
+			`LineBreak "\\\n"`, `Text "c"`,
+		}))
+		Expect(tokens("<https://example.com/a?b> <user@example.com> <not a link>")).To(Equal([]string{
+			`Autolink "https://example.com/a?b"`, `Text " "`, `Autolink "user@example.com"`, `Text " "`,
+			`Text "<not a link>"`,
+		}))
+	})
+})
@@ -0,0 +1,249 @@
+// Package shell splits command lines into words and operators as the POSIX shell does,
+// e.g. for command-line and DSL parsing:
+//
+//	tokens, err := shell.Tokenize(`grep -r "$PATTERN" 'a b' | wc -l`)
+//
+// Each word is lexed as a Word token, whose value is its Parts: its literal text, with
+// quotes and backslash escapes removed, and its expansions (e.g. $HOME, ${HOME}, or $(date))
+// as written, which are not expanded. Each operator (e.g. |, &&, or >>) is lexed as an
+// Operator token, and each line break as a Newline token. Blanks, comments, and escaped line
+// breaks are skipped, and captured as trivia if the lexer was created with lexer.WithTrivia.
+package shell
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/eczarny/lexer"
+)
+
+// The types of the tokens of command lines.
+const (
+	Word lexer.TokenType = iota + 1
+	Operator
+	Newline
+)
+
+// Part is a part of a word.
+type Part struct {
+	// Text is the literal text of the part, or the expansion as written (e.g. ${HOME}).
+	Text string
+
+	// Quoted is true if the part was quoted (by single or double quotes, or a backslash),
+	// and is therefore not subject to field splitting or pathname expansion.
+	Quoted bool
+
+	// Expansion is true if the part is a parameter expansion (e.g. $HOME or ${HOME:-/}),
+	// command substitution (e.g. $(date) or `date`), or arithmetic expansion (e.g. $((1+2))).
+	Expansion bool
+}
+
+// Parts are the parts of a word, the value of a Word token.
+type Parts []Part
+
+// String returns the text of the parts, i.e. the word with its quotes removed.
+func (w Parts) String() string {
+	var b strings.Builder
+	for _, p := range w {
+		b.WriteString(p.Text)
+	}
+	return b.String()
+}
+
+var operators = lexer.NewLiteralMatcher("&&", "||", ";;", "<<", "<<-", ">>", "<&", ">&", "<>", ">|", "|", "&", ";", "<", ">", "(", ")")
+
+// Tokenize tokenizes the command lines, returning their tokens, or the tokens preceding the
+// first error and the error, a lexer.LexError.
+func Tokenize(input string, options ...lexer.Option) ([]lexer.Token, error) {
+	return lexer.Tokenize(input, State, options...)
+}
+
+// State is the state lexing command lines, e.g. to create a lexer with lexer.NewLexer.
+func State(l *lexer.Lexer) lexer.StateFunc {
+	for {
+		rest := l.Input[l.CurrentPosition:]
+		switch {
+		case rest == "":
+			return nil
+		case rest[0] == ' ' || rest[0] == '\t':
+			l.Ignore()
+		case strings.HasPrefix(rest, "\\\n"):
+			l.Ignore()
+			l.Ignore()
+		case rest[0] == '\n':
+			l.Next()
+			l.Emit(Newline)
+		case rest[0] == '#':
+			l.IgnoreUpTo(func(r rune) bool { return r == '\n' })
+		default:
+			if _, ok := l.AcceptAnyLiteral(operators); ok {
+				l.Emit(Operator)
+				continue
+			}
+			return lexWord
+		}
+	}
+}
+
+func lexWord(l *lexer.Lexer) lexer.StateFunc {
+	w := &wordScanner{l: l}
+	for {
+		rest := l.Input[l.CurrentPosition:]
+		if rest == "" || strings.ContainsRune(" \t\n", rune(rest[0])) {
+			break
+		}
+		if _, ok := operators.Match(rest); ok {
+			break
+		}
+		switch r := l.Next(); r {
+		case '\\':
+			switch e := l.Next(); e {
+			case '\n':
+			case lexer.EOF:
+				w.literal("\\", false)
+			default:
+				w.literal(string(e), true)
+			}
+		case '\'':
+			end := strings.IndexByte(rest[1:], '\'')
+			if end < 0 {
+				return l.Errorf("unterminated single-quoted string")
+			}
+			w.literal(rest[1:1+end], true)
+			advance(l, end+1)
+		case '"':
+			if err := w.doubleQuoted(); err != nil {
+				return l.ReportError(err)
+			}
+		case '$', '`':
+			l.Previous()
+			if err := w.expansion(false); err != nil {
+				return l.ReportError(err)
+			}
+		default:
+			w.literal(string(r), false)
+		}
+	}
+	l.EmitValue(Word, w.word)
+	return State
+}
+
+// wordScanner scans the parts of a word.
+type wordScanner struct {
+	l    *lexer.Lexer
+	word Parts
+}
+
+// literal appends literal text to the word, merging it with the preceding part if that is
+// literal text quoted alike.
+func (w *wordScanner) literal(text string, quoted bool) {
+	if n := len(w.word); n > 0 && !w.word[n-1].Expansion && w.word[n-1].Quoted == quoted {
+		w.word[n-1].Text += text
+		return
+	}
+	w.word = append(w.word, Part{Text: text, Quoted: quoted})
+}
+
+// doubleQuoted scans the rest of a double-quoted string, in which a backslash only escapes
+// $, `, ", \, and line breaks.
+func (w *wordScanner) doubleQuoted() error {
+	l := w.l
+	for {
+		switch r := l.Next(); r {
+		case '"':
+			return nil
+		case lexer.EOF:
+			return errors.New("unterminated double-quoted string")
+		case '\\':
+			switch e := l.Next(); {
+			case e == '\n':
+			case e == lexer.EOF:
+				return errors.New("unterminated double-quoted string")
+			case strings.ContainsRune("$`\"\\", e):
+				w.literal(string(e), true)
+			default:
+				w.literal("\\"+string(e), true)
+			}
+		case '$', '`':
+			l.Previous()
+			if err := w.expansion(true); err != nil {
+				return err
+			}
+		default:
+			w.literal(string(r), true)
+		}
+	}
+}
+
+// expansion scans the expansion starting with the $ or ` at the current position, or a
+// literal $ if none does.
+func (w *wordScanner) expansion(quoted bool) error {
+	l := w.l
+	rest := l.Input[l.CurrentPosition:]
+	n := 0
+	switch {
+	case rest[0] == '`':
+		end := closing(rest[1:], '`', '`')
+		if end < 0 {
+			return errors.New("unterminated command substitution")
+		}
+		n = end + 2
+	case strings.HasPrefix(rest, "$("):
+		end := closing(rest[2:], '(', ')')
+		if end < 0 {
+			return errors.New("unterminated command substitution")
+		}
+		n = end + 3
+	case strings.HasPrefix(rest, "${"):
+		end := closing(rest[2:], '{', '}')
+		if end < 0 {
+			return errors.New("unterminated parameter expansion")
+		}
+		n = end + 3
+	case len(rest) > 1 && strings.IndexByte("@*#?$!-0123456789", rest[1]) >= 0:
+		n = 2
+	default:
+		n = 1
+		for n < len(rest) && isName(rest[n], n > 1) {
+			n++
+		}
+		if n == 1 {
+			advance(l, 1)
+			w.literal("$", quoted)
+			return nil
+		}
+	}
+	w.word = append(w.word, Part{Text: rest[:n], Quoted: quoted, Expansion: true})
+	advance(l, n)
+	return nil
+}
+
+// closing returns the index of the delimiter closing the opening delimiter preceding the
+// input, skipping nested pairs of delimiters and escaped runes, or -1 if there is none.
+func closing(input string, open, close byte) int {
+	depth := 0
+	for i := 0; i < len(input); i++ {
+		switch c := input[i]; {
+		case c == '\\':
+			i++
+		case c == close && depth == 0:
+			return i
+		case c == close:
+			depth--
+		case c == open:
+			depth++
+		}
+	}
+	return -1
+}
+
+func isName(c byte, digits bool) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_' || digits && '0' <= c && c <= '9'
+}
+
+// advance consumes n bytes.
+func advance(l *lexer.Lexer, n int) {
+	for end := l.CurrentPosition + lexer.RunePosition(n); l.CurrentPosition < end; {
+		l.Next()
+	}
+}
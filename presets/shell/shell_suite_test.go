@@ -0,0 +1,13 @@
+package shell_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestShell(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shell Suite")
+}
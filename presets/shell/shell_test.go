@@ -0,0 +1,72 @@
+package shell_test
+
+import (
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/presets/shell"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shell", func() {
+	// words returns the words and operators of the tokens, as strings.
+	words := func(input string) []string {
+		tokens, err := shell.Tokenize(input)
+		Expect(err).NotTo(HaveOccurred(), input)
+		var words []string
+		for _, t := range tokens {
+			switch t.Type {
+			case shell.Word:
+				words = append(words, t.Value.(shell.Parts).String())
+			case shell.Newline:
+				words = append(words, "\\n")
+			default:
+				words = append(words, t.Value.(string))
+			}
+		}
+		return words
+	}
+
+	It("should split words removing quotes and escapes", func() {
+		Expect(words(`echo 'a b' "c d" e\ f g"h"'i' \"`)).To(Equal([]string{"echo", "a b", "c d", "e f", "ghi", `"`}))
+		Expect(words(`"a\$b\c" 'a\b'`)).To(Equal([]string{`a$b\c`, `a\b`}))
+		Expect(words("a \\\nb # comment\nc")).To(Equal([]string{"a", "b", "\\n", "c"}))
+		Expect(words("a#b")).To(Equal([]string{"a#b"}))
+	})
+
+	It("should split operators from words", func() {
+		Expect(words("a|b&&c; d >>log 2>&1 &")).To(Equal([]string{"a", "|", "b", "&&", "c", ";", "d", ">>", "log", "2", ">&", "1", "&"}))
+		Expect(words("(cd x)")).To(Equal([]string{"(", "cd", "x", ")"}))
+	})
+
+	It("should preserve the boundaries of expansions", func() {
+		tokens, err := shell.Tokenize(`$HOME/x"${a:-b c}"'$y'$(ls -l)$1 \$z`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(2))
+		Expect(tokens[0].Value).To(Equal(shell.Parts{
+			{Text: "$HOME", Expansion: true},
+			{Text: "/x"},
+			{Text: "${a:-b c}", Quoted: true, Expansion: true},
+			{Text: "$y", Quoted: true},
+			{Text: "$(ls -l)", Expansion: true},
+			{Text: "$1", Expansion: true},
+		}))
+		Expect(tokens[1].Value).To(Equal(shell.Parts{{Text: "$", Quoted: true}, {Text: "z"}}))
+		Expect(words("echo $((1 + (2))) `date` $ a$")).To(Equal([]string{"echo", "$((1 + (2)))", "`date`", "$", "a$"}))
+	})
+
+	It("should report unterminated quotes and expansions", func() {
+		for input, message := range map[string]string{
+			"echo 'a":  "unterminated single-quoted string",
+			`echo "a`:  "unterminated double-quoted string",
+			"echo $(a": "unterminated command substitution",
+			"echo `a":  "unterminated command substitution",
+			"echo ${a": "unterminated parameter expansion",
+		} {
+			_, err := shell.Tokenize(input)
+			Expect(err).To(MatchError(message), input)
+		}
+		_, err := shell.Tokenize(`a "b`)
+		Expect(err.(lexer.LexError).Position).To(Equal(lexer.RunePosition(2)))
+	})
+})
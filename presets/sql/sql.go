@@ -0,0 +1,244 @@
+// Package sql tokenizes SQL statements in the syntax of common dialects, e.g.
+//
+//	tokens, err := sql.PostgreSQL.Tokenize(`SELECT "name" FROM users WHERE id = $1; -- by id`)
+//
+// Keywords are recognized regardless of case, and lexed as Keyword tokens whose value is the
+// keyword as written. The value of a String or QuotedIdentifier token is the string or
+// identifier with its quotes (and escape sequences) removed, and the value of any other
+// token the text it was lexed from. Whitespace is skipped, and captured as trivia if the
+// lexer was created with lexer.WithTrivia.
+package sql
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eczarny/lexer"
+)
+
+// The types of the tokens of SQL statements.
+const (
+	Keyword lexer.TokenType = iota + 1
+	Identifier
+	QuotedIdentifier
+	String
+	Number
+	Parameter
+	Operator
+	Punctuation
+	Comment
+)
+
+// Keywords are the reserved words of SQL common to most dialects.
+var Keywords = []string{
+	"ADD", "ALL", "ALTER", "AND", "ANY", "AS", "ASC", "BETWEEN", "BY", "CASE", "CAST", "CHECK",
+	"COLUMN", "CONSTRAINT", "CREATE", "CROSS", "DEFAULT", "DELETE", "DESC", "DISTINCT", "DROP",
+	"ELSE", "END", "EXCEPT", "EXISTS", "FALSE", "FOREIGN", "FROM", "FULL", "GROUP", "HAVING",
+	"IN", "INDEX", "INNER", "INSERT", "INTERSECT", "INTO", "IS", "JOIN", "KEY", "LEFT", "LIKE",
+	"LIMIT", "NOT", "NULL", "OFFSET", "ON", "OR", "ORDER", "OUTER", "PRIMARY", "REFERENCES",
+	"RIGHT", "SELECT", "SET", "TABLE", "THEN", "TRUE", "UNION", "UNIQUE", "UPDATE", "VALUES",
+	"VIEW", "WHEN", "WHERE", "WITH",
+}
+
+// Dialect is a dialect of SQL.
+type Dialect struct {
+	// Keywords are the keywords of the dialect, in any case.
+	Keywords []string
+
+	// IdentifierQuotes are the pairs of runes quoting identifiers (e.g. `""`, "``", or
+	// "[]"), which are doubled to be included in them.
+	IdentifierQuotes []string
+
+	// DoubleQuotedStrings makes double quotes quote strings rather than identifiers, as
+	// single quotes do.
+	DoubleQuotedStrings bool
+
+	// BackslashEscapes enables backslash escape sequences in strings (e.g. \n or \').
+	BackslashEscapes bool
+
+	// DollarQuotes enables dollar-quoted strings (e.g. $$it's$$ or $fn$...$fn$).
+	DollarQuotes bool
+
+	// HashComments enables comments starting with '#', besides those starting with "--",
+	// and NestedComments block comments nested in block comments.
+	HashComments, NestedComments bool
+
+	// Parameters are the runes starting query parameters: '?' on its own, and '$', ':', or
+	// '@' followed by a number or name (e.g. $1, :name, or @name).
+	Parameters string
+}
+
+// The syntax of standard SQL and of common dialects.
+var (
+	ANSI       = Dialect{Keywords: Keywords, IdentifierQuotes: []string{`""`}}
+	PostgreSQL = Dialect{Keywords: Keywords, IdentifierQuotes: []string{`""`}, DollarQuotes: true, NestedComments: true, Parameters: "$"}
+	MySQL      = Dialect{Keywords: Keywords, IdentifierQuotes: []string{"``"}, DoubleQuotedStrings: true, BackslashEscapes: true, HashComments: true, Parameters: "?"}
+	SQLite     = Dialect{Keywords: Keywords, IdentifierQuotes: []string{`""`, "``", "[]"}, Parameters: "?:@$"}
+	SQLServer  = Dialect{Keywords: Keywords, IdentifierQuotes: []string{`""`, "[]"}, Parameters: "@"}
+)
+
+var operators = lexer.NewLiteralMatcher(
+	"=", "<>", "!=", "<", "<=", ">", ">=", "<=>", "+", "-", "*", "/", "%", "||", "&", "|", "^", "~",
+	"!", "::", "->", "->>", "<<", ">>",
+)
+
+var identifiers = lexer.IdentifierSyntax{Connectors: "_"}
+
+var numbers = lexer.NumberSyntax{Floats: true, LeadingDot: true, TrailingDot: true}
+
+var backslashEscapes = lexer.EscapeSet{
+	Escape: '\\',
+	Simple: map[rune]rune{'0': 0, 'b': '\b', 'n': '\n', 'r': '\r', 't': '\t', 'Z': 0x1A, '"': '"', '\'': '\''},
+
+	// Any other escaped rune denotes itself, e.g. \x denotes x.
+	Permissive: true,
+
+	// Strings may span lines, and a doubled quote denotes the quote itself.
+	Newlines: true,
+	Doubled:  true,
+}
+
+// Tokenize tokenizes the SQL statements, returning their tokens, or the tokens preceding the
+// first error and the error, a lexer.LexError.
+func (d Dialect) Tokenize(input string, options ...lexer.Option) ([]lexer.Token, error) {
+	return lexer.Tokenize(input, d.State(), options...)
+}
+
+// State returns the state lexing SQL statements in the dialect, e.g. to create a lexer with
+// lexer.NewLexer.
+func (d Dialect) State() lexer.StateFunc {
+	keywords := map[string]lexer.TokenType{}
+	for _, k := range d.Keywords {
+		keywords[strings.ToUpper(k)] = Keyword
+	}
+	s := &sqlLexer{Dialect: d, keywords: lexer.Keywords(keywords)}
+	return s.lex
+}
+
+type sqlLexer struct {
+	Dialect
+	keywords *lexer.KeywordTable
+}
+
+func (s *sqlLexer) lex(l *lexer.Lexer) lexer.StateFunc {
+	start, rest := l.CurrentPosition, l.Input[l.CurrentPosition:]
+	if rest == "" {
+		return nil
+	}
+	var err error
+	switch r := l.Peek(); {
+	case r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f':
+		l.Ignore()
+		return s.lex
+	case strings.HasPrefix(rest, "--"):
+		err = lexer.ScanLineComment(l, "--")
+		l.Emit(Comment)
+	case r == '#' && s.HashComments:
+		err = lexer.ScanLineComment(l, "#")
+		l.Emit(Comment)
+	case strings.HasPrefix(rest, "/*"):
+		if err = lexer.ScanBlockComment(l, "/*", "*/", s.NestedComments); err == nil {
+			l.Emit(Comment)
+		}
+	case r == '\'' || r == '"' && s.DoubleQuotedStrings:
+		var value string
+		if s.BackslashEscapes {
+			value, err = lexer.ScanString(l, r, backslashEscapes)
+		} else {
+			value, err = lexer.ScanRawString(l, string(r), string(r), true)
+		}
+		err = unterminated(err, "string")
+		if err == nil {
+			l.EmitValue(String, value)
+		}
+	case s.quote(r) != "":
+		q := s.quote(r)
+		var name string
+		if name, err = lexer.ScanRawString(l, q[:1], q[1:], true); err == nil {
+			l.EmitValue(QuotedIdentifier, name)
+		}
+		err = unterminated(err, "quoted identifier")
+	case r == '$' && s.DollarQuotes && dollarTag(rest) != "":
+		tag := dollarTag(rest)
+		var value string
+		if value, err = lexer.ScanRawString(l, tag, tag, false); err == nil {
+			l.EmitValue(String, value)
+		}
+		err = unterminated(err, "dollar-quoted string")
+	case strings.ContainsRune(s.Parameters, r) && isParameter(r, rest):
+		l.Next()
+		if r != '?' {
+			for identifiers.IsContinue(l.Peek()) {
+				l.Next()
+			}
+		}
+		l.Emit(Parameter)
+	case '0' <= r && r <= '9' || r == '.' && len(rest) > 1 && '0' <= rest[1] && rest[1] <= '9':
+		if _, err = numbers.Scan(l); err == nil {
+			l.Emit(Number)
+		}
+	case identifiers.IsStart(r):
+		identifiers.Scan(l)
+		if _, ok := s.keywords.Lookup(strings.ToUpper(l.Input[start:l.CurrentPosition])); ok {
+			l.Emit(Keyword)
+		} else {
+			l.Emit(Identifier)
+		}
+	case strings.ContainsRune("(),;.", r):
+		l.Next()
+		l.Emit(Punctuation)
+	default:
+		if _, ok := l.AcceptAnyLiteral(operators); !ok {
+			return l.Errorf("unexpected %q", r)
+		}
+		l.Emit(Operator)
+	}
+	if err != nil {
+		return l.ReportError(err)
+	}
+	return s.lex
+}
+
+// unterminated names the literal an error scanning it reports as unterminated, if it is
+// reported as such.
+func unterminated(err error, literal string) error {
+	if e, ok := err.(lexer.LexError); ok && strings.HasPrefix(e.Message, "unterminated ") {
+		e.Message = "unterminated " + literal
+		return e
+	}
+	return err
+}
+
+// quote returns the pair of runes quoting identifiers opened by the rune, if any.
+func (s *sqlLexer) quote(r rune) string {
+	for _, q := range s.IdentifierQuotes {
+		if strings.HasPrefix(q, string(r)) {
+			return q
+		}
+	}
+	return ""
+}
+
+// dollarTag returns the tag of the dollar-quoted string at the start of the input (e.g. $$
+// or $fn$), if any.
+func dollarTag(input string) string {
+	for i, r := range input[1:] {
+		switch {
+		case r == '$':
+			return input[:i+2]
+		case !identifiers.IsContinue(r) || i == 0 && '0' <= r && r <= '9':
+			return ""
+		}
+	}
+	return ""
+}
+
+// isParameter reports whether a parameter starting with the rune starts the input: '?', or
+// another rune followed by a name or number.
+func isParameter(r rune, input string) bool {
+	if r == '?' {
+		return true
+	}
+	next, _ := utf8.DecodeRuneInString(input[1:])
+	return identifiers.IsContinue(next)
+}
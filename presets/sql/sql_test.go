@@ -0,0 +1,110 @@
+package sql_test
+
+import (
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/presets/sql"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SQL", func() {
+	type token struct {
+		Type  lexer.TokenType
+		Value interface{}
+	}
+
+	lex := func(d sql.Dialect, input string) []token {
+		tokens, err := d.Tokenize(input)
+		Expect(err).NotTo(HaveOccurred(), input)
+		var values []token
+		for _, t := range tokens {
+			values = append(values, token{t.Type, t.Value})
+		}
+		return values
+	}
+
+	It("should tokenize statements recognizing keywords regardless of case", func() {
+		Expect(lex(sql.ANSI, "select \"First \"\"Name\"\"\", count(*) FROM users -- all\nWHERE age >= 21.5 AND name <> 'O''Brien';")).To(Equal([]token{
+			{sql.Keyword, "select"},
+			{sql.QuotedIdentifier, `First "Name"`},
+			{sql.Punctuation, ","},
+			{sql.Identifier, "count"},
+			{sql.Punctuation, "("},
+			{sql.Operator, "*"},
+			{sql.Punctuation, ")"},
+			{sql.Keyword, "FROM"},
+			{sql.Identifier, "users"},
+			{sql.Comment, "-- all"},
+			{sql.Keyword, "WHERE"},
+			{sql.Identifier, "age"},
+			{sql.Operator, ">="},
+			{sql.Number, "21.5"},
+			{sql.Keyword, "AND"},
+			{sql.Identifier, "name"},
+			{sql.Operator, "<>"},
+			{sql.String, "O'Brien"},
+			{sql.Punctuation, ";"},
+		}))
+	})
+
+	It("should tokenize the syntax of PostgreSQL", func() {
+		Expect(lex(sql.PostgreSQL, "/* a /* b */ */ $1::text || $$it's$$ || $fn$x$fn$")).To(Equal([]token{
+			{sql.Comment, "/* a /* b */ */"},
+			{sql.Parameter, "$1"},
+			{sql.Operator, "::"},
+			{sql.Identifier, "text"},
+			{sql.Operator, "||"},
+			{sql.String, "it's"},
+			{sql.Operator, "||"},
+			{sql.String, "x"},
+		}))
+	})
+
+	It("should tokenize the syntax of MySQL", func() {
+		Expect(lex(sql.MySQL, "SELECT `order`, \"a\\tb\", 'it\\'s' # comment\n WHERE id = ?")).To(Equal([]token{
+			{sql.Keyword, "SELECT"},
+			{sql.QuotedIdentifier, "order"},
+			{sql.Punctuation, ","},
+			{sql.String, "a\tb"},
+			{sql.Punctuation, ","},
+			{sql.String, "it's"},
+			{sql.Comment, "# comment"},
+			{sql.Keyword, "WHERE"},
+			{sql.Identifier, "id"},
+			{sql.Operator, "="},
+			{sql.Parameter, "?"},
+		}))
+		Expect(lex(sql.MySQL, `SELECT 'a\xb'`)).To(Equal([]token{{sql.Keyword, "SELECT"}, {sql.String, "axb"}}))
+		Expect(lex(sql.MySQL, `SELECT 'it''s'`)).To(Equal([]token{{sql.Keyword, "SELECT"}, {sql.String, "it's"}}))
+		Expect(lex(sql.MySQL, "SELECT 'a\nb'")).To(Equal([]token{{sql.Keyword, "SELECT"}, {sql.String, "a\nb"}}))
+		_, err := sql.MySQL.Tokenize(`'a\'`)
+		Expect(err).To(MatchError("unterminated string"))
+	})
+
+	It("should tokenize the syntax of SQLite and SQL Server", func() {
+		Expect(lex(sql.SQLite, "[a]]b] :name @x $y ?")).To(Equal([]token{
+			{sql.QuotedIdentifier, "a]b"},
+			{sql.Parameter, ":name"},
+			{sql.Parameter, "@x"},
+			{sql.Parameter, "$y"},
+			{sql.Parameter, "?"},
+		}))
+		Expect(lex(sql.SQLServer, "SELECT [name] FROM t WHERE id = @id")[1]).To(Equal(token{sql.QuotedIdentifier, "name"}))
+		Expect(lex(sql.Dialect{Keywords: []string{"select"}}, "SELECT from")).To(Equal([]token{{sql.Keyword, "SELECT"}, {sql.Identifier, "from"}}))
+	})
+
+	It("should report malformed statements", func() {
+		for input, message := range map[string]string{
+			"'abc":  "unterminated string",
+			`"abc`:  "unterminated quoted identifier",
+			"/* a":  "unterminated comment",
+			"a ` b": "unexpected '`'",
+		} {
+			_, err := sql.ANSI.Tokenize(input)
+			Expect(err).To(MatchError(message), input)
+		}
+		_, err := sql.PostgreSQL.Tokenize("$tag$abc")
+		Expect(err).To(MatchError("unterminated dollar-quoted string"))
+	})
+})
@@ -0,0 +1,378 @@
+// Package toml tokenizes TOML documents, as specified by TOML v1.0.0, e.g.
+//
+//	tokens, err := toml.Tokenize("[server]\nhost = \"example.com\"\nstarted = 1979-05-27T07:32:00Z\n")
+//
+// Keys are lexed as BareKey tokens, or String tokens if quoted. The value of a String token
+// is the string with its escape sequences (and, in multi-line strings, line-ending
+// backslashes) replaced, and the value of any other token the text it was lexed from. Line
+// breaks ending key/value pairs and table headers are lexed as Newline tokens, and other
+// whitespace is skipped, and captured as trivia if the lexer was created with
+// lexer.WithTrivia.
+package toml
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eczarny/lexer"
+)
+
+// The types of the tokens of TOML documents.
+const (
+	BareKey lexer.TokenType = iota + 1
+	String
+	Integer
+	Float
+	Boolean
+	DateTime
+	Dot
+	Equals
+	Comma
+	ArrayStart
+	ArrayEnd
+	InlineTableStart
+	InlineTableEnd
+	TableStart
+	TableEnd
+	ArrayTableStart
+	ArrayTableEnd
+	Comment
+	Newline
+)
+
+var escapes = lexer.EscapeSet{
+	Escape:      '\\',
+	Simple:      map[rune]rune{'b': '\b', 't': '\t', 'n': '\n', 'f': '\f', 'r': '\r'},
+	Unicode:     true,
+	LongUnicode: true,
+}
+
+// numbers is the number grammar of TOML, except that it prohibits leading zeros in decimal
+// literals, and signs preceding hexadecimal, octal, and binary literals, which lexNumber
+// rejects.
+var numbers = lexer.NumberSyntax{Hex: true, Octal: true, Binary: true, Underscores: true, Sign: lexer.SignAny, Floats: true}
+
+// Tokenize tokenizes the TOML document, returning its tokens, or the tokens preceding the
+// first error and the error, a lexer.LexError.
+func Tokenize(input string, options ...lexer.Option) ([]lexer.Token, error) {
+	return lexer.Tokenize(input, State(), options...)
+}
+
+// State returns the state lexing a TOML document, e.g. to create a lexer with
+// lexer.NewLexer.
+func State() lexer.StateFunc {
+	t := &tomlLexer{key: true}
+	return t.lex
+}
+
+// tomlLexer tracks whether the lexer is expecting a key or a value, and the arrays and
+// inline tables ('[' and '{') and table header (of depth 1 or 2) it is in.
+type tomlLexer struct {
+	key    bool
+	nested []rune
+	header int
+}
+
+func (t *tomlLexer) top() rune {
+	if n := len(t.nested); n > 0 {
+		return t.nested[n-1]
+	}
+	return 0
+}
+
+func (t *tomlLexer) lex(l *lexer.Lexer) lexer.StateFunc {
+	rest := l.Input[l.CurrentPosition:]
+	if rest == "" {
+		switch {
+		case t.header > 0:
+			return l.Errorf("unterminated table header")
+		case t.top() == '[':
+			return l.Errorf("unterminated array")
+		case t.top() == '{':
+			return l.Errorf("unterminated inline table")
+		}
+		return nil
+	}
+	switch r := rune(rest[0]); {
+	case r == ' ' || r == '\t':
+		l.Ignore()
+	case r == '\n' || strings.HasPrefix(rest, "\r\n"):
+		switch {
+		case t.top() == '[':
+			l.IgnoreUpTo(func(r rune) bool { return r == '\n' })
+			l.Ignore()
+			return t.lex
+		case t.top() == '{' || t.header > 0:
+			return l.Errorf("unexpected line break")
+		}
+		l.NextUpTo(func(r rune) bool { return r == '\n' })
+		l.Next()
+		l.Emit(Newline)
+		t.key = true
+	case r == '#':
+		l.NextUpTo(func(r rune) bool { return r == '\n' || r == '\r' })
+		l.Emit(Comment)
+	case r == ',':
+		emit(l, Comma, 1)
+		t.key = t.top() == '{'
+	case r == '}' && t.top() == '{':
+		t.nested = t.nested[:len(t.nested)-1]
+		emit(l, InlineTableEnd, 1)
+		t.key = false
+	case r == ']' && t.header == 2:
+		if !strings.HasPrefix(rest, "]]") {
+			return l.Errorf("expected ']]' ending array table header")
+		}
+		emit(l, ArrayTableEnd, 2)
+		t.header = 0
+	case r == ']' && t.header == 1:
+		emit(l, TableEnd, 1)
+		t.header = 0
+	case r == ']' && t.top() == '[':
+		t.nested = t.nested[:len(t.nested)-1]
+		emit(l, ArrayEnd, 1)
+		t.key = false
+	case t.key:
+		return t.lexKey(l, rest)
+	default:
+		return t.lexValue(l, rest)
+	}
+	return t.lex
+}
+
+func (t *tomlLexer) lexKey(l *lexer.Lexer, rest string) lexer.StateFunc {
+	switch r := rune(rest[0]); {
+	case r == '[' && len(t.nested) == 0 && t.header == 0:
+		if strings.HasPrefix(rest, "[[") {
+			emit(l, ArrayTableStart, 2)
+			t.header = 2
+		} else {
+			emit(l, TableStart, 1)
+			t.header = 1
+		}
+	case isBareKey(r):
+		l.NextUpTo(func(r rune) bool { return !isBareKey(r) })
+		l.Emit(BareKey)
+	case r == '"' || r == '\'':
+		if strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, "'''") {
+			return l.Errorf("multi-line strings cannot be keys")
+		}
+		return t.lexString(l, rest)
+	case r == '.':
+		emit(l, Dot, 1)
+	case r == '=' && t.header == 0:
+		emit(l, Equals, 1)
+		t.key = false
+	default:
+		return l.Errorf("unexpected %q", r)
+	}
+	return t.lex
+}
+
+func (t *tomlLexer) lexValue(l *lexer.Lexer, rest string) lexer.StateFunc {
+	switch r := rune(rest[0]); {
+	case r == '"' || r == '\'':
+		return t.lexString(l, rest)
+	case r == '[':
+		t.nested = append(t.nested, '[')
+		emit(l, ArrayStart, 1)
+	case r == '{':
+		t.nested = append(t.nested, '{')
+		emit(l, InlineTableStart, 1)
+		t.key = true
+	case strings.HasPrefix(rest, "true"):
+		emit(l, Boolean, 4)
+	case strings.HasPrefix(rest, "false"):
+		emit(l, Boolean, 5)
+	case r == '+' || r == '-' || '0' <= r && r <= '9' || r == 'i' || r == 'n':
+		return t.lexNumber(l, rest)
+	default:
+		return l.Errorf("unexpected %q", r)
+	}
+	return t.lex
+}
+
+func (t *tomlLexer) lexString(l *lexer.Lexer, rest string) lexer.StateFunc {
+	var value string
+	var err error
+	switch {
+	case strings.HasPrefix(rest, `"""`):
+		value, err = scanMultilineBasic(l)
+	case strings.HasPrefix(rest, "'''"):
+		if value, err = lexer.ScanRawString(l, "'''", "'''", false); err == nil {
+			// Up to two quotes may precede the closing delimiter.
+			for i := 0; i < 2 && l.Peek() == '\''; i++ {
+				l.Next()
+				value += "'"
+			}
+			value = trimNewline(value)
+		}
+	case rest[0] == '"':
+		value, err = lexer.ScanString(l, '"', escapes)
+	default:
+		if end := strings.IndexAny(rest[1:], "'\n"); end < 0 || rest[1+end] != '\'' {
+			return l.Errorf("unterminated literal string")
+		}
+		value, err = lexer.ScanRawString(l, "'", "'", false)
+	}
+	if err != nil {
+		return l.ReportError(err)
+	}
+	l.EmitValue(String, value)
+	return t.lex
+}
+
+// scanMultilineBasic scans a multi-line basic string, returning its value.
+func scanMultilineBasic(l *lexer.Lexer) (string, error) {
+	advance(l, 3)
+	var b strings.Builder
+	if rest := l.Input[l.CurrentPosition:]; strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n") {
+		advance(l, len(rest)-len(trimNewline(rest)))
+	}
+	for {
+		rest := l.Input[l.CurrentPosition:]
+		if strings.HasPrefix(rest, `"""`) {
+			// Up to two quotes may precede the closing delimiter.
+			n := len(rest) - len(strings.TrimLeft(rest, `"`))
+			if n > 5 {
+				return b.String(), errors.New("too many quotes ending multi-line string")
+			}
+			b.WriteString(strings.Repeat(`"`, n-3))
+			advance(l, n)
+			return b.String(), nil
+		}
+		r := l.Next()
+		switch {
+		case r == lexer.EOF:
+			return b.String(), errors.New("unterminated multi-line string")
+		case r != '\\':
+			b.WriteRune(r)
+		case lineEnding(rest[1:]):
+			// A line-ending backslash trims the whitespace up to the next non-whitespace rune.
+			trimmed := strings.TrimLeft(rest[1:], " \t\r\n")
+			advance(l, len(rest)-1-len(trimmed))
+		default:
+			v, err := unescape(l)
+			if err != nil {
+				return b.String(), err
+			}
+			b.WriteRune(v)
+		}
+	}
+}
+
+// unescape consumes the escape sequence following a backslash, returning the rune it
+// denotes.
+func unescape(l *lexer.Lexer) (rune, error) {
+	e := l.Next()
+	if v, ok := escapes.Simple[e]; ok {
+		return v, nil
+	}
+	digits := 0
+	switch e {
+	case '"', '\\':
+		return e, nil
+	case 'u':
+		digits = 4
+	case 'U':
+		digits = 8
+	default:
+		return 0, fmt.Errorf("unknown escape sequence \\%c", e)
+	}
+	hex := l.Input[l.CurrentPosition:]
+	if len(hex) > digits {
+		hex = hex[:digits]
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) < digits || err != nil || !utf8.ValidRune(rune(v)) {
+		return 0, errors.New("invalid Unicode escape sequence")
+	}
+	advance(l, digits)
+	return rune(v), nil
+}
+
+func (t *tomlLexer) lexNumber(l *lexer.Lexer, rest string) lexer.StateFunc {
+	start := l.CurrentPosition
+	if _, err := lexer.ScanDateTime(l); l.CurrentPosition > start {
+		if err != nil {
+			return l.ReportError(err)
+		}
+		// A space may separate the date and the time of day.
+		if rest := l.Input[l.CurrentPosition:]; len(rest) > 3 && rest[0] == ' ' && isDigit(rest[1]) && isDigit(rest[2]) && rest[3] == ':' {
+			l.Next()
+			if _, err := lexer.ScanDateTime(l); err != nil {
+				return l.ReportError(err)
+			}
+		}
+		if datetime := l.Input[start:l.CurrentPosition]; !hasSeconds(datetime) {
+			return l.Errorf("invalid time %q: seconds are required", datetime)
+		}
+		l.Emit(DateTime)
+		return t.lex
+	}
+	unsigned := strings.TrimLeft(rest, "+-")
+	if sign := len(rest) - len(unsigned); sign <= 1 && (strings.HasPrefix(unsigned, "inf") || strings.HasPrefix(unsigned, "nan")) {
+		emit(l, Float, sign+3)
+		return t.lex
+	}
+	typ, err := numbers.Scan(l)
+	if err != nil {
+		return l.ReportError(err)
+	}
+	number := l.Input[start:l.CurrentPosition]
+	if len(unsigned) < len(rest) && len(unsigned) > 1 && unsigned[0] == '0' && strings.ContainsRune("xob", rune(unsigned[1])) {
+		return l.Errorf("invalid number %q: signs are only allowed before decimal literals", number)
+	}
+	if digits := strings.TrimLeft(number, "+-"); len(digits) > 1 && digits[0] == '0' && (isDigit(digits[1]) || digits[1] == '_') {
+		return l.Errorf("invalid number %q: leading zeros are not allowed", number)
+	}
+	if typ == lexer.TokenFloat {
+		l.Emit(Float)
+	} else {
+		l.Emit(Integer)
+	}
+	return t.lex
+}
+
+// hasSeconds reports whether the time of day of the date-time, if it has one, has seconds.
+func hasSeconds(datetime string) bool {
+	i := strings.IndexByte(datetime, ':')
+	return i < 0 || len(datetime) > i+3 && datetime[i+3] == ':'
+}
+
+// emit consumes n bytes and emits them as a token of the specified type.
+func emit(l *lexer.Lexer, tokenType lexer.TokenType, n int) {
+	advance(l, n)
+	l.Emit(tokenType)
+}
+
+// advance consumes n bytes.
+func advance(l *lexer.Lexer, n int) {
+	for end := l.CurrentPosition + lexer.RunePosition(n); l.CurrentPosition < end; {
+		l.Next()
+	}
+}
+
+// lineEnding reports whether only spaces and tabs precede the end of the line.
+func lineEnding(s string) bool {
+	s = strings.TrimLeft(s, " \t")
+	return strings.HasPrefix(s, "\n") || strings.HasPrefix(s, "\r\n")
+}
+
+func trimNewline(s string) string {
+	if strings.HasPrefix(s, "\r\n") {
+		return s[2:]
+	}
+	return strings.TrimPrefix(s, "\n")
+}
+
+func isBareKey(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_' || r == '-'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
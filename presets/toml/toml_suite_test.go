@@ -0,0 +1,13 @@
+package toml_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestTOML(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TOML Suite")
+}
@@ -0,0 +1,121 @@
+package toml_test
+
+import (
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/presets/toml"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TOML", func() {
+	type token struct {
+		Type  lexer.TokenType
+		Value interface{}
+	}
+
+	lex := func(input string) []token {
+		tokens, err := toml.Tokenize(input)
+		Expect(err).NotTo(HaveOccurred(), input)
+		var values []token
+		for _, t := range tokens {
+			values = append(values, token{t.Type, t.Value})
+		}
+		return values
+	}
+
+	It("should tokenize key/value pairs and table headers", func() {
+		Expect(lex("# config\n[server.\"main\"]\nhost = 'example.com' # host\n\n[[ports]]\n")).To(Equal([]token{
+			{toml.Comment, "# config"},
+			{toml.Newline, "\n"},
+			{toml.TableStart, "["},
+			{toml.BareKey, "server"},
+			{toml.Dot, "."},
+			{toml.String, "main"},
+			{toml.TableEnd, "]"},
+			{toml.Newline, "\n"},
+			{toml.BareKey, "host"},
+			{toml.Equals, "="},
+			{toml.String, "example.com"},
+			{toml.Comment, "# host"},
+			{toml.Newline, "\n"},
+			{toml.Newline, "\n"},
+			{toml.ArrayTableStart, "[["},
+			{toml.BareKey, "ports"},
+			{toml.ArrayTableEnd, "]]"},
+			{toml.Newline, "\n"},
+		}))
+	})
+
+	It("should tokenize arrays and inline tables", func() {
+		Expect(lex("a = [\n  1, [true],\n]\nb = {x = 1, y = {}}")).To(Equal([]token{
+			{toml.BareKey, "a"}, {toml.Equals, "="}, {toml.ArrayStart, "["},
+			{toml.Integer, "1"}, {toml.Comma, ","}, {toml.ArrayStart, "["}, {toml.Boolean, "true"}, {toml.ArrayEnd, "]"}, {toml.Comma, ","},
+			{toml.ArrayEnd, "]"}, {toml.Newline, "\n"},
+			{toml.BareKey, "b"}, {toml.Equals, "="}, {toml.InlineTableStart, "{"},
+			{toml.BareKey, "x"}, {toml.Equals, "="}, {toml.Integer, "1"}, {toml.Comma, ","},
+			{toml.BareKey, "y"}, {toml.Equals, "="}, {toml.InlineTableStart, "{"}, {toml.InlineTableEnd, "}"},
+			{toml.InlineTableEnd, "}"},
+		}))
+	})
+
+	It("should tokenize strings", func() {
+		for input, value := range map[string]string{
+			`"a\tb\u00e9\U0001F600"`:       "a\tbé😀",
+			`'C:\path'`:                    `C:\path`,
+			"\"\"\"\nline 1\nline 2\"\"\"": "line 1\nline 2",
+			"\"\"\"a \\\n\n    b\"\"\"":    "a b",
+			`"""quoted "" and """"`:        `quoted "" and "`,
+			"'''\nraw \\n\n'''":            "raw \\n\n",
+			"''''quoted'''''":              "'quoted''",
+		} {
+			Expect(lex("k = "+input)).To(Equal([]token{{toml.BareKey, "k"}, {toml.Equals, "="}, {toml.String, value}}), input)
+		}
+	})
+
+	It("should tokenize numbers, booleans, and datetimes", func() {
+		for input, tokenType := range map[string]lexer.TokenType{
+			"42":                               toml.Integer,
+			"-1_000":                           toml.Integer,
+			"0xDEAD_beef":                      toml.Integer,
+			"0o755":                            toml.Integer,
+			"0b1101":                           toml.Integer,
+			"+3.14":                            toml.Float,
+			"5e+22":                            toml.Float,
+			"-inf":                             toml.Float,
+			"nan":                              toml.Float,
+			"false":                            toml.Boolean,
+			"1979-05-27T07:32:00Z":             toml.DateTime,
+			"1979-05-27T00:32:00.999999-07:00": toml.DateTime,
+			"1979-05-27 07:32:00":              toml.DateTime,
+			"1979-05-27":                       toml.DateTime,
+			"07:32:00":                         toml.DateTime,
+		} {
+			Expect(lex("k = "+input)).To(Equal([]token{{toml.BareKey, "k"}, {toml.Equals, "="}, {tokenType, input}}), input)
+		}
+	})
+
+	It("should report malformed documents", func() {
+		for input, message := range map[string]string{
+			"k = 01":                `invalid number "01": leading zeros are not allowed`,
+			"k = -0xFF":             `invalid number "-0xFF": signs are only allowed before decimal literals`,
+			"k = +0o7":              `invalid number "+0o7": signs are only allowed before decimal literals`,
+			"k = 07:32":             `invalid time "07:32": seconds are required`,
+			"k = 1979-05-27T07:32Z": `invalid time "1979-05-27T07:32Z": seconds are required`,
+			"k = 'a\n'":             "unterminated literal string",
+			`k = "a`:                "unterminated string",
+			`k = "\x"`:              "unknown escape sequence",
+			"k = \"\"\"a":           "unterminated multi-line string",
+			"k = [1":                "unterminated array",
+			"k = {a = 1\n}":         "unexpected line break",
+			"[a\n":                  "unexpected line break",
+			"[[a]":                  "expected ']]' ending array table header",
+			`"""k""" = 1`:           "multi-line strings cannot be keys",
+			"k = 1979-13-01":        "month out of range",
+			"k = ?":                 `unexpected '?'`,
+		} {
+			_, err := toml.Tokenize(input)
+			Expect(err).To(MatchError(message), input)
+		}
+	})
+})
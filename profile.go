@@ -0,0 +1,96 @@
+package lexer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Profile records the number of tokens each rule of a Rules table was applied to, and the
+// time spent lexing them, so that language implementers can find which rules dominate
+// lexing time.
+//
+// A profile may be shared by any number of lexers, including concurrently running ones.
+type Profile struct {
+	mutex sync.Mutex
+	rules map[ruleKey]*RuleProfile
+}
+
+type ruleKey struct {
+	mode Mode
+	rule int
+}
+
+// RuleProfile is the profile of a rule (see Profile).
+type RuleProfile struct {
+	// Mode is the mode the rule was added to, and Rule the index of the rule in the order the
+	// rules of the mode were added.
+	Mode Mode
+	Rule int
+	Type TokenType
+
+	// Hits is the number of times the rule was applied, and Time the cumulative time spent
+	// matching the input it was applied to, including trying the rules that did not apply.
+	Hits int
+	Time time.Duration
+}
+
+// NewProfile creates an empty profile.
+func NewProfile() *Profile {
+	return &Profile{rules: map[ruleKey]*RuleProfile{}}
+}
+
+// WithProfile records the rules applied by the lexer in the profile. Emitting the tokens of
+// the rules is not timed, except by rules written as a Matcher, and neither are the rules
+// added at runtime to DynamicRules.
+func WithProfile(p *Profile) Option {
+	return func(l *Lexer) {
+		l.profile = p
+	}
+}
+
+// Rules returns the profiles of the rules that were applied, in order of decreasing time.
+func (p *Profile) Rules() []RuleProfile {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	rules := make([]RuleProfile, 0, len(p.rules))
+	for _, r := range p.rules {
+		rules = append(rules, *r)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Time != rules[j].Time {
+			return rules[i].Time > rules[j].Time
+		}
+		if rules[i].Mode != rules[j].Mode {
+			return rules[i].Mode < rules[j].Mode
+		}
+		return rules[i].Rule < rules[j].Rule
+	})
+	return rules
+}
+
+// String returns a report of the mode, index, token type, hits, and time of each rule that
+// was applied, in order of decreasing time.
+func (p *Profile) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-6s %-6s %-16s %10s %14s\n", "mode", "rule", "type", "hits", "time")
+	for _, r := range p.Rules() {
+		fmt.Fprintf(&b, "%-6d %-6d %-16v %10d %14v\n", r.Mode, r.Rule, r.Type, r.Hits, r.Time)
+	}
+	return b.String()
+}
+
+func (p *Profile) record(mode Mode, u *rule, elapsed time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	key := ruleKey{mode, u.index}
+	r, ok := p.rules[key]
+	if !ok {
+		r = &RuleProfile{Mode: mode, Rule: u.index, Type: u.tokenType}
+		p.rules[key] = r
+	}
+	r.Hits++
+	r.Time += elapsed
+}
@@ -0,0 +1,67 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Profile", func() {
+	const (
+		Identifier lexer.TokenType = iota
+		Number
+		Quote
+	)
+
+	const Quoted lexer.Mode = 1
+
+	rules := lexer.NewRules().
+		Skip(lexer.Class(`\s`)).
+		Add(Number, lexer.Class(`\d`)).
+		Add(Identifier, lexer.Class(`[a-z]`)).
+		Add(Quote, lexer.Literal(`"`)).Begin(Quoted).
+		Inclusive(Quoted, lexer.NewRules().
+			Add(Quote, lexer.Literal(`"`)).Begin(lexer.ModeInitial).
+			Add(Identifier, lexer.Class(`[A-Z]`)))
+
+	hits := func(p *lexer.Profile) map[[2]int]int {
+		hits := map[[2]int]int{}
+		for _, r := range p.Rules() {
+			hits[[2]int{int(r.Mode), r.Rule}] = r.Hits
+		}
+		return hits
+	}
+
+	It("should record the rules applied by the lexer (i.e. WithProfile)", func() {
+		state, err := rules.State()
+		Expect(err).NotTo(HaveOccurred())
+		p := lexer.NewProfile()
+		_, err = lexer.Tokenize(`a 1 b "C 2 d"`, state, lexer.WithProfile(p))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hits(p)).To(Equal(map[[2]int]int{
+			{0, 0}: 5,
+			{0, 1}: 2,
+			{0, 2}: 3,
+			{0, 3}: 1,
+			{1, 0}: 1,
+			{1, 1}: 1,
+		}))
+		r := p.Rules()
+		for i := 1; i < len(r); i++ {
+			Expect(r[i-1].Time).To(BeNumerically(">=", r[i].Time))
+		}
+		Expect(p.String()).To(HavePrefix("mode   rule   type"))
+	})
+
+	It("should record the rules of dynamic tables (i.e. WithProfile)", func() {
+		d, err := rules.Dynamic()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = d.Add(Number, lexer.Literal("+"))
+		Expect(err).NotTo(HaveOccurred())
+		p := lexer.NewProfile()
+		_, err = lexer.Tokenize("a+1", d.State(), lexer.WithProfile(p))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hits(p)).To(Equal(map[[2]int]int{{0, 1}: 1, {0, 2}: 1}))
+	})
+})
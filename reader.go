@@ -0,0 +1,62 @@
+package lexer
+
+import (
+	"context"
+	"io"
+)
+
+// TokenReader is implemented by sources that tokens can be read from, so that parsers can
+// be written against a lexer and tested with canned tokens alike.
+//
+// ReadToken returns io.EOF once there are no more tokens.
+type TokenReader interface {
+	ReadToken() (Token, error)
+}
+
+// ReadToken returns the next token emitted by the lexer.
+//
+// Returns io.EOF once the state machine has finished and every token has been returned, and
+// ErrClosed if the lexer has been closed.
+func (l *Lexer) ReadToken() (Token, error) {
+	return l.NextTokenContext(context.Background())
+}
+
+// ReadToken reads the next token from the underlying reader (see Decode).
+func (d *TokenBinaryDecoder) ReadToken() (Token, error) {
+	return d.Decode()
+}
+
+// TokenSliceReader reads tokens from a slice, e.g. canned tokens in a parser's tests.
+type TokenSliceReader struct {
+	tokens []Token
+}
+
+// NewTokenSliceReader creates a reader that reads the specified tokens in order.
+func NewTokenSliceReader(tokens ...Token) *TokenSliceReader {
+	return &TokenSliceReader{tokens: tokens}
+}
+
+// ReadToken returns the next token of the slice.
+func (r *TokenSliceReader) ReadToken() (Token, error) {
+	if len(r.tokens) == 0 {
+		return Token{}, io.EOF
+	}
+	t := r.tokens[0]
+	r.tokens = r.tokens[1:]
+	return t, nil
+}
+
+// ReadTokens reads all tokens from r.
+func ReadTokens(r TokenReader) ([]Token, error) {
+	var tokens []Token
+	for {
+		t, err := r.ReadToken()
+		if err == io.EOF {
+			return tokens, nil
+		}
+		if err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, t)
+	}
+}
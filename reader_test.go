@@ -0,0 +1,56 @@
+package lexer_test
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reader", func() {
+	var state lexer.StateFunc
+	state = func(l *lexer.Lexer) lexer.StateFunc {
+		if l.Next() == lexer.EOF {
+			return nil
+		}
+		l.Emit(Token)
+		return state
+	}
+
+	parse := func(r lexer.TokenReader) []interface{} {
+		var values []interface{}
+		for {
+			t, err := r.ReadToken()
+			if err == io.EOF {
+				return values
+			}
+			Expect(err).NotTo(HaveOccurred())
+			values = append(values, t.Value)
+		}
+	}
+
+	It("should read tokens emitted by the lexer (i.e. Lexer.ReadToken)", func() {
+		Expect(parse(lexer.NewLexer("ab", state))).To(Equal([]interface{}{"a", "b"}))
+		Expect(parse(lexer.NewLexer("ab", state, lexer.WithSynchronous()))).To(Equal([]interface{}{"a", "b"}))
+	})
+
+	It("should read canned tokens (i.e. TokenSliceReader)", func() {
+		r := lexer.NewTokenSliceReader(lexer.Token{Type: Token, Value: "a"}, lexer.Token{Type: Token, Value: "b"})
+		Expect(parse(r)).To(Equal([]interface{}{"a", "b"}))
+		_, err := r.ReadToken()
+		Expect(err).To(Equal(io.EOF))
+	})
+
+	It("should read recorded tokens (i.e. TokenBinaryDecoder.ReadToken and ReadTokens)", func() {
+		var buf bytes.Buffer
+		recorded, err := lexer.ReadTokens(lexer.NewLexer("abc", state))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lexer.EncodeTokens(&buf, recorded)).To(Succeed())
+		replayed, err := lexer.ReadTokens(lexer.NewTokenBinaryDecoder(&buf))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replayed).To(Equal(recorded))
+	})
+})
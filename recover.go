@@ -0,0 +1,17 @@
+package lexer
+
+// Recover returns a state that skips the input up to and including the next rune
+// satisfying the predicate (e.g. a newline or semicolon) and then resumes the initial state,
+// so that a single bad token following an error does not end the lexing of the input:
+//
+//	l.Errorf("unexpected %q", r)
+//	return l.Recover(isNewline)
+func (l *Lexer) Recover(sync RunePredicate) StateFunc {
+	return func(l *Lexer) StateFunc {
+		if l.IgnoreUpTo(sync) != EOF {
+			l.Ignore()
+		}
+		l.failed = false
+		return l.initialState
+	}
+}
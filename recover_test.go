@@ -0,0 +1,57 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recover", func() {
+	const (
+		Number lexer.TokenType = iota
+		Semicolon
+	)
+
+	semicolon := func(r rune) bool {
+		return r == ';'
+	}
+
+	var state lexer.StateFunc
+	state = func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case unicode.IsDigit(r):
+				l.NextUpTo(func(r rune) bool {
+					return !unicode.IsDigit(r)
+				})
+				l.Emit(Number)
+			case r == ';':
+				l.Next()
+				l.Emit(Semicolon)
+			default:
+				l.Errorf("unexpected %q", r)
+				return l.Recover(semicolon)
+			}
+		}
+	}
+
+	It("should resume the initial state after the synchronization rune (i.e. Recover)", func() {
+		l := lexer.NewLexer("1; 2 x 3; 4", state)
+		var tokens []lexer.Token
+		for t := range l.All() {
+			tokens = append(tokens, t)
+		}
+		Expect(tokens).To(HaveLen(5))
+		Expect(tokens[2]).To(EqualToken(lexer.Token{Type: Number, Value: "2"}))
+		Expect(tokens[3]).To(EqualToken(lexer.Token{Type: lexer.TokenError, Value: `unexpected 'x'`}))
+		Expect(tokens[4]).To(EqualToken(lexer.Token{Type: Number, Value: "4"}))
+		Expect(tokens[4].Column).To(Equal(11))
+	})
+})
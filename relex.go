@@ -0,0 +1,35 @@
+package lexer
+
+// WithOrigin positions the tokens emitted by the lexer, and its diagnostics, as if its input
+// started at the specified position, line, and column of an enclosing input.
+func WithOrigin(position RunePosition, line, column int) Option {
+	return func(l *Lexer) {
+		l.origin = Token{Position: position, Line: line, Column: column}
+	}
+}
+
+// Relex returns a lexer, starting in the specified state, over the value of a token (e.g.
+// to tokenize the inside of an interpolated string) whose tokens are positioned in the input
+// the token was emitted from.
+//
+// The offset is the number of bytes between the start of the token and the start of its
+// value in the input, e.g. 1 for a string stripped of its opening quote. The lexer is
+// created with the specified options; the token's value must be a string, and positions are
+// only exact if it is a contiguous excerpt of the input.
+func Relex(t Token, offset int, state StateFunc, options ...Option) *Lexer {
+	value, _ := t.Value.(string)
+	line, column := t.Line, t.Column
+	if offset <= len(t.Raw) {
+		for _, r := range t.Raw[:offset] {
+			if r == '\n' {
+				line, column = line+1, 1
+			} else {
+				column++
+			}
+		}
+	} else {
+		column += offset
+	}
+	origin := WithOrigin(t.Position+RunePosition(offset), line, column)
+	return NewLexer(value, state, append([]Option{origin}, options...)...)
+}
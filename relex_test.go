@@ -0,0 +1,96 @@
+package lexer_test
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Relex", func() {
+	const (
+		String lexer.TokenType = iota
+		Text
+		Interpolation
+	)
+
+	lexString := func(l *lexer.Lexer) lexer.StateFunc {
+		l.IgnoreUpTo(func(r rune) bool {
+			return r == '"'
+		})
+		l.Next()
+		l.NextUpTo(func(r rune) bool {
+			return r == '"' || r == lexer.EOF
+		})
+		l.Next()
+		l.EmitTrimmed(String, `"`)
+		return nil
+	}
+
+	lexInterpolated := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				l.EmitNonEmpty(Text)
+				return nil
+			case r == '$':
+				l.EmitNonEmpty(Text)
+				l.Next()
+				l.NextUpTo(func(r rune) bool {
+					return !unicode.IsLetter(r)
+				})
+				l.Emit(Interpolation)
+			case r == '!':
+				return l.ErrorCodef("E1", "unexpected %q", r)
+			default:
+				l.Next()
+			}
+		}
+	}
+
+	It("should position tokens lexed from a token's value in the original input (i.e. Relex)", func() {
+		l := lexer.NewLexer(`x = "a $b"`, lexString)
+		s := l.NextToken()
+		Expect(s.Value).To(Equal("a $b"))
+		tokens, err := lexer.ReadTokens(lexer.Relex(s, 1, lexInterpolated))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(2))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: Interpolation, Value: "$b"}))
+		Expect(tokens[1].Position).To(Equal(lexer.RunePosition(7)))
+		Expect(tokens[1].Line).To(Equal(1))
+		Expect(tokens[1].Column).To(Equal(8))
+	})
+
+	It("should account for the token's raw text spanning lines (i.e. Relex)", func() {
+		input := "y\n\"é\n$c !\""
+		l := lexer.NewLexer(input, lexString, lexer.WithLossless())
+		s := l.NextToken()
+		tokens, err := lexer.ReadTokens(lexer.Relex(s, 1, lexInterpolated))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Line).To(Equal(2))
+		Expect(tokens).To(HaveLen(3))
+		Expect(tokens[0]).To(EqualToken(lexer.Token{Type: Text, Value: "é\n"}))
+		Expect(tokens[0].Line).To(Equal(2))
+		Expect(tokens[0].Column).To(Equal(2))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: Interpolation, Value: "$c"}))
+		Expect(tokens[1].Position).To(Equal(lexer.RunePosition(strings.Index(input, "$"))))
+		Expect(tokens[1].Line).To(Equal(3))
+		Expect(tokens[1].Column).To(Equal(1))
+		Expect(tokens[2].Value.(lexer.LexError).Position).To(Equal(lexer.RunePosition(strings.Index(input, " !"))))
+	})
+
+	It("should position tokens as if the input started at the origin (i.e. WithOrigin)", func() {
+		l := lexer.NewLexer("ab\n$c", lexInterpolated, lexer.WithOrigin(10, 4, 7))
+		t := l.NextToken()
+		Expect(t.Position).To(Equal(lexer.RunePosition(10)))
+		Expect(t.Line).To(Equal(4))
+		Expect(t.Column).To(Equal(7))
+		t = l.NextToken()
+		Expect(t.Position).To(Equal(lexer.RunePosition(13)))
+		Expect(t.Line).To(Equal(5))
+		Expect(t.Column).To(Equal(1))
+	})
+})
@@ -0,0 +1,118 @@
+package lexer
+
+import "iter"
+
+// RewritePass rewrites a stream of tokens one token at a time.
+//
+// The pass is called once for every token of the stream with a cursor positioned at that
+// token; by default the token is passed through unchanged.
+type RewritePass func(c *RewriteCursor)
+
+// RewriteCursor is positioned at a token of the stream being rewritten by a RewritePass.
+type RewriteCursor struct {
+	next     func() (Token, bool)
+	upcoming []Token
+	token    Token
+	output   []Token
+	previous Token
+}
+
+// Rewrite returns a stream of the tokens rewritten by each of the passes in turn, e.g.
+// Rewrite(l.All(), promoteKeywords, foldStrings).
+func Rewrite(tokens iter.Seq[Token], passes ...RewritePass) iter.Seq[Token] {
+	for _, pass := range passes {
+		tokens = rewrite(tokens, pass)
+	}
+	return tokens
+}
+
+func rewrite(tokens iter.Seq[Token], pass RewritePass) iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		next, stop := iter.Pull(tokens)
+		defer stop()
+		c := &RewriteCursor{next: next}
+		for {
+			t, ok := c.pull()
+			if !ok {
+				return
+			}
+			c.token = t
+			c.output = append(c.output[:0], t)
+			pass(c)
+			for _, u := range c.output {
+				if !yield(u) {
+					return
+				}
+				c.previous = u
+			}
+		}
+	}
+}
+
+// Token returns the token the cursor is positioned at.
+func (c *RewriteCursor) Token() Token {
+	return c.token
+}
+
+// Previous returns the token most recently produced by the pass, or an empty token if none
+// has been.
+func (c *RewriteCursor) Previous() Token {
+	return c.previous
+}
+
+// Peek returns the k-th upcoming token, starting at 1, or an empty token if fewer than k
+// tokens remain.
+func (c *RewriteCursor) Peek(k int) Token {
+	for len(c.upcoming) < k {
+		t, ok := c.next()
+		if !ok {
+			return Token{}
+		}
+		c.upcoming = append(c.upcoming, t)
+	}
+	return c.upcoming[k-1]
+}
+
+// Skip deletes the next n upcoming tokens from the stream.
+func (c *RewriteCursor) Skip(n int) {
+	for i := 0; i < n; i++ {
+		if _, ok := c.pull(); !ok {
+			return
+		}
+	}
+}
+
+// Replace replaces the token the cursor is positioned at with the specified tokens.
+//
+// Tokens without a line are given the position of the token they replace.
+func (c *RewriteCursor) Replace(tokens ...Token) {
+	c.output = c.output[:0]
+	c.Insert(tokens...)
+}
+
+// Delete deletes the token the cursor is positioned at.
+func (c *RewriteCursor) Delete() {
+	c.output = c.output[:0]
+}
+
+// Insert inserts the specified tokens after the tokens produced so far for the token the
+// cursor is positioned at.
+//
+// Tokens without a line are given the position of the token the cursor is positioned at.
+func (c *RewriteCursor) Insert(tokens ...Token) {
+	for _, t := range tokens {
+		if t.Line == 0 {
+			t.Position, t.Line, t.Column = c.token.Position, c.token.Line, c.token.Column
+		}
+		c.output = append(c.output, t)
+	}
+}
+
+func (c *RewriteCursor) pull() (Token, bool) {
+	if len(c.upcoming) > 0 {
+		t := c.upcoming[0]
+		c.upcoming = c.upcoming[1:]
+		return t, true
+	}
+	return c.next()
+}
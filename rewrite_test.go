@@ -0,0 +1,109 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rewrite", func() {
+	const (
+		Identifier lexer.TokenType = iota
+		Keyword
+		String
+	)
+
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case r == '"':
+				l.Next()
+				l.NextUpTo(func(r rune) bool {
+					return r == '"' || r == lexer.EOF
+				})
+				l.Next()
+				l.EmitTrimmed(String, `"`)
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Identifier)
+			}
+		}
+	}
+
+	collect := func(l *lexer.Lexer, passes ...lexer.RewritePass) []lexer.Token {
+		var tokens []lexer.Token
+		for t := range lexer.Rewrite(l.All(), passes...) {
+			tokens = append(tokens, t)
+		}
+		return tokens
+	}
+
+	It("should pass tokens through unchanged by default (i.e. Rewrite)", func() {
+		l := lexer.NewLexer(`a "b"`, state)
+		Expect(collect(l, func(c *lexer.RewriteCursor) {})).To(Equal(collect(lexer.NewLexer(`a "b"`, state))))
+	})
+
+	It("should promote contextual identifiers using the surrounding tokens (i.e. Previous and Peek)", func() {
+		promote := func(c *lexer.RewriteCursor) {
+			t := c.Token()
+			if t.Value == "async" && c.Peek(1).Value == "function" && c.Previous().Type != Keyword {
+				t.Type = Keyword
+				c.Replace(t)
+			}
+		}
+		tokens := collect(lexer.NewLexer("async function async", state), promote)
+		Expect(tokens).To(HaveLen(3))
+		Expect(tokens[0]).To(EqualToken(lexer.Token{Type: Keyword, Value: "async"}))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: Identifier, Value: "function"}))
+		Expect(tokens[2]).To(EqualToken(lexer.Token{Type: Identifier, Value: "async"}))
+	})
+
+	It("should fold adjacent tokens while preserving their positions (i.e. Skip)", func() {
+		fold := func(c *lexer.RewriteCursor) {
+			t := c.Token()
+			if t.Type != String {
+				return
+			}
+			for c.Peek(1).Type == String {
+				t.Value = t.Value.(string) + c.Peek(1).Value.(string)
+				c.Skip(1)
+			}
+			c.Replace(t)
+		}
+		tokens := collect(lexer.NewLexer(`x "a" "b"
+"c" y`, state), fold)
+		Expect(tokens).To(HaveLen(3))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: String, Value: "abc"}))
+		Expect(tokens[1].Position).To(Equal(lexer.RunePosition(2)))
+		Expect(tokens[1].Line).To(Equal(1))
+		Expect(tokens[1].Column).To(Equal(3))
+		Expect(tokens[2].Line).To(Equal(2))
+	})
+
+	It("should insert and delete tokens over multiple passes (i.e. Insert and Delete)", func() {
+		drop := func(c *lexer.RewriteCursor) {
+			if c.Token().Value == "b" {
+				c.Delete()
+			}
+		}
+		terminate := func(c *lexer.RewriteCursor) {
+			if c.Peek(1).Line == 0 {
+				c.Insert(lexer.Token{Type: Keyword, Value: ";"})
+			}
+		}
+		tokens := collect(lexer.NewLexer("a b c", state), drop, terminate)
+		Expect(tokens).To(HaveLen(3))
+		Expect(tokens[0]).To(EqualToken(lexer.Token{Type: Identifier, Value: "a"}))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: Identifier, Value: "c"}))
+		Expect(tokens[2]).To(EqualToken(lexer.Token{Type: Keyword, Value: ";"}))
+		Expect(tokens[2].Position).To(Equal(tokens[1].Position))
+		Expect(tokens[2].Column).To(Equal(5))
+	})
+})
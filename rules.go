@@ -0,0 +1,446 @@
+package lexer
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Pattern is a pattern matched against the input by a rule of a Rules table.
+type Pattern struct {
+	expr     string
+	literal  string
+	trailing string
+}
+
+// Literal returns a pattern matching the string.
+func Literal(s string) Pattern {
+	return Pattern{expr: regexp.QuoteMeta(s), literal: s}
+}
+
+// Class returns a pattern matching one or more runes of the character class, in the
+// syntax of the regexp package (e.g. "[a-zA-Z_]", `\d`, or `\p{L}`), which may refer to
+// the classes of the table (see DefineClass).
+func Class(class string) Pattern {
+	return Pattern{expr: "(?:" + class + ")+"}
+}
+
+// Regexp returns a pattern matching the regular expression, in the syntax of the regexp
+// package.
+func Regexp(expr string) Pattern {
+	return Pattern{expr: expr}
+}
+
+// FollowedBy returns a pattern matching the input the pattern matches only if it is followed
+// by input matching the lookahead, which is not consumed (i.e. the trailing context of flex,
+// pattern/lookahead). The lookahead counts towards the length of the match when choosing
+// the rule matching the longest prefix of the input.
+func (p Pattern) FollowedBy(lookahead Pattern) Pattern {
+	p.trailing = lookahead.expr
+	return p
+}
+
+// String returns the pattern as a regular expression, followed by a slash and the trailing
+// context of the pattern, if any.
+func (p Pattern) String() string {
+	if p.trailing != "" {
+		return p.expr + "/" + p.trailing
+	}
+	return p.expr
+}
+
+// full returns a regular expression matching the pattern followed by its trailing context.
+func (p Pattern) full() string {
+	if p.trailing != "" {
+		return "(?:" + p.expr + ")(?:" + p.trailing + ")"
+	}
+	return p.expr
+}
+
+// Rules is a table of rules that lexes the input without any hand-written state functions,
+// e.g.
+//
+//	state, err := lexer.NewRules().
+//		Skip(lexer.Class(`\s`)).
+//		Add(Number, lexer.Class(`\d`)).
+//		Add(Plus, lexer.Literal("+")).
+//		State()
+//
+// Rules that cannot be expressed as patterns can be written as a Matcher (see Match).
+type Rules struct {
+	rules   []rule
+	modes   map[Mode]modeRules
+	classes map[string][]runeRange
+}
+
+type modeRules struct {
+	rules     *Rules
+	exclusive bool
+}
+
+type rule struct {
+	index     int
+	tokenType TokenType
+	pattern   Pattern
+	skip      bool
+	match     Matcher
+	begin     bool
+	mode      Mode
+	action    RuleAction
+	examples  []example
+	re        *regexp.Regexp
+}
+
+// NewRules creates an empty table of rules.
+func NewRules() *Rules {
+	return &Rules{}
+}
+
+// Add adds a rule emitting a token of the specified type for input matching the pattern
+// and returns the table.
+func (r *Rules) Add(tokenType TokenType, pattern Pattern) *Rules {
+	r.rules = append(r.rules, rule{tokenType: tokenType, pattern: pattern})
+	return r
+}
+
+// Skip adds a rule skipping input matching the pattern, e.g. whitespace, and returns the
+// table. The skipped runes are captured as trivia if the lexer was created with WithTrivia.
+func (r *Rules) Skip(pattern Pattern) *Rules {
+	r.rules = append(r.rules, rule{pattern: pattern, skip: true})
+	return r
+}
+
+// Match adds a rule lexing the input with a hand-written matcher, tried if no pattern
+// matches, and returns the table.
+func (r *Rules) Match(m Matcher) *Rules {
+	r.rules = append(r.rules, rule{match: m})
+	return r
+}
+
+// Begin makes the rule most recently added switch the lexer to the specified mode when it
+// is applied (see Modes), and returns the table.
+func (r *Rules) Begin(mode Mode) *Rules {
+	if n := len(r.rules); n > 0 {
+		r.rules[n-1].begin, r.rules[n-1].mode = true, mode
+	}
+	return r
+}
+
+// RuleAction is called when a rule is applied with the lexeme the rule matched, e.g. to
+// switch modes, and returns the value of the token emitted for it, or false to skip the
+// lexeme instead (as if matched by a rule added with Skip).
+type RuleAction func(l *Lexer, lexeme string) (interface{}, bool)
+
+// Action makes the rule most recently added call the action when it is applied, and
+// returns the table. The actions of rules added with Skip are called for their effects only.
+func (r *Rules) Action(action RuleAction) *Rules {
+	if n := len(r.rules); n > 0 {
+		r.rules[n-1].action = action
+	}
+	return r
+}
+
+// State returns a state that lexes the input by applying the rule, of those applying to
+// the active mode, matching the longest prefix of the input at the current position, or
+// the first such rule if several do, until the end of the input (as flex does). Matchers
+// are tried, in order, only if no pattern matches.
+//
+// The patterns are compiled into a DFA matching every rule in a single pass over the input,
+// unless a pattern contains an assertion (e.g. ^ or \b) or the DFA would be too large.
+//
+// An error token is emitted if no rule matches the input, unless the lexer was created with
+// WithIllegal. Returns an error if the table is not valid (see Validate).
+func (r *Rules) State() (StateFunc, error) {
+	match, err := r.Matcher()
+	if err != nil {
+		return nil, err
+	}
+	var state StateFunc
+	state = func(l *Lexer) StateFunc {
+		r := l.Peek()
+		if r == EOF {
+			return nil
+		}
+		if match(l) {
+			return state
+		}
+		return l.unmatched(r, state)
+	}
+	return state, nil
+}
+
+// Matcher returns a matcher applying the rules to the input at the current position, as
+// State does, e.g. as the rules of a mode of a Modes set.
+func (r *Rules) Matcher() (Matcher, error) {
+	base, err := compileTable(r.rules, len(r.rules), r.classes)
+	if err != nil {
+		return nil, err
+	}
+	modes := map[Mode]*ruleTable{}
+	for mode, m := range r.modes {
+		t, err := compileTable(r.modeRules(m), len(m.rules.rules), r.mergeClasses(m.rules))
+		if err != nil {
+			return nil, fmt.Errorf("lexer: mode %d: %w", mode, err)
+		}
+		t.mode = mode
+		modes[mode] = t
+	}
+	return func(l *Lexer) bool {
+		if t, ok := modes[l.mode]; ok {
+			return t.match(l)
+		}
+		return base.match(l)
+	}, nil
+}
+
+// Inclusive adds the rules of an inclusive mode, which inherits the rules of the table, and
+// returns the table. The rules of the mode are applied as if added before the inherited
+// rules, taking precedence over them if both match the same input.
+func (r *Rules) Inclusive(mode Mode, rules *Rules) *Rules {
+	return r.addMode(mode, modeRules{rules, false})
+}
+
+// Exclusive adds the rules of an exclusive mode, which are the only rules applied in that
+// mode, and returns the table.
+func (r *Rules) Exclusive(mode Mode, rules *Rules) *Rules {
+	return r.addMode(mode, modeRules{rules, true})
+}
+
+func (r *Rules) addMode(mode Mode, rules modeRules) *Rules {
+	if r.modes == nil {
+		r.modes = map[Mode]modeRules{}
+	}
+	r.modes[mode] = rules
+	return r
+}
+
+// modeRules returns the rules applied in a mode.
+func (r *Rules) modeRules(m modeRules) []rule {
+	rules := append([]rule(nil), m.rules.rules...)
+	if !m.exclusive {
+		rules = append(rules, r.rules...)
+	}
+	return rules
+}
+
+// ruleTable is a compiled table of rules.
+type ruleTable struct {
+	rules    []rule
+	dfa      *dfa
+	compiled bool
+
+	// mode is the mode of the rules, of which the first n were added to the mode, and the
+	// rest inherited from ModeInitial.
+	mode Mode
+	n    int
+}
+
+// compileTable compiles the rules, validating the first n; the rules inherited by a mode,
+// which may be overridden by its own, are validated with the table.
+func compileTable(table []rule, n int, classes map[string][]runeRange) (*ruleTable, error) {
+	rules, err := compileRules(table, classes)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(rules[:n]); err != nil {
+		return nil, err
+	}
+	d, compiled := compileDFA(rules)
+	return &ruleTable{rules: rules, dfa: d, compiled: compiled, n: n}, nil
+}
+
+// longest returns the rule whose pattern matches the longest prefix of the input, and the
+// length of the prefix, or nil if none matches.
+func (t *ruleTable) longest(input string) (*rule, int) {
+	if !t.compiled {
+		return longestMatch(t.rules, input)
+	}
+	if i, n := t.dfa.match(input); n > 0 {
+		return &t.rules[i], n
+	}
+	return nil, 0
+}
+
+func (t *ruleTable) match(l *Lexer) bool {
+	var start time.Time
+	if l.profile != nil {
+		start = time.Now()
+	}
+	if u, n := t.longest(l.Input[l.CurrentPosition:]); n > 0 {
+		if l.profile != nil {
+			t.record(l.profile, u, time.Since(start))
+		}
+		u.apply(l, n)
+		return true
+	}
+	if u := t.matchers(l); u != nil {
+		if l.profile != nil {
+			t.record(l.profile, u, time.Since(start))
+		}
+		return true
+	}
+	return false
+}
+
+// matchers tries the matchers of the table in order, returning the one that applied, if any.
+func (t *ruleTable) matchers(l *Lexer) *rule {
+	for i, u := range t.rules {
+		if u.match != nil && u.match(l) {
+			if u.begin {
+				l.Begin(u.mode)
+			}
+			return &t.rules[i]
+		}
+	}
+	return nil
+}
+
+// record records the rule in the profile, as a rule of ModeInitial if it was inherited.
+func (t *ruleTable) record(p *Profile, u *rule, elapsed time.Duration) {
+	if u.index >= t.n {
+		inherited := *u
+		inherited.index -= t.n
+		p.record(ModeInitial, &inherited, elapsed)
+		return
+	}
+	p.record(t.mode, u, elapsed)
+}
+
+// RuleMatch describes a rule matching a prefix of an input (see Explain).
+type RuleMatch struct {
+	// Rule is the index of the rule in the order the rules were added.
+	Rule    int
+	Type    TokenType
+	Skip    bool
+	Pattern Pattern
+	Length  int
+
+	// Won is true if the rule is the one applied to the input.
+	Won bool
+}
+
+// Explain returns the rules whose patterns match a prefix of the input, in the order they
+// were added, identifying the rule State applies to it, e.g. to debug overlapping rules.
+func (r *Rules) Explain(input string) ([]RuleMatch, error) {
+	rules, err := r.compile()
+	if err != nil {
+		return nil, err
+	}
+	winner, _ := longestMatch(rules, input)
+	var matches []RuleMatch
+	for i, u := range rules {
+		if u.match != nil {
+			continue
+		}
+		if n := u.length(input); n > 0 {
+			matches = append(matches, RuleMatch{
+				Rule:    i,
+				Type:    u.tokenType,
+				Skip:    u.skip,
+				Pattern: r.rules[i].pattern,
+				Length:  n,
+				Won:     winner != nil && winner.index == i,
+			})
+		}
+	}
+	return matches, nil
+}
+
+func (r *Rules) compile() ([]rule, error) {
+	return compileRules(r.rules, r.classes)
+}
+
+func compileRules(table []rule, classes map[string][]runeRange) ([]rule, error) {
+	rules := make([]rule, len(table))
+	for i, u := range table {
+		u.index = i
+		if u.match == nil {
+			pattern, re, err := compilePattern(u.pattern, classes)
+			if err != nil {
+				return nil, fmt.Errorf("lexer: rule %d: %w", i, err)
+			}
+			u.pattern, u.re = pattern, re
+		}
+		rules[i] = u
+	}
+	return rules, nil
+}
+
+// compilePattern expands the classes the pattern refers to, and compiles it into a regular
+// expression matching a prefix of the input, capturing the input preceding the trailing
+// context of the pattern, if any.
+func compilePattern(p Pattern, classes map[string][]runeRange) (Pattern, *regexp.Regexp, error) {
+	p.expr = expandClasses(p.expr, classes)
+	expr := `^(?:` + p.expr + `)`
+	if p.trailing != "" {
+		p.trailing = expandClasses(p.trailing, classes)
+		expr = `^(?:(` + p.expr + `)(?:` + p.trailing + `))`
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return p, nil, err
+	}
+	re.Longest()
+	return p, re, nil
+}
+
+// longestMatch returns the first of the rules whose pattern matches the longest prefix of
+// the input, and the length of the prefix, or nil if none matches.
+func longestMatch(rules []rule, input string) (*rule, int) {
+	var longest *rule
+	length := 0
+	for i := range rules {
+		if rules[i].match != nil {
+			continue
+		}
+		if n := rules[i].length(input); n > length {
+			longest, length = &rules[i], n
+		}
+	}
+	return longest, length
+}
+
+// apply consumes the n bytes matched by the rule's pattern, excluding its trailing context,
+// and emits, or skips, them.
+func (u rule) apply(l *Lexer, n int) {
+	if u.pattern.trailing != "" {
+		n = u.re.FindStringSubmatchIndex(l.Input[l.CurrentPosition:])[3]
+	}
+	l.advance(n)
+	if u.begin {
+		l.Begin(u.mode)
+	}
+	switch {
+	case u.action != nil:
+		lexeme := l.Input[l.startPosition:l.CurrentPosition]
+		if value, ok := u.action(l, lexeme); ok && !u.skip {
+			l.EmitValue(u.tokenType, value)
+			return
+		}
+		fallthrough
+	case u.skip:
+		l.EmitTrivia(TokenTrivia)
+	default:
+		l.Emit(u.tokenType)
+	}
+}
+
+// length returns the length of the input matched by the rule's pattern, including its
+// trailing context, or -1 if it does not match (or matches nothing but trailing context).
+func (u rule) length(input string) int {
+	if u.pattern.trailing != "" {
+		if loc := u.re.FindStringSubmatchIndex(input); loc != nil && loc[3] > 0 {
+			return loc[1]
+		}
+	} else if loc := u.re.FindStringIndex(input); loc != nil {
+		return loc[1]
+	}
+	return -1
+}
+
+// advance moves the current position of the lexer ahead by n bytes.
+func (l *Lexer) advance(n int) {
+	for end := l.CurrentPosition + RunePosition(n); l.CurrentPosition < end; {
+		l.Next()
+	}
+}
@@ -0,0 +1,267 @@
+package lexer_test
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rules", func() {
+	const (
+		Identifier lexer.TokenType = iota
+		Keyword
+		Number
+		Operator
+		String
+	)
+
+	str := func(l *lexer.Lexer) bool {
+		if l.Peek() != '"' {
+			return false
+		}
+		l.Next()
+		l.NextUpTo(func(r rune) bool {
+			return r == '"' || r == lexer.EOF
+		})
+		l.Next()
+		l.EmitTrimmed(String, `"`)
+		return true
+	}
+
+	rules := lexer.NewRules().
+		Skip(lexer.Class(`\s`)).
+		Add(Number, lexer.Regexp(`\d+(\.\d+)?`)).
+		Add(Operator, lexer.Literal("**")).
+		Add(Operator, lexer.Class(`[-+*/]`)).
+		Add(Identifier, lexer.Regexp(`[a-zA-Z_]\w*`)).
+		Match(str)
+
+	It("should lex the input with the rules (i.e. Add, Skip, and State)", func() {
+		state, err := rules.State()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize(`x ** 2.5 + "a b"`, state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(5))
+		Expect(tokens[0]).To(Equal(lexer.Token{Type: Identifier, Value: "x", Position: 0, Line: 1, Column: 1}))
+		Expect(tokens[1]).To(Equal(lexer.Token{Type: Operator, Value: "**", Position: 2, Line: 1, Column: 3}))
+		Expect(tokens[2]).To(Equal(lexer.Token{Type: Number, Value: "2.5", Position: 5, Line: 1, Column: 6}))
+		Expect(tokens[3]).To(EqualToken(lexer.Token{Type: Operator, Value: "+"}))
+		Expect(tokens[4]).To(EqualToken(lexer.Token{Type: String, Value: "a b"}))
+	})
+
+	It("should apply the rule matching the longest prefix of the input, then the first (i.e. State)", func() {
+		state, err := lexer.NewRules().
+			Skip(lexer.Class(`\s`)).
+			Add(Keyword, lexer.Literal("if")).
+			Add(Identifier, lexer.Regexp(`[a-z]+`)).
+			Add(Operator, lexer.Literal("=")).
+			Add(Operator, lexer.Literal("==")).
+			State()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize("if iffy == i", state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(4))
+		Expect(tokens[0]).To(EqualToken(lexer.Token{Type: Keyword, Value: "if"}))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: Identifier, Value: "iffy"}))
+		Expect(tokens[2]).To(EqualToken(lexer.Token{Type: Operator, Value: "=="}))
+		Expect(tokens[3]).To(EqualToken(lexer.Token{Type: Identifier, Value: "i"}))
+	})
+
+	It("should switch modes when rules are applied (i.e. Begin and Matcher)", func() {
+		const Quoted lexer.Mode = 1
+		initial, err := lexer.NewRules().
+			Skip(lexer.Class(`\s`)).
+			Add(Identifier, lexer.Regexp(`\w+`)).
+			Add(Operator, lexer.Literal(`"`)).Begin(Quoted).
+			Matcher()
+		Expect(err).NotTo(HaveOccurred())
+		quoted, err := lexer.NewRules().
+			Add(String, lexer.Regexp(`[^"]+`)).
+			Add(Operator, lexer.Literal(`"`)).Begin(lexer.ModeInitial).
+			Matcher()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize(`a "b c" d`, lexer.NewModes(initial).Exclusive(Quoted, quoted).State())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(5))
+		Expect(tokens[2]).To(EqualToken(lexer.Token{Type: String, Value: "b c"}))
+		Expect(tokens[4]).To(EqualToken(lexer.Token{Type: Identifier, Value: "d"}))
+	})
+
+	It("should call the actions of applied rules (i.e. Action)", func() {
+		const Upper lexer.Mode = 1
+		var skipped []string
+		initial, err := lexer.NewRules().
+			Skip(lexer.Class(`\s`)).Action(func(l *lexer.Lexer, lexeme string) (interface{}, bool) {
+			skipped = append(skipped, lexeme)
+			return nil, true
+		}).
+			Add(Number, lexer.Class(`\d`)).Action(func(l *lexer.Lexer, lexeme string) (interface{}, bool) {
+			n, err := strconv.Atoi(lexeme)
+			return n, err == nil
+		}).
+			Add(Identifier, lexer.Regexp(`#\w*`)).Action(func(l *lexer.Lexer, lexeme string) (interface{}, bool) {
+			return nil, false
+		}).
+			Add(Operator, lexer.Literal("^")).Action(func(l *lexer.Lexer, lexeme string) (interface{}, bool) {
+			l.Begin(Upper)
+			return lexeme, true
+		}).
+			Add(Identifier, lexer.Class(`[a-z]`)).
+			Matcher()
+		Expect(err).NotTo(HaveOccurred())
+		upper, err := lexer.NewRules().Add(Identifier, lexer.Class(`[a-z]`)).Action(func(l *lexer.Lexer, lexeme string) (interface{}, bool) {
+			l.Begin(lexer.ModeInitial)
+			return strings.ToUpper(lexeme), true
+		}).Matcher()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize("12 #note ^ab cd", lexer.NewModes(initial).Inclusive(Upper, upper).State(), lexer.WithLossless())
+		Expect(err).NotTo(HaveOccurred())
+		var values, raws []interface{}
+		for _, t := range tokens {
+			values, raws = append(values, t.Value), append(raws, t.Raw)
+		}
+		Expect(values).To(Equal([]interface{}{12, "^", "AB", "cd"}))
+		Expect(raws).To(Equal([]interface{}{"12", "^", "ab", "cd"}))
+		Expect(tokens[1].Trivia).To(HaveLen(1))
+		Expect(tokens[1].Trivia[0].Value).To(Equal(" #note "))
+		Expect(skipped).To(Equal([]string{" ", " ", " "}))
+	})
+
+	It("should apply the rules of the active mode (i.e. Inclusive and Exclusive)", func() {
+		const (
+			Quoted lexer.Mode = iota + 1
+			Math
+		)
+		state, err := lexer.NewRules().
+			Skip(lexer.Class(`\s`)).
+			Add(Identifier, lexer.Class(`[a-z]`)).
+			Add(Operator, lexer.Literal(`"`)).Begin(Quoted).
+			Add(Operator, lexer.Literal("$")).Begin(Math).
+			Exclusive(Quoted, lexer.NewRules().
+				Add(String, lexer.Regexp(`[^"]+`)).
+				Add(Operator, lexer.Literal(`"`)).Begin(lexer.ModeInitial)).
+			Inclusive(Math, lexer.NewRules().
+				Add(Number, lexer.Class(`\d`)).
+				Add(Keyword, lexer.Literal("pi")).
+				Add(Operator, lexer.Literal("$")).Begin(lexer.ModeInitial)).
+			State()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize(`a "pi 2" $ pi 2 pie $ pi`, state)
+		Expect(err).NotTo(HaveOccurred())
+		var types []lexer.TokenType
+		for _, t := range tokens {
+			types = append(types, t.Type)
+		}
+		Expect(types).To(Equal([]lexer.TokenType{Identifier, Operator, String, Operator, Operator, Keyword, Number, Identifier, Operator, Identifier}))
+		_, err = lexer.NewRules().Add(Identifier, lexer.Class(`[a-z]`)).Inclusive(Quoted, lexer.NewRules()).Exclusive(Math, lexer.NewRules().Add(Keyword, lexer.Literal("pi")).Add(Keyword, lexer.Literal("pi"))).State()
+		Expect(err).To(MatchError("lexer: mode 2: lexer: rule 1 is shadowed by rule 0"))
+	})
+
+	It("should match Unicode and named classes (i.e. DefineClass)", func() {
+		r := lexer.NewRules().
+			DefineClass("IDStart", unicode.L, unicode.Nl).
+			DefineClass("Digit", unicode.Nd).
+			Skip(lexer.Class(`\s`)).
+			Add(Number, lexer.Class(`\p{Digit}`)).
+			Add(Identifier, lexer.Regexp(`\p{IDStart}[\p{IDStart}\p{Mn}\p{Digit}_]*`)).
+			Add(Operator, lexer.Class(`[^\p{IDStart}\p{Digit}\s]`)).
+			Add(Keyword, lexer.Class(`\p{Greek}`))
+		state, err := r.State()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize("ñandú_٣ ٣٤ +- Ⅻ", state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(4))
+		Expect(tokens[0]).To(EqualToken(lexer.Token{Type: Identifier, Value: "ñandú_٣"}))
+		Expect(tokens[1]).To(EqualToken(lexer.Token{Type: Number, Value: "٣٤"}))
+		Expect(tokens[2]).To(EqualToken(lexer.Token{Type: Operator, Value: "+-"}))
+		Expect(tokens[3]).To(EqualToken(lexer.Token{Type: Identifier, Value: "Ⅻ"}))
+		matches, err := r.Explain("λ")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(matches).To(HaveLen(2))
+		Expect(matches[0].Pattern).To(Equal(lexer.Regexp(`\p{IDStart}[\p{IDStart}\p{Mn}\p{Digit}_]*`)))
+		Expect(matches[0].Won).To(BeTrue())
+	})
+
+	It("should apply the named classes of modes (i.e. DefineClass)", func() {
+		const Math lexer.Mode = 1
+		state, err := lexer.NewRules().
+			DefineClass("Word", unicode.Latin).
+			Add(Identifier, lexer.Class(`\p{Word}`)).
+			Add(Operator, lexer.Literal("$")).Begin(Math).
+			Exclusive(Math, lexer.NewRules().
+				DefineClass("Word", unicode.Greek).
+				Add(Keyword, lexer.Class(`\p{Word}`)).
+				Add(Number, lexer.Class(`\P{Word}`))).
+			State()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize("ab$πa", state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(4))
+		Expect(tokens[2]).To(EqualToken(lexer.Token{Type: Keyword, Value: "π"}))
+		Expect(tokens[3]).To(EqualToken(lexer.Token{Type: Number, Value: "a"}))
+	})
+
+	It("should match rules only if followed by their trailing context (i.e. FollowedBy)", func() {
+		r := lexer.NewRules().
+			Skip(lexer.Class(`\s`)).
+			Add(Keyword, lexer.Regexp(`[a-z]+`).FollowedBy(lexer.Regexp(`\s*\(`))).
+			Add(Identifier, lexer.Regexp(`[a-z]+`)).
+			Add(Number, lexer.Class(`\d`).FollowedBy(lexer.Literal(".."))).
+			Add(Number, lexer.Regexp(`\d+(\.\d+)?`)).
+			Add(Operator, lexer.Class(`[.()]`))
+		state, err := r.State()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize("f (x) 1..2 1.5", state)
+		Expect(err).NotTo(HaveOccurred())
+		var values []string
+		for _, t := range tokens {
+			values = append(values, t.Value.(string))
+		}
+		Expect(values).To(Equal([]string{"f", "(", "x", ")", "1", "..", "2", "1.5"}))
+		Expect(tokens[0].Type).To(Equal(Keyword))
+		Expect(tokens[2].Type).To(Equal(Identifier))
+		Expect(tokens[4].Type).To(Equal(Number))
+		Expect(lexer.Regexp("a").FollowedBy(lexer.Literal("+")).String()).To(Equal(`a/\+`))
+		Expect(r.Generate(&strings.Builder{}, "main")).To(MatchError("lexer: rule 1 has trailing context and cannot be generated"))
+	})
+
+	It("should explain which rule matches a prefix of the input (i.e. Explain)", func() {
+		matches, err := rules.Explain("**2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(matches).To(Equal([]lexer.RuleMatch{
+			{Rule: 2, Type: Operator, Pattern: lexer.Literal("**"), Length: 2, Won: true},
+			{Rule: 3, Type: Operator, Pattern: lexer.Class(`[-+*/]`), Length: 2},
+		}))
+		matches, err = rules.Explain(`"a"`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(matches).To(BeEmpty())
+	})
+
+	It("should emit an error token if no rule matches (i.e. State)", func() {
+		state, err := rules.State()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize("a ?", state)
+		Expect(tokens).To(HaveLen(1))
+		Expect(err).To(MatchError(`unexpected '?'`))
+	})
+
+	It("should capture skipped input as trivia (i.e. Skip)", func() {
+		state, err := rules.State()
+		Expect(err).NotTo(HaveOccurred())
+		tokens, err := lexer.Tokenize("a  b", state, lexer.WithTrivia(lexer.TriviaLeading))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens[1].Trivia).To(HaveLen(1))
+		Expect(tokens[1].Trivia[0].Value).To(Equal("  "))
+	})
+
+	It("should report invalid patterns (i.e. Regexp)", func() {
+		_, err := lexer.NewRules().Add(Number, lexer.Literal("(")).Add(Number, lexer.Regexp("(")).State()
+		Expect(err).To(MatchError(ContainSubstring("lexer: rule 1: error parsing regexp")))
+		Expect(lexer.Literal("a+").String()).To(Equal(`a\+`))
+	})
+})
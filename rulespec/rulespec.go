@@ -0,0 +1,197 @@
+// Package rulespec loads rule tables (see lexer.Rules) from JSON or YAML specs, e.g. so a
+// syntax highlighting service can add languages by configuration:
+//
+//	rules:
+//	  - {pattern: '\s+'}
+//	  - {token: NUMBER, pattern: '\d+'}
+//	  - {token: QUOTE, literal: '"', begin: string}
+//	modes:
+//	  - name: string
+//	    exclusive: true
+//	    rules:
+//	      - {token: TEXT, pattern: '[^"]+'}
+//	      - {token: QUOTE, literal: '"', begin: INITIAL}
+package rulespec
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/eczarny/lexer"
+	"gopkg.in/yaml.v3"
+)
+
+// InitialMode is the name of lexer.ModeInitial in specs.
+const InitialMode = "INITIAL"
+
+// Spec is a declarative definition of the rules of a lexer.
+type Spec struct {
+	// Rules are the rules of lexer.ModeInitial.
+	Rules []Rule `json:"rules" yaml:"rules"`
+	Modes []Mode `json:"modes,omitempty" yaml:"modes,omitempty"`
+}
+
+// Rule is a rule matching either a regular expression or a literal.
+type Rule struct {
+	// Token is the name of the type of the tokens emitted by the rule; input matched by a rule
+	// without a token is skipped.
+	Token   string `json:"token,omitempty" yaml:"token,omitempty"`
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Literal string `json:"literal,omitempty" yaml:"literal,omitempty"`
+
+	// Lookahead is a regular expression the input matched by the rule must be followed by,
+	// without consuming it (see lexer.Pattern.FollowedBy).
+	Lookahead string `json:"lookahead,omitempty" yaml:"lookahead,omitempty"`
+
+	// Begin is the name of the mode the lexer switches to when the rule is applied.
+	Begin string `json:"begin,omitempty" yaml:"begin,omitempty"`
+}
+
+// Mode is a mode inheriting the rules of lexer.ModeInitial, unless it is exclusive (see
+// lexer.Rules.Inclusive).
+type Mode struct {
+	Name      string `json:"name" yaml:"name"`
+	Exclusive bool   `json:"exclusive,omitempty" yaml:"exclusive,omitempty"`
+	Rules     []Rule `json:"rules" yaml:"rules"`
+}
+
+// ParseJSON parses a spec encoded as JSON.
+func ParseJSON(data []byte) (*Spec, error) {
+	var s Spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ParseYAML parses a spec encoded as YAML.
+func ParseYAML(data []byte) (*Spec, error) {
+	var s Spec
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Compile returns a state lexing the input with the rules of the spec, and the token types
+// of the names of its tokens, numbered in the order they first appear.
+//
+// The modes of the spec are numbered in order from 1.
+func (s *Spec) Compile() (lexer.StateFunc, map[string]lexer.TokenType, error) {
+	c, err := newCompiler(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	rules, err := c.rules(InitialMode, s.Rules)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.validate(InitialMode, rules); err != nil {
+		return nil, nil, err
+	}
+	for _, m := range s.Modes {
+		modeRules, err := c.rules(m.Name, m.Rules)
+		if err != nil {
+			return nil, nil, err
+		}
+		if m.Exclusive {
+			rules.Exclusive(c.modes[m.Name], modeRules)
+		} else {
+			rules.Inclusive(c.modes[m.Name], modeRules)
+		}
+		if err := c.validate(m.Name, rules); err != nil {
+			return nil, nil, err
+		}
+	}
+	state, err := rules.State()
+	if err != nil {
+		return nil, nil, fmt.Errorf("rulespec: %w", err)
+	}
+	return state, c.types, nil
+}
+
+// Table returns the rule table of a spec without modes, e.g. to generate a lexer (see
+// lexer.Rules.Generate), and the token types of the names of its tokens.
+func (s *Spec) Table() (*lexer.Rules, map[string]lexer.TokenType, error) {
+	if len(s.Modes) > 0 {
+		return nil, nil, fmt.Errorf("rulespec: spec has modes")
+	}
+	c, err := newCompiler(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	rules, err := c.rules(InitialMode, s.Rules)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rules, c.types, nil
+}
+
+type compiler struct {
+	types map[string]lexer.TokenType
+	modes map[string]lexer.Mode
+}
+
+func newCompiler(s *Spec) (*compiler, error) {
+	c := &compiler{
+		types: map[string]lexer.TokenType{},
+		modes: map[string]lexer.Mode{InitialMode: lexer.ModeInitial},
+	}
+	for i, m := range s.Modes {
+		if _, ok := c.modes[m.Name]; ok {
+			return nil, fmt.Errorf("rulespec: duplicate mode %q", m.Name)
+		}
+		c.modes[m.Name] = lexer.Mode(i + 1)
+	}
+	return c, nil
+}
+
+func (c *compiler) rules(mode string, specs []Rule) (*lexer.Rules, error) {
+	rules := lexer.NewRules()
+	for i, r := range specs {
+		var pattern lexer.Pattern
+		switch {
+		case r.Pattern != "" && r.Literal != "":
+			return nil, fmt.Errorf("rulespec: mode %s: rule %d has both a pattern and a literal", mode, i)
+		case r.Pattern != "":
+			pattern = lexer.Regexp(r.Pattern)
+		case r.Literal != "":
+			pattern = lexer.Literal(r.Literal)
+		default:
+			return nil, fmt.Errorf("rulespec: mode %s: rule %d has neither a pattern nor a literal", mode, i)
+		}
+		if r.Lookahead != "" {
+			pattern = pattern.FollowedBy(lexer.Regexp(r.Lookahead))
+		}
+		if r.Token == "" {
+			rules.Skip(pattern)
+		} else {
+			rules.Add(c.tokenType(r.Token), pattern)
+		}
+		if r.Begin != "" {
+			begin, ok := c.modes[r.Begin]
+			if !ok {
+				return nil, fmt.Errorf("rulespec: mode %s: rule %d begins unknown mode %q", mode, i, r.Begin)
+			}
+			rules.Begin(begin)
+		}
+	}
+	return rules, nil
+}
+
+// validate validates the rules once the rules of the mode have been added.
+func (c *compiler) validate(mode string, rules *lexer.Rules) error {
+	if err := rules.Validate(); err != nil {
+		return fmt.Errorf("rulespec: mode %s: %w", mode, err)
+	}
+	return nil
+}
+
+func (c *compiler) tokenType(name string) lexer.TokenType {
+	t, ok := c.types[name]
+	if !ok {
+		t = lexer.TokenType(len(c.types))
+		c.types[name] = t
+	}
+	return t
+}
@@ -0,0 +1,13 @@
+package rulespec_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestRulespec(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Rulespec Suite")
+}
@@ -0,0 +1,93 @@
+package rulespec_test
+
+import (
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/rulespec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rulespec", func() {
+	type token struct {
+		Type  string
+		Value interface{}
+	}
+
+	lex := func(spec *rulespec.Spec, input string) ([]token, error) {
+		state, types, err := spec.Compile()
+		if err != nil {
+			return nil, err
+		}
+		names := map[lexer.TokenType]string{}
+		for name, t := range types {
+			names[t] = name
+		}
+		tokens, err := lexer.Tokenize(input, state)
+		var named []token
+		for _, t := range tokens {
+			named = append(named, token{names[t.Type], t.Value})
+		}
+		return named, err
+	}
+
+	It("should load rules from JSON (i.e. ParseJSON and Compile)", func() {
+		spec, err := rulespec.ParseJSON([]byte(`{
+			"rules": [
+				{"pattern": "\\s+"},
+				{"token": "NUMBER", "pattern": "\\d+"},
+				{"token": "PLUS", "literal": "+"}
+			]
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lex(spec, "1 + 23")).To(Equal([]token{{"NUMBER", "1"}, {"PLUS", "+"}, {"NUMBER", "23"}}))
+	})
+
+	It("should load rules and modes from YAML (i.e. ParseYAML and Compile)", func() {
+		spec, err := rulespec.ParseYAML([]byte(`
+rules:
+  - {pattern: '\s+'}
+  - {token: WORD, pattern: '\w+'}
+  - {token: QUOTE, literal: '"', begin: string}
+modes:
+  - name: string
+    exclusive: true
+    rules:
+      - {token: TEXT, pattern: '[^"]+'}
+      - {token: QUOTE, literal: '"', begin: INITIAL}
+`))
+		Expect(err).NotTo(HaveOccurred())
+		_, types, err := spec.Compile()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(types).To(Equal(map[string]lexer.TokenType{"WORD": 0, "QUOTE": 1, "TEXT": 2}))
+		Expect(lex(spec, `say "a b" c`)).To(Equal([]token{{"WORD", "say"}, {"QUOTE", `"`}, {"TEXT", "a b"}, {"QUOTE", `"`}, {"WORD", "c"}}))
+	})
+
+	It("should return the rule tables of specs without modes (i.e. Table)", func() {
+		spec := &rulespec.Spec{Rules: []rulespec.Rule{{Token: "A", Literal: "a"}, {Token: "B", Pattern: "b+"}}}
+		rules, types, err := spec.Table()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(types).To(Equal(map[string]lexer.TokenType{"A": 0, "B": 1}))
+		Expect(rules.Explain("bb")).To(HaveLen(1))
+		spec.Rules[0].Lookahead = "b"
+		rules, _, err = spec.Table()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rules.Explain("ab")).To(HaveLen(1))
+		spec.Modes = []rulespec.Mode{{Name: "m"}}
+		_, _, err = spec.Table()
+		Expect(err).To(MatchError("rulespec: spec has modes"))
+	})
+
+	It("should report invalid specs (i.e. Compile)", func() {
+		_, _, err := (&rulespec.Spec{Rules: []rulespec.Rule{{Token: "A"}}}).Compile()
+		Expect(err).To(MatchError("rulespec: mode INITIAL: rule 0 has neither a pattern nor a literal"))
+		_, _, err = (&rulespec.Spec{Rules: []rulespec.Rule{{Token: "A", Literal: "a", Begin: "b"}}}).Compile()
+		Expect(err).To(MatchError(`rulespec: mode INITIAL: rule 0 begins unknown mode "b"`))
+		_, _, err = (&rulespec.Spec{Rules: []rulespec.Rule{{Token: "A", Literal: "a"}, {Token: "B", Literal: "a"}}}).Compile()
+		Expect(err).To(MatchError("rulespec: mode INITIAL: lexer: rule 1 is shadowed by rule 0"))
+		_, _, err = (&rulespec.Spec{Modes: []rulespec.Mode{{Name: "m"}, {Name: "m"}}}).Compile()
+		Expect(err).To(MatchError(`rulespec: duplicate mode "m"`))
+		_, err = rulespec.ParseJSON([]byte(`{"rules": 1}`))
+		Expect(err).To(HaveOccurred())
+	})
+})
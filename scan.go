@@ -0,0 +1,25 @@
+package lexer
+
+import "unicode/utf8"
+
+// peekAt returns the rune n runes ahead of the current position of the lexer, without
+// moving it, or EOF if the input ends before it.
+func (l *Lexer) peekAt(n int) rune {
+	position := int(l.CurrentPosition)
+	for ; n > 0 && position < len(l.Input); n-- {
+		_, w := utf8.DecodeRuneInString(l.Input[position:])
+		position += w
+	}
+	if position >= len(l.Input) {
+		return EOF
+	}
+	r, _ := utf8.DecodeRuneInString(l.Input[position:])
+	return r
+}
+
+// errorAt returns an error reported at the position of the input, e.g. by a scanner.
+func (l *Lexer) errorAt(position RunePosition, format string, args ...interface{}) LexError {
+	e := l.lexError(SeverityError, "", format, args...)
+	e.Position = position + l.origin.Position
+	return e
+}
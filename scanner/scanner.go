@@ -0,0 +1,163 @@
+// Package scanner provides a facade mimicking text/scanner.Scanner backed by a lexer, easing
+// the migration of code written against the standard library scanner:
+//
+//	s := scanner.NewScanner(lexText, map[lexer.TokenType]rune{Ident: scanner.Ident, Number: scanner.Int})
+//	s.Init(strings.NewReader(src))
+//	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
+//		fmt.Printf("%s: %s\n", s.Position, s.TokenText())
+//	}
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/scanner"
+	"unicode/utf8"
+
+	"github.com/eczarny/lexer"
+)
+
+// Position is a position in the source, as reported by text/scanner.
+type Position = scanner.Position
+
+// The token classes returned by Scan, as defined by text/scanner.
+const (
+	EOF       = scanner.EOF
+	Ident     = scanner.Ident
+	Int       = scanner.Int
+	Float     = scanner.Float
+	Char      = scanner.Char
+	String    = scanner.String
+	RawString = scanner.RawString
+	Comment   = scanner.Comment
+)
+
+// TokenString returns a printable string for a token class or character (see
+// text/scanner.TokenString).
+func TokenString(tok rune) string {
+	return scanner.TokenString(tok)
+}
+
+// Scanner implements the reading of tokens from an io.Reader with the API of
+// text/scanner.Scanner, lexing the source with a state function.
+type Scanner struct {
+	// Position is the position of the most recently scanned token; the filename, if set, is
+	// retained by Init.
+	Position
+
+	// Error is called for each error token emitted by the lexer; if nil, errors are reported
+	// to os.Stderr.
+	Error func(s *Scanner, msg string)
+
+	// ErrorCount is incremented for each error token emitted by the lexer.
+	ErrorCount int
+
+	state   lexer.StateFunc
+	classes map[lexer.TokenType]rune
+	options []lexer.Option
+	lexer   *lexer.Lexer
+	text    string
+}
+
+// NewScanner creates a scanner that lexes its source starting in the specified state,
+// creating the lexer with the options, and returns tokens of each type as the class it is
+// mapped to.
+func NewScanner(state lexer.StateFunc, classes map[lexer.TokenType]rune, options ...lexer.Option) *Scanner {
+	return &Scanner{state: state, classes: classes, options: options}
+}
+
+// Init initializes the scanner with a new source and returns it.
+func (s *Scanner) Init(src io.Reader) *Scanner {
+	input, err := io.ReadAll(src)
+	s.lexer = lexer.NewLexer(string(input), s.state, s.options...)
+	s.Position = Position{Filename: s.Filename}
+	s.ErrorCount = 0
+	s.text = ""
+	if err != nil {
+		s.error(err.Error())
+	}
+	return s
+}
+
+// Scan reads the next token from the source and returns its class, or EOF at the end of
+// the source.
+//
+// Tokens of types without a class are returned as the first rune of their text, as
+// text/scanner returns characters that do not start a token. Warning tokens are skipped.
+func (s *Scanner) Scan() rune {
+	for {
+		t, err := s.lexer.ReadToken()
+		if err != nil {
+			line, column := lineColumn(s.lexer.Input)
+			s.setToken(lexer.Token{Position: lexer.RunePosition(len(s.lexer.Input)), Line: line, Column: column}, "")
+			return EOF
+		}
+		switch t.Type {
+		case lexer.TokenWarning:
+			continue
+		case lexer.TokenError:
+			s.setToken(t, "")
+			e, _ := t.LexError()
+			s.error(e.Error())
+			continue
+		}
+		text, ok := t.Value.(string)
+		if !ok {
+			text = fmt.Sprint(t.Value)
+		}
+		s.setToken(t, text)
+		if class, ok := s.classes[t.Type]; ok {
+			return class
+		}
+		r, _ := utf8.DecodeRuneInString(text)
+		return r
+	}
+}
+
+// TokenText returns the text of the most recently scanned token.
+func (s *Scanner) TokenText() string {
+	return s.text
+}
+
+// Pos returns the position immediately after the most recently scanned token.
+func (s *Scanner) Pos() Position {
+	pos := s.Position
+	for _, r := range s.text {
+		if r == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+	}
+	pos.Offset += len(s.text)
+	return pos
+}
+
+func (s *Scanner) setToken(t lexer.Token, text string) {
+	s.text = text
+	s.Position = Position{Filename: s.Filename, Offset: int(t.Position), Line: t.Line, Column: t.Column}
+}
+
+func (s *Scanner) error(msg string) {
+	s.ErrorCount++
+	if s.Error != nil {
+		s.Error(s, msg)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s: %s\n", s.Position, msg)
+}
+
+func lineColumn(input string) (line, column int) {
+	line, column = 1, 1
+	for _, r := range input {
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
@@ -0,0 +1,84 @@
+package scanner_test
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/scanner"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	Ident lexer.TokenType = iota
+	Number
+	Punct
+)
+
+var _ = Describe("Scanner", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case unicode.IsLetter(r):
+				l.NextUpTo(func(r rune) bool {
+					return !unicode.IsLetter(r)
+				})
+				l.Emit(Ident)
+			case unicode.IsDigit(r):
+				l.NextUpTo(func(r rune) bool {
+					return !unicode.IsDigit(r)
+				})
+				l.Emit(Number)
+			case r == '$':
+				return l.Errorf("unexpected %q", r)
+			default:
+				l.Next()
+				l.Emit(Punct)
+			}
+		}
+	}
+
+	classes := map[lexer.TokenType]rune{Ident: scanner.Ident, Number: scanner.Int}
+
+	It("should scan tokens as their classes (i.e. Scan and TokenText)", func() {
+		s := scanner.NewScanner(state, classes)
+		s.Init(strings.NewReader("x = 42;"))
+		var scanned []string
+		for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
+			scanned = append(scanned, scanner.TokenString(tok)+" "+s.TokenText())
+		}
+		Expect(scanned).To(Equal([]string{"Ident x", `"=" =`, "Int 42", `";" ;`}))
+	})
+
+	It("should report the positions of tokens (i.e. Position and Pos)", func() {
+		s := scanner.NewScanner(state, classes)
+		s.Init(strings.NewReader("x\n  foo"))
+		s.Filename = "input"
+		s.Scan()
+		s.Scan()
+		Expect(s.Position).To(Equal(scanner.Position{Filename: "input", Offset: 4, Line: 2, Column: 3}))
+		Expect(s.Position.String()).To(Equal("input:2:3"))
+		Expect(s.Pos()).To(Equal(scanner.Position{Filename: "input", Offset: 7, Line: 2, Column: 6}))
+		Expect(s.Scan()).To(Equal(rune(scanner.EOF)))
+		Expect(s.Position).To(Equal(scanner.Position{Filename: "input", Offset: 7, Line: 2, Column: 6}))
+	})
+
+	It("should report error tokens (i.e. Error and ErrorCount)", func() {
+		var messages []string
+		s := scanner.NewScanner(state, classes)
+		s.Error = func(s *scanner.Scanner, msg string) {
+			messages = append(messages, s.Position.String()+": "+msg)
+		}
+		s.Init(strings.NewReader("a $"))
+		Expect(s.Scan()).To(Equal(rune(scanner.Ident)))
+		Expect(s.Scan()).To(Equal(rune(scanner.EOF)))
+		Expect(s.ErrorCount).To(Equal(1))
+		Expect(messages).To(Equal([]string{`<input>:1:3: unexpected '$'`}))
+	})
+})
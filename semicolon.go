@@ -0,0 +1,86 @@
+package lexer
+
+import "strings"
+
+// SemicolonStyle determines when a semicolon insertion pass inserts a terminator.
+type SemicolonStyle int
+
+const (
+	// SemicolonGo inserts a terminator after the final token of a line, or of the input, if
+	// that token can end a statement (see the Go specification).
+	SemicolonGo SemicolonStyle = iota
+
+	// SemicolonJS inserts a terminator between two tokens separated by a line break if the
+	// first can end a statement and the second cannot continue it, or if the first is a
+	// restricted token (e.g. return); before a closing brace; and at the end of the input
+	// (see the ECMAScript specification).
+	SemicolonJS
+)
+
+// SemicolonRules configures the terminators inserted by InsertSemicolons.
+type SemicolonRules struct {
+	Style SemicolonStyle
+
+	// Terminator is the token inserted, e.g. Token{Type: Semicolon, Value: ";"}. It is given
+	// the position of the token it follows.
+	Terminator Token
+
+	// Ends reports whether a statement can end with the token, e.g. an identifier, literal,
+	// or closing bracket.
+	Ends func(t Token) bool
+
+	// Continues reports whether the token can continue the statement on the preceding line,
+	// e.g. a binary operator or opening parenthesis (SemicolonJS only).
+	Continues func(t Token) bool
+
+	// Restricted reports whether a line break after the token always ends the statement, e.g.
+	// return or throw (SemicolonJS only).
+	Restricted func(t Token) bool
+
+	// Closes reports whether the token closes a block, e.g. a closing brace (SemicolonJS
+	// only).
+	Closes func(t Token) bool
+}
+
+// InsertSemicolons returns a RewritePass that inserts terminator tokens according to the
+// rules, based on the line breaks between tokens and the type of the preceding token.
+//
+// No terminator is inserted before a terminator already in the stream, or after an error,
+// warning, or trivia token.
+func InsertSemicolons(rules SemicolonRules) RewritePass {
+	is := func(predicate func(Token) bool, t Token) bool {
+		return predicate != nil && predicate(t)
+	}
+	return func(c *RewriteCursor) {
+		t, next := c.Token(), c.Peek(1)
+		if t.Type == TokenError || t.Type == TokenWarning || t.Type == TokenTrivia {
+			return
+		}
+		end := next.Line == 0
+		if !end && next.Type == rules.Terminator.Type {
+			return
+		}
+		lineBreak := end || next.Line > endLine(t)
+		var insert bool
+		switch rules.Style {
+		case SemicolonGo:
+			insert = lineBreak && is(rules.Ends, t)
+		case SemicolonJS:
+			insert = is(rules.Ends, t) && (end || is(rules.Closes, next) || lineBreak && !is(rules.Continues, next)) ||
+				lineBreak && is(rules.Restricted, t)
+		}
+		if insert {
+			c.Insert(rules.Terminator)
+		}
+	}
+}
+
+// endLine returns the line the token ends on, counting the line breaks in its raw text, if
+// captured, or otherwise its value.
+func endLine(t Token) int {
+	text := t.Raw
+	if text == "" {
+		text, _ = t.Value.(string)
+	}
+	return t.Line + strings.Count(text, "\n")
+}
@@ -0,0 +1,90 @@
+package lexer_test
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Semicolon", func() {
+	const (
+		Identifier lexer.TokenType = iota
+		Operator
+		Semicolon
+	)
+
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case r == ';':
+				l.Next()
+				l.Emit(Semicolon)
+			case strings.ContainsRune("+-(){}", r):
+				l.Next()
+				l.Emit(Operator)
+			default:
+				l.NextUpTo(func(r rune) bool {
+					return unicode.IsSpace(r) || strings.ContainsRune(";+-(){}", r) || r == lexer.EOF
+				})
+				l.Emit(Identifier)
+			}
+		}
+	}
+
+	values := func(input string, rules lexer.SemicolonRules) string {
+		var values []string
+		for t := range lexer.Rewrite(lexer.NewLexer(input, state).All(), lexer.InsertSemicolons(rules)) {
+			values = append(values, t.Value.(string))
+		}
+		return strings.Join(values, " ")
+	}
+
+	terminator := lexer.Token{Type: Semicolon, Value: ";"}
+
+	ends := func(t lexer.Token) bool {
+		return t.Type == Identifier || t.Value == ")" || t.Value == "}"
+	}
+
+	It("should insert terminators after the final token of a line (i.e. SemicolonGo)", func() {
+		rules := lexer.SemicolonRules{Style: lexer.SemicolonGo, Terminator: terminator, Ends: ends}
+		Expect(values("a +\nb\nf(\n)\nc;\n", rules)).To(Equal("a + b ; f ( ) ; c ;"))
+		Expect(values("x", rules)).To(Equal("x ;"))
+	})
+
+	It("should insert terminators where the next line cannot continue the statement (i.e. SemicolonJS)", func() {
+		rules := lexer.SemicolonRules{
+			Style:      lexer.SemicolonJS,
+			Terminator: terminator,
+			Ends:       ends,
+			Continues: func(t lexer.Token) bool {
+				return t.Value == "+" || t.Value == "("
+			},
+			Restricted: func(t lexer.Token) bool {
+				return t.Value == "return"
+			},
+			Closes: func(t lexer.Token) bool {
+				return t.Value == "}"
+			},
+		}
+		Expect(values("a\n+ b\nc\n(d)\ne", rules)).To(Equal("a + b ; c ( d ) ; e ;"))
+		Expect(values("{ return\nx }", rules)).To(Equal("{ return ; x ; } ;"))
+	})
+
+	It("should give inserted terminators the position of the preceding token (i.e. InsertSemicolons)", func() {
+		rules := lexer.SemicolonRules{Style: lexer.SemicolonGo, Terminator: terminator, Ends: ends}
+		var tokens []lexer.Token
+		for t := range lexer.Rewrite(lexer.NewLexer("a\n  b", state).All(), lexer.InsertSemicolons(rules)) {
+			tokens = append(tokens, t)
+		}
+		Expect(tokens).To(HaveLen(4))
+		Expect(tokens[3]).To(Equal(lexer.Token{Type: Semicolon, Value: ";", Position: 4, Line: 2, Column: 3}))
+	})
+})
@@ -0,0 +1,227 @@
+package lexer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+const snapshotMagic = "LXSN\x01"
+
+// Snapshot is a serializable checkpoint of a lexer's state, e.g. for checkpointing
+// long-running lexing jobs or moving work between processes (see WithSnapshot).
+//
+// State functions cannot be serialized; the state to resume in is given when the lexer is
+// resumed.
+type Snapshot struct {
+	Position   RunePosition
+	Start      RunePosition
+	Mode       Mode
+	ErrorCount int
+	modeStack  []Mode
+	length     int
+	trivia     []Token
+	triviaEnd  RunePosition
+	held       *Token
+	delimiters []delimiter
+	heredocs   []Heredoc
+}
+
+// Snapshot returns the state of the lexer: its position, the start of the runes consumed
+// since the last token was emitted, its mode and mode stack, the delimiters open (see
+// OpenDelim), the heredocs whose bodies are pending (see AcceptHeredoc), and any trivia not
+// yet attached to a token.
+//
+// The snapshot must be taken while the state machine is not running, i.e. from within a
+// state or between calls to Step. Returns an error if the state stack is not empty.
+func (l *Lexer) Snapshot() (Snapshot, error) {
+	if len(l.states) > 0 {
+		return Snapshot{}, errors.New("lexer: cannot snapshot a lexer with states on its state stack")
+	}
+	s := Snapshot{
+		Position:   l.CurrentPosition,
+		Start:      l.startPosition,
+		Mode:       l.mode,
+		ErrorCount: l.errorCount,
+		modeStack:  append([]Mode(nil), l.modeStack...),
+		length:     len(l.Input),
+		trivia:     append([]Token(nil), l.trivia...),
+		triviaEnd:  l.triviaEnd,
+		delimiters: append([]delimiter(nil), l.delimiters...),
+		heredocs:   append([]Heredoc(nil), l.heredocs...),
+	}
+	if l.heldToken != nil {
+		held := *l.heldToken
+		s.held = &held
+	}
+	return s, nil
+}
+
+// WithSnapshot resumes the lexer from the snapshot of a lexer over the same input, starting
+// in the initial state of the new lexer.
+//
+// The lexer emits an error token, and halts, if the input differs in length from the one
+// the snapshot was taken of.
+func WithSnapshot(s Snapshot) Option {
+	return func(l *Lexer) {
+		if s.length != len(l.Input) {
+			l.state = func(l *Lexer) StateFunc {
+				return l.Errorf("lexer: snapshot was taken of a different input")
+			}
+			return
+		}
+		l.CurrentPosition, l.startPosition = s.Position, s.Start
+		l.mode, l.errorCount = s.Mode, s.ErrorCount
+		l.modeStack = append([]Mode(nil), s.modeStack...)
+		l.trivia, l.triviaEnd = append([]Token(nil), s.trivia...), s.triviaEnd
+		l.delimiters = append([]delimiter(nil), s.delimiters...)
+		l.heredocs = append([]Heredoc(nil), s.heredocs...)
+		if s.held != nil {
+			held := *s.held
+			l.heldToken = &held
+		}
+	}
+}
+
+// MarshalBinary encodes the snapshot in the binary form of a TokenBinaryEncoder.
+func (s Snapshot) MarshalBinary() ([]byte, error) {
+	b := []byte(snapshotMagic)
+	for _, u := range []int{int(s.Position), int(s.Start), s.ErrorCount, s.length, int(s.triviaEnd)} {
+		b = binary.AppendUvarint(b, uint64(u))
+	}
+	b = binary.AppendVarint(b, int64(s.Mode))
+	b = binary.AppendUvarint(b, uint64(len(s.modeStack)))
+	for _, mode := range s.modeStack {
+		b = binary.AppendVarint(b, int64(mode))
+	}
+	tokens := s.trivia
+	if s.held != nil {
+		tokens = append([]Token{*s.held}, tokens...)
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+	b = binary.AppendUvarint(b, uint64(len(tokens)))
+	var err error
+	for _, t := range tokens {
+		if b, err = appendBinaryToken(b, t); err != nil {
+			return nil, err
+		}
+	}
+	b = binary.AppendUvarint(b, uint64(len(s.delimiters)))
+	for _, d := range s.delimiters {
+		b = binary.AppendUvarint(binary.AppendVarint(b, int64(d.r)), uint64(d.position))
+	}
+	b = binary.AppendUvarint(b, uint64(len(s.heredocs)))
+	for _, h := range s.heredocs {
+		b = binary.AppendUvarint(appendBinaryString(b, h.Delimiter), uint64(h.Indent))
+		if h.Quoted {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes a snapshot encoded by MarshalBinary.
+func (s *Snapshot) UnmarshalBinary(data []byte) error {
+	invalid := errors.New("lexer: invalid snapshot")
+	if !bytes.HasPrefix(data, []byte(snapshotMagic)) {
+		return invalid
+	}
+	d := &TokenBinaryDecoder{r: bufio.NewReader(bytes.NewReader(data[len(snapshotMagic):])), started: true}
+	var fields [5]uint64
+	for i := range fields {
+		u, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return invalid
+		}
+		fields[i] = u
+	}
+	mode, err := binary.ReadVarint(d.r)
+	if err != nil {
+		return invalid
+	}
+	n, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return invalid
+	}
+	var modeStack []Mode
+	for i := uint64(0); i < n; i++ {
+		m, err := binary.ReadVarint(d.r)
+		if err != nil {
+			return invalid
+		}
+		modeStack = append(modeStack, Mode(m))
+	}
+	held, err := d.r.ReadByte()
+	if err != nil {
+		return invalid
+	}
+	if n, err = binary.ReadUvarint(d.r); err != nil {
+		return invalid
+	}
+	var tokens []Token
+	for i := uint64(0); i < n; i++ {
+		t, err := d.token(0)
+		if err != nil {
+			return invalid
+		}
+		tokens = append(tokens, t)
+	}
+	if n, err = binary.ReadUvarint(d.r); err != nil {
+		return invalid
+	}
+	var delimiters []delimiter
+	for i := uint64(0); i < n; i++ {
+		r, err := binary.ReadVarint(d.r)
+		if err != nil {
+			return invalid
+		}
+		position, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return invalid
+		}
+		delimiters = append(delimiters, delimiter{rune(r), RunePosition(position)})
+	}
+	if n, err = binary.ReadUvarint(d.r); err != nil {
+		return invalid
+	}
+	var heredocs []Heredoc
+	for i := uint64(0); i < n; i++ {
+		var h Heredoc
+		if h.Delimiter, err = d.string(); err != nil {
+			return invalid
+		}
+		indent, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return invalid
+		}
+		quoted, err := d.r.ReadByte()
+		if err != nil {
+			return invalid
+		}
+		h.Indent, h.Quoted = HeredocIndent(indent), quoted == 1
+		heredocs = append(heredocs, h)
+	}
+	*s = Snapshot{
+		Position:   RunePosition(fields[0]),
+		Start:      RunePosition(fields[1]),
+		Mode:       Mode(mode),
+		ErrorCount: int(fields[2]),
+		modeStack:  modeStack,
+		length:     int(fields[3]),
+		triviaEnd:  RunePosition(fields[4]),
+		delimiters: delimiters,
+		heredocs:   heredocs,
+	}
+	if held == 1 && len(tokens) > 0 {
+		s.held, tokens = &tokens[0], tokens[1:]
+	}
+	if len(tokens) > 0 {
+		s.trivia = tokens
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshot", func() {
+	const Quoted lexer.Mode = 1
+
+	var state lexer.StateFunc
+	state = func(l *lexer.Lexer) lexer.StateFunc {
+		switch r := l.Peek(); {
+		case r == lexer.EOF:
+			return nil
+		case unicode.IsSpace(r):
+			l.Ignore()
+		case r == '"':
+			l.Ignore()
+			if l.Mode() == Quoted {
+				l.Begin(lexer.ModeInitial)
+			} else {
+				l.Begin(Quoted)
+			}
+		default:
+			l.NextUpTo(func(r rune) bool {
+				return unicode.IsSpace(r) || r == '"' || r == lexer.EOF
+			})
+			if l.Mode() == Quoted {
+				l.Emit(1)
+			} else {
+				l.Emit(Token)
+			}
+		}
+		return state
+	}
+
+	drain := func(l *lexer.Lexer) []lexer.Token {
+		var tokens []lexer.Token
+		for t := range l.All() {
+			tokens = append(tokens, t)
+		}
+		return tokens
+	}
+
+	It("should resume lexing from a serialized snapshot (i.e. Snapshot and WithSnapshot)", func() {
+		input := `a "b c" d`
+		l := lexer.NewLexer(input, state, lexer.WithSynchronous(), lexer.WithTrivia(lexer.TriviaLeading))
+		var before []lexer.Token
+		for len(before) < 2 {
+			emitted, _ := l.Step()
+			before = append(before, emitted...)
+		}
+		l.Step()
+		s, err := l.Snapshot()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Mode).To(Equal(Quoted))
+		data, err := s.MarshalBinary()
+		Expect(err).NotTo(HaveOccurred())
+
+		var restored lexer.Snapshot
+		Expect(restored.UnmarshalBinary(data)).To(Succeed())
+		Expect(restored).To(Equal(s))
+		after := drain(lexer.NewLexer(input, state, lexer.WithTrivia(lexer.TriviaLeading), lexer.WithSnapshot(restored)))
+		Expect(append(before, after...)).To(Equal(drain(lexer.NewLexer(input, state, lexer.WithTrivia(lexer.TriviaLeading)))))
+	})
+
+	It("should snapshot the mode stack (i.e. Snapshot and WithSnapshot)", func() {
+		var s lexer.Snapshot
+		l := lexer.NewLexer("ab", func(l *lexer.Lexer) lexer.StateFunc {
+			l.PushMode(Quoted)
+			l.PushMode(2)
+			var err error
+			s, err = l.Snapshot()
+			Expect(err).NotTo(HaveOccurred())
+			return nil
+		}, lexer.WithSynchronous())
+		l.Step()
+		data, err := s.MarshalBinary()
+		Expect(err).NotTo(HaveOccurred())
+		var restored lexer.Snapshot
+		Expect(restored.UnmarshalBinary(data)).To(Succeed())
+		Expect(restored).To(Equal(s))
+
+		var modes []lexer.Mode
+		lexer.NewLexer("ab", func(l *lexer.Lexer) lexer.StateFunc {
+			modes = append(modes, l.Mode())
+			for l.PopMode() {
+				modes = append(modes, l.Mode())
+			}
+			return nil
+		}, lexer.WithSynchronous(), lexer.WithSnapshot(restored)).Step()
+		Expect(modes).To(Equal([]lexer.Mode{2, Quoted, lexer.ModeInitial}))
+	})
+
+	It("should snapshot open delimiters (i.e. Snapshot and WithSnapshot)", func() {
+		input := "(a [b"
+		paren := func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.OpenDelim('(')
+			l.Emit(Token)
+			l.IgnoreUpTo(func(r rune) bool { return r == '[' })
+			l.Next()
+			l.OpenDelim('[')
+			l.Emit(Token)
+			return nil
+		}
+		l := lexer.NewLexer(input, paren, lexer.WithSynchronous())
+		l.Step()
+		s, err := l.Snapshot()
+		Expect(err).NotTo(HaveOccurred())
+		data, err := s.MarshalBinary()
+		Expect(err).NotTo(HaveOccurred())
+		var restored lexer.Snapshot
+		Expect(restored.UnmarshalBinary(data)).To(Succeed())
+		Expect(restored).To(Equal(s))
+
+		resumed := lexer.NewLexer(input, func(l *lexer.Lexer) lexer.StateFunc {
+			Expect(l.DelimDepth()).To(Equal(2))
+			Expect(l.CloseDelim(']')).To(BeTrue())
+			return nil
+		}, lexer.WithSnapshot(restored))
+		defer resumed.Close()
+		var errors []string
+		for t := range resumed.All() {
+			errors = append(errors, t.Value.(string))
+		}
+		Expect(errors).To(Equal([]string{`unclosed '('`}))
+	})
+
+	It("should snapshot pending heredocs (i.e. Snapshot and WithSnapshot)", func() {
+		input := "cat <<EOF <<-'END'\nbody\nEOF\n\tmore\nEND\n"
+		l := lexer.NewLexer(input, func(l *lexer.Lexer) lexer.StateFunc {
+			l.IgnoreUpTo(func(r rune) bool { return r == '<' })
+			l.AcceptHeredoc()
+			l.Ignore()
+			l.AcceptHeredoc()
+			return nil
+		}, lexer.WithSynchronous())
+		l.Step()
+		s, err := l.Snapshot()
+		Expect(err).NotTo(HaveOccurred())
+		data, err := s.MarshalBinary()
+		Expect(err).NotTo(HaveOccurred())
+		var restored lexer.Snapshot
+		Expect(restored.UnmarshalBinary(data)).To(Succeed())
+		Expect(restored).To(Equal(s))
+
+		tokens, err := lexer.Tokenize(input, func(l *lexer.Lexer) lexer.StateFunc {
+			l.Ignore()
+			l.ScanHeredocs(Token)
+			return nil
+		}, lexer.WithSnapshot(restored))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(2))
+		Expect(tokens[0].Value).To(Equal("body\n"))
+		Expect(tokens[1].Value).To(Equal("more\n"))
+	})
+
+	It("should not snapshot states on the state stack (i.e. Snapshot)", func() {
+		l := lexer.NewLexer("", func(l *lexer.Lexer) lexer.StateFunc {
+			l.PushState(nil)
+			_, err := l.Snapshot()
+			Expect(err).To(MatchError("lexer: cannot snapshot a lexer with states on its state stack"))
+			return nil
+		}, lexer.WithSynchronous())
+		l.Step()
+	})
+
+	It("should reject snapshots of a different input (i.e. WithSnapshot and UnmarshalBinary)", func() {
+		s, err := lexer.NewLexer("abc", state, lexer.WithSynchronous()).Snapshot()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = lexer.Tokenize("abcd", state, lexer.WithSnapshot(s))
+		Expect(err).To(MatchError("lexer: snapshot was taken of a different input"))
+		Expect(new(lexer.Snapshot).UnmarshalBinary([]byte("LXTK"))).To(MatchError("lexer: invalid snapshot"))
+	})
+
+	It("should reject corrupt snapshots without panicking (i.e. UnmarshalBinary)", func() {
+		// A snapshot of one trivia token whose string value has a corrupt length.
+		data := []byte("LXSN\x01\x00\x00\x00\x00\x00\x00\x00\x00\x01\x00\x00\x00\x00\x01\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01")
+		Expect(func() {
+			Expect(new(lexer.Snapshot).UnmarshalBinary(data)).To(MatchError("lexer: invalid snapshot"))
+		}).NotTo(Panic())
+	})
+})
@@ -0,0 +1,75 @@
+package lexer
+
+import (
+	"bufio"
+	"strings"
+	"unicode"
+	"unsafe"
+)
+
+// AsSplitFunc returns a bufio.SplitFunc that splits its input into the input text of the
+// tokens emitted by a lexer starting in the specified state, so that tokenizations built
+// with this package can be plugged into a bufio.Scanner.
+//
+// Warning and trivia tokens are skipped; an error token stops the scanner with its
+// diagnostic as the error. Since the lexer is restarted at the beginning of each token, the
+// state should not depend on the tokens emitted before it.
+//
+// Trivia preceding a token that may continue beyond the data is advanced over, as is data
+// consisting only of trivia, except trivia other than whitespace that may continue beyond
+// it.
+func AsSplitFunc(state StateFunc, options ...Option) bufio.SplitFunc {
+	options = append(options[:len(options):len(options)], WithSynchronous(), WithLossless())
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if len(data) == 0 {
+			return 0, nil, nil
+		}
+		// The lexer reads the data in place, rather than a copy of it, and only lexes it up
+		// to the end of the first token.
+		l := NewLexer(unsafe.String(&data[0], len(data)), state, options...)
+		defer l.Close()
+		for {
+			t, ok := l.receive()
+			if !ok {
+				if atEOF {
+					return len(data), nil, nil
+				}
+				return 0, nil, nil
+			}
+			switch t.Type {
+			case TokenWarning:
+				continue
+			case TokenTrivia:
+				if atEOF {
+					continue
+				}
+				return skipTrivia(t.Trivia, len(data)), nil, nil
+			case TokenError:
+				if !atEOF && int(l.CurrentPosition) == len(data) {
+					return 0, nil, nil
+				}
+				e, _ := t.LexError()
+				e.Message = strings.Clone(e.Message)
+				return 0, nil, e
+			}
+			end := int(t.Position) + len(t.Raw)
+			if !atEOF && end == len(data) {
+				return int(t.Position), nil, nil
+			}
+			return end, data[t.Position:end], nil
+		}
+	}
+}
+
+// skipTrivia returns the end of the trivia that cannot continue beyond the end of the data.
+func skipTrivia(trivia []Token, end int) int {
+	skip := 0
+	for _, t := range trivia {
+		e := int(t.Position) + len(t.Raw)
+		if e == end && strings.TrimFunc(t.Raw, unicode.IsSpace) != "" {
+			break
+		}
+		skip = e
+	}
+	return skip
+}
@@ -0,0 +1,89 @@
+package lexer_test
+
+import (
+	"bufio"
+	"strings"
+	"testing/iotest"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Split", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case r == '"':
+				l.Next()
+				if l.NextUpTo(func(r rune) bool {
+					return r == '"' || r == lexer.EOF
+				}) == lexer.EOF {
+					return l.ErrorCodef("E1", "unterminated string")
+				}
+				l.Next()
+				l.Emit(Token)
+			default:
+				l.NextUpTo(func(r rune) bool {
+					return unicode.IsSpace(r) || r == '"' || r == lexer.EOF
+				})
+				l.Emit(Token)
+			}
+		}
+	}
+
+	scan := func(s *bufio.Scanner) ([]string, error) {
+		var tokens []string
+		for s.Scan() {
+			tokens = append(tokens, s.Text())
+		}
+		return tokens, s.Err()
+	}
+
+	It("should split the input into tokens (i.e. AsSplitFunc)", func() {
+		s := bufio.NewScanner(strings.NewReader(`say "hello world"  twice `))
+		s.Split(lexer.AsSplitFunc(state))
+		tokens, err := scan(s)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(Equal([]string{"say", `"hello world"`, "twice"}))
+	})
+
+	It("should request more data for tokens that may continue beyond it (i.e. AsSplitFunc)", func() {
+		s := bufio.NewScanner(iotest.OneByteReader(strings.NewReader(`abc "d e" f`)))
+		s.Split(lexer.AsSplitFunc(state))
+		tokens, err := scan(s)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(Equal([]string{"abc", `"d e"`, "f"}))
+	})
+
+	It("should stop the scanner at error tokens (i.e. AsSplitFunc)", func() {
+		s := bufio.NewScanner(strings.NewReader(`a "b`))
+		s.Split(lexer.AsSplitFunc(state))
+		tokens, err := scan(s)
+		Expect(tokens).To(Equal([]string{"a"}))
+		Expect(err).To(MatchError("E1 unterminated string"))
+	})
+	It("should advance over data consisting only of trivia (i.e. AsSplitFunc)", func() {
+		split := lexer.AsSplitFunc(state)
+		advance, token, err := split([]byte("   "), false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(advance).To(Equal(3))
+		Expect(token).To(BeNil())
+		advance, token, err = split([]byte("  abc"), false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(advance).To(Equal(2))
+		Expect(token).To(BeNil())
+
+		s := bufio.NewScanner(strings.NewReader(strings.Repeat(" ", 1<<17) + "a"))
+		s.Split(split)
+		tokens, err := scan(s)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(Equal([]string{"a"}))
+	})
+})
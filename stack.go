@@ -0,0 +1,24 @@
+package lexer
+
+// PushState pushes the state onto the lexer's state stack, so that a state lexing a nested
+// construct (e.g. an interpolated expression) can return to it with PopState.
+func (l *Lexer) PushState(s StateFunc) {
+	l.states = append(l.states, s)
+}
+
+// PopState pops and returns the state most recently pushed onto the lexer's state stack.
+//
+// Returns nil, finishing the lexer, if the stack is empty.
+func (l *Lexer) PopState() StateFunc {
+	if len(l.states) == 0 {
+		return nil
+	}
+	s := l.states[len(l.states)-1]
+	l.states = l.states[:len(l.states)-1]
+	return s
+}
+
+// StateDepth returns the number of states on the lexer's state stack.
+func (l *Lexer) StateDepth() int {
+	return len(l.states)
+}
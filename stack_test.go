@@ -0,0 +1,74 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stack", func() {
+	const (
+		Text lexer.TokenType = iota
+		Open
+		Close
+	)
+
+	// lexText lexes strings like "a${b${c}d}e", where expressions may nest strings.
+	var lexText, lexExpr lexer.StateFunc
+	lexText = func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch l.Peek() {
+			case lexer.EOF:
+				l.EmitNonEmpty(Text)
+				return l.PopState()
+			case '$':
+				l.EmitNonEmpty(Text)
+				l.Next()
+				l.Next()
+				l.Emit(Open)
+				l.PushState(lexText)
+				return lexExpr
+			case '}':
+				if l.StateDepth() > 0 {
+					l.EmitNonEmpty(Text)
+					return l.PopState()
+				}
+				l.Next()
+			default:
+				l.Next()
+			}
+		}
+	}
+	lexExpr = func(l *lexer.Lexer) lexer.StateFunc {
+		if l.Peek() == '}' {
+			l.Next()
+			l.Emit(Close)
+			return l.PopState()
+		}
+		l.PushState(lexExpr)
+		return lexText
+	}
+
+	It("should return to the pushed states of nested constructs (i.e. PushState and PopState)", func() {
+		tokens, err := lexer.Tokenize("a${b${c}d}e", lexText)
+		Expect(err).NotTo(HaveOccurred())
+		var values []interface{}
+		for _, t := range tokens {
+			values = append(values, t.Value)
+		}
+		Expect(values).To(Equal([]interface{}{"a", "${", "b", "${", "c", "}", "d", "}", "e"}))
+	})
+
+	It("should finish the lexer when the state stack is empty (i.e. PopState)", func() {
+		steps := 0
+		tokens, err := lexer.Tokenize("", func(l *lexer.Lexer) lexer.StateFunc {
+			steps++
+			Expect(l.StateDepth()).To(Equal(0))
+			return l.PopState()
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(BeEmpty())
+		Expect(steps).To(Equal(1))
+	})
+})
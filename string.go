@@ -0,0 +1,306 @@
+package lexer
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// TokenString represents a type of token that contains a string literal (see ScanString).
+	TokenString TokenType = -8
+
+	// TokenChar represents a type of token that contains a character literal (see
+	// ScanCharLiteral).
+	TokenChar TokenType = -9
+)
+
+// EscapeSet is the set of escape sequences valid in the string literals of a language (see
+// ScanString). The quote of a literal, and the escape rune itself, can always be escaped.
+type EscapeSet struct {
+	// Escape is the rune introducing escape sequences, usually '\\', or 0 if there are none.
+	Escape rune
+
+	// Simple maps the runes following the escape rune to the runes they denote, e.g. 'n' to
+	// '\n'.
+	Simple map[rune]rune
+
+	// Hex enables \xHH (a byte), Octal \OOO (a byte, with three octal digits), Unicode
+	// \uHHHH, LongUnicode \UHHHHHHHH, and BracedUnicode \u{H...} (with one to six digits).
+	Hex, Octal, Unicode, LongUnicode, BracedUnicode bool
+
+	// Surrogates combines UTF-16 surrogate pairs of \u escapes (e.g. \ud83d\ude00) into the
+	// rune they encode, as in JSON.
+	Surrogates bool
+
+	// LineContinuation removes escaped newlines from the value of a string.
+	LineContinuation bool
+
+	// Newlines allows unescaped newlines in strings.
+	Newlines bool
+
+	// Doubled makes a doubled quote denote the quote itself (e.g. 'it''s' denotes it's), as
+	// in SQL.
+	Doubled bool
+
+	// Permissive makes escape sequences that are not otherwise valid denote the escaped rune
+	// (e.g. \q denotes q), as in MySQL, instead of being reported as unknown.
+	Permissive bool
+}
+
+// The escape sequences of common languages.
+var (
+	GoEscapes = EscapeSet{
+		Escape:      '\\',
+		Simple:      map[rune]rune{'a': '\a', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t', 'v': '\v'},
+		Hex:         true,
+		Octal:       true,
+		Unicode:     true,
+		LongUnicode: true,
+	}
+	JSONEscapes = EscapeSet{
+		Escape:     '\\',
+		Simple:     map[rune]rune{'/': '/', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t'},
+		Unicode:    true,
+		Surrogates: true,
+	}
+)
+
+// ScanString scans a string literal enclosed in the quote at the current position of the
+// lexer, returning its value with its escape sequences replaced. The literal is consumed,
+// not emitted; emit the raw literal with Emit, or its value with EmitValue.
+//
+// Returns an error, a LexError, if the string contains an invalid escape sequence, having
+// consumed the literal, or if it is unterminated, positioned at its opening quote, having
+// consumed the runes up to the end of the line (or input).
+func ScanString(l *Lexer, quote rune, escapes EscapeSet) (string, error) {
+	start := l.CurrentPosition
+	if l.Peek() != quote {
+		return "", l.errorAt(start, "expected %q", quote)
+	}
+	l.Next()
+	var b strings.Builder
+	var err error
+	for {
+		r := l.Next()
+		switch {
+		case r == quote && escapes.Doubled && l.Peek() == quote:
+			l.Next()
+			b.WriteRune(quote)
+		case r == quote:
+			return b.String(), err
+		case r == EOF, r == '\n' && !escapes.Newlines:
+			if r == '\n' {
+				l.Previous()
+			}
+			return b.String(), l.errorAt(start, "unterminated string")
+		case r == escapes.Escape && escapes.Escape != 0:
+			v, isByte, e := escapes.unescape(l, quote)
+			switch {
+			case isByte:
+				b.WriteByte(byte(v))
+			case v >= 0:
+				b.WriteRune(v)
+			}
+			if err == nil {
+				err = e
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
+
+// unescape consumes the escape sequence following an escape rune, returning the rune it
+// denotes, or -1 if it denotes none (e.g. a line continuation), and whether the rune is a
+// byte (e.g. \xff) rather than a code point.
+func (s EscapeSet) unescape(l *Lexer, quote rune) (rune, bool, error) {
+	position := l.CurrentPosition - RunePosition(utf8.RuneLen(s.Escape))
+	r := l.Peek()
+	if r == EOF {
+		return -1, false, nil
+	}
+	l.Next()
+	if simple, ok := s.Simple[r]; ok {
+		return simple, false, nil
+	}
+	switch {
+	case r == quote, r == s.Escape:
+		return r, false, nil
+	case r == '\n' && s.LineContinuation:
+		return -1, false, nil
+	case r == 'x' && s.Hex:
+		v, err := s.hex(l, 2, 2)
+		return v, true, err
+	case '0' <= r && r <= '7' && s.Octal:
+		v := r - '0'
+		for i := 0; i < 2; i++ {
+			d := l.Peek()
+			if d < '0' || d > '7' {
+				return v, true, l.errorAt(l.CurrentPosition, "invalid character %q in octal escape", d)
+			}
+			v = v*8 + d - '0'
+			l.Next()
+		}
+		if v > 255 {
+			return v & 0xFF, true, l.errorAt(position, "octal escape value %d > 255", v)
+		}
+		return v, true, nil
+	case r == 'u' && s.BracedUnicode && l.Peek() == '{':
+		l.Next()
+		v, err := s.hex(l, 1, 6)
+		if err != nil {
+			return v, false, err
+		}
+		if l.Peek() != '}' {
+			return v, false, l.errorAt(l.CurrentPosition, "expected '}' in Unicode escape")
+		}
+		l.Next()
+		return s.codePoint(l, v, position)
+	case r == 'u' && s.Unicode:
+		v, err := s.hex(l, 4, 4)
+		if err != nil {
+			return v, false, err
+		}
+		if s.Surrogates && 0xD800 <= v && v < 0xDC00 && l.Peek() == s.Escape && l.peekAt(1) == 'u' {
+			next := l.CurrentPosition
+			l.Next()
+			l.Next()
+			low, err := s.hex(l, 4, 4)
+			if err != nil {
+				return v, false, err
+			}
+			if low < 0xDC00 || low > 0xDFFF {
+				return v, false, l.errorAt(next, "invalid surrogate pair")
+			}
+			v = 0x10000 + (v-0xD800)<<10 + (low - 0xDC00)
+		}
+		return s.codePoint(l, v, position)
+	case r == 'U' && s.LongUnicode:
+		v, err := s.hex(l, 8, 8)
+		if err != nil {
+			return v, false, err
+		}
+		return s.codePoint(l, v, position)
+	case s.Permissive:
+		return r, false, nil
+	}
+	return r, false, l.errorAt(position, "unknown escape sequence")
+}
+
+// hex consumes from min to max hexadecimal digits, returning their value.
+func (s EscapeSet) hex(l *Lexer, min, max int) (rune, error) {
+	v := rune(0)
+	for i := 0; i < max; i++ {
+		d := l.Peek()
+		if !isDigit(d, 16) {
+			if i >= min {
+				break
+			}
+			return v, l.errorAt(l.CurrentPosition, "invalid character %q in hexadecimal escape", d)
+		}
+		l.Next()
+		v = v*16 + hexValue(d)
+	}
+	return v, nil
+}
+
+// codePoint returns the code point, or an error if it is not a valid Unicode code point.
+func (s EscapeSet) codePoint(l *Lexer, v rune, position RunePosition) (rune, bool, error) {
+	if !utf8.ValidRune(v) && !(s.Surrogates && 0xD800 <= v && v <= 0xDFFF) {
+		return utf8.RuneError, false, l.errorAt(position, "escape sequence is invalid Unicode code point")
+	}
+	return v, false, nil
+}
+
+func hexValue(d rune) rune {
+	switch {
+	case '0' <= d && d <= '9':
+		return d - '0'
+	case 'a' <= d && d <= 'f':
+		return d - 'a' + 10
+	}
+	return d - 'A' + 10
+}
+
+// ScanRawString scans a raw string literal enclosed in the open and close delimiters (e.g.
+// "`" and "`" in Go, or `r#"` and `"#` in Rust) at the current position of the lexer,
+// returning its contents, which have no escape sequences. If doubled is true a doubled close
+// delimiter stands for the delimiter itself (e.g. a doubled quote in SQL) rather than ending the
+// literal. The literal is consumed, not emitted, as ScanString does.
+//
+// Returns an error, a LexError positioned at the open delimiter, if the literal is
+// unterminated, having consumed the rest of the input.
+func ScanRawString(l *Lexer, open, close string, doubled bool) (string, error) {
+	start := l.CurrentPosition
+	if !strings.HasPrefix(l.Input[start:], open) {
+		return "", l.errorAt(start, "expected %q", open)
+	}
+	l.advance(len(open))
+	var b strings.Builder
+	for {
+		rest := l.Input[l.CurrentPosition:]
+		i := strings.Index(rest, close)
+		if i < 0 {
+			b.WriteString(rest)
+			l.advance(len(rest))
+			return b.String(), l.errorAt(start, "unterminated raw string")
+		}
+		b.WriteString(rest[:i])
+		l.advance(i + len(close))
+		if !doubled || !strings.HasPrefix(rest[i+len(close):], close) {
+			return b.String(), nil
+		}
+		b.WriteString(close)
+		l.advance(len(close))
+	}
+}
+
+// ScanCharLiteral scans a single-quoted character literal of exactly one rune, or one escape
+// sequence, at the current position of the lexer (e.g. 'a' or '\n'), returning its value.
+// The literal is consumed, not emitted, as ScanString does.
+//
+// Returns an error, a LexError, if the literal contains an invalid escape sequence, or if it
+// is empty, has more than one rune, or is unterminated, positioned at its opening quote.
+func ScanCharLiteral(l *Lexer, escapes EscapeSet) (rune, error) {
+	start := l.CurrentPosition
+	if l.Peek() != '\'' {
+		return 0, l.errorAt(start, "expected '\\''")
+	}
+	l.Next()
+	value, n := rune(0), 0
+	var err error
+	for {
+		r := l.Next()
+		switch {
+		case r == '\'':
+			switch {
+			case err != nil:
+				return value, err
+			case n == 0:
+				return value, l.errorAt(start, "empty character literal")
+			case n > 1:
+				return value, l.errorAt(start, "more than one character in character literal")
+			}
+			return value, nil
+		case r == EOF, r == '\n':
+			if r == '\n' {
+				l.Previous()
+			}
+			return value, l.errorAt(start, "unterminated character literal")
+		case r == escapes.Escape && escapes.Escape != 0:
+			v, _, e := escapes.unescape(l, '\'')
+			if v >= 0 {
+				if n++; n == 1 {
+					value = v
+				}
+			}
+			if err == nil {
+				err = e
+			}
+		default:
+			if n++; n == 1 {
+				value = r
+			}
+		}
+	}
+}
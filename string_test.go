@@ -0,0 +1,162 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("String", func() {
+	It("should scan string literals replacing escape sequences (i.e. ScanString)", func() {
+		for input, value := range map[string]string{
+			`"abc"`:                      `abc`,
+			`""`:                         ``,
+			`"a\tb\n"`:                   "a\tb\n",
+			`"\"\\"`:                     `"\`,
+			`"\x41\101\u00e9\U0001F600"`: "AAé😀",
+			`"ü"`:                        "ü",
+		} {
+			l := lexer.NewLexer(input+" x", nil)
+			v, err := lexer.ScanString(l, '"', lexer.GoEscapes)
+			Expect(err).NotTo(HaveOccurred(), input)
+			Expect(v).To(Equal(value), input)
+			Expect(l.Input[:l.CurrentPosition]).To(Equal(input))
+		}
+		l := lexer.NewLexer(`'it\'s'`, nil)
+		Expect(lexer.ScanString(l, '\'', lexer.EscapeSet{Escape: '\\'})).To(Equal("it's"))
+		l = lexer.NewLexer(`"\ud83d\ude00\/"`, nil)
+		Expect(lexer.ScanString(l, '"', lexer.JSONEscapes)).To(Equal("😀/"))
+		l = lexer.NewLexer("\"a\\\nb\nc\"", nil)
+		Expect(lexer.ScanString(l, '"', lexer.EscapeSet{Escape: '\\', LineContinuation: true, Newlines: true})).To(Equal("ab\nc"))
+		l = lexer.NewLexer(`"\u{1F600}"`, nil)
+		Expect(lexer.ScanString(l, '"', lexer.EscapeSet{Escape: '\\', Unicode: true, BracedUnicode: true})).To(Equal("😀"))
+		l = lexer.NewLexer(`"\q\n"`, nil)
+		Expect(lexer.ScanString(l, '"', lexer.EscapeSet{Escape: '\\', Simple: map[rune]rune{'n': '\n'}, Permissive: true})).To(Equal("q\n"))
+		l = lexer.NewLexer(`'it''s' ''`, nil)
+		Expect(lexer.ScanString(l, '\'', lexer.EscapeSet{Doubled: true})).To(Equal("it's"))
+	})
+
+	It("should report invalid escape sequences (i.e. ScanString)", func() {
+		for input, message := range map[string]string{
+			`"\q"`:         "unknown escape sequence",
+			`"\'"`:         "unknown escape sequence",
+			`"\x4g"`:       "invalid character 'g' in hexadecimal escape",
+			`"\400"`:       "octal escape value 256 > 255",
+			`"\18"`:        "invalid character '8' in octal escape",
+			`"\uD800"`:     "escape sequence is invalid Unicode code point",
+			`"\U00110000"`: "escape sequence is invalid Unicode code point",
+			`x`:            `expected '"'`,
+		} {
+			l := lexer.NewLexer(input, nil)
+			_, err := lexer.ScanString(l, '"', lexer.GoEscapes)
+			Expect(err).To(MatchError(message), input)
+		}
+		l := lexer.NewLexer(`"a\qb" x`, nil)
+		v, err := lexer.ScanString(l, '"', lexer.GoEscapes)
+		Expect(v).To(Equal("aqb"))
+		Expect(err.(lexer.LexError).Position).To(Equal(lexer.RunePosition(2)))
+		Expect(l.CurrentPosition).To(Equal(lexer.RunePosition(6)))
+	})
+
+	It("should report unterminated strings at their opening quote (i.e. ScanString)", func() {
+		l := lexer.NewLexer("x = \"abc\ny", nil)
+		l.Ignore()
+		l.Ignore()
+		l.Ignore()
+		l.Ignore()
+		_, err := lexer.ScanString(l, '"', lexer.GoEscapes)
+		Expect(err).To(MatchError("unterminated string"))
+		Expect(err.(lexer.LexError).Position).To(Equal(lexer.RunePosition(4)))
+		Expect(l.Peek()).To(Equal('\n'))
+		l = lexer.NewLexer(`"abc\"`, nil)
+		_, err = lexer.ScanString(l, '"', lexer.GoEscapes)
+		Expect(err).To(MatchError("unterminated string"))
+	})
+
+	It("should emit either the raw literal or its value (i.e. EmitValue)", func() {
+		raw := func(l *lexer.Lexer) lexer.StateFunc {
+			if _, err := lexer.ScanString(l, '"', lexer.GoEscapes); err != nil {
+				return l.ReportError(err)
+			}
+			l.Emit(lexer.TokenString)
+			return nil
+		}
+		tokens, err := lexer.Tokenize(`"a\n"`, raw)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(Equal([]lexer.Token{{Type: lexer.TokenString, Value: `"a\n"`, Line: 1, Column: 1}}))
+		value := func(l *lexer.Lexer) lexer.StateFunc {
+			v, err := lexer.ScanString(l, '"', lexer.GoEscapes)
+			if err != nil {
+				return l.ReportError(err)
+			}
+			l.EmitValue(lexer.TokenString, v)
+			return nil
+		}
+		l := lexer.NewLexer(`"a\n"`, value, lexer.WithLossless())
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: lexer.TokenString, Value: "a\n", Raw: `"a\n"`}))
+		l = lexer.NewLexer(`"abc`, value)
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: lexer.TokenError, Value: "unterminated string"}))
+	})
+
+	It("should scan raw string literals (i.e. ScanRawString)", func() {
+		l := lexer.NewLexer("`a\\n\nb` x", nil)
+		Expect(lexer.ScanRawString(l, "`", "`", false)).To(Equal("a\\n\nb"))
+		Expect(l.Input[l.CurrentPosition:]).To(Equal(" x"))
+		l = lexer.NewLexer(`r#"say "hi""# x`, nil)
+		Expect(lexer.ScanRawString(l, `r#"`, `"#`, false)).To(Equal(`say "hi"`))
+		Expect(l.Input[l.CurrentPosition:]).To(Equal(" x"))
+		l = lexer.NewLexer(`'it''s''' x`, nil)
+		Expect(lexer.ScanRawString(l, "'", "'", true)).To(Equal("it's'"))
+		Expect(l.Input[l.CurrentPosition:]).To(Equal(" x"))
+		l = lexer.NewLexer(`'it''s`, nil)
+		Expect(lexer.ScanRawString(l, "'", "'", false)).To(Equal("it"))
+		Expect(l.Input[:l.CurrentPosition]).To(Equal(`'it'`))
+	})
+
+	It("should report unterminated raw string literals (i.e. ScanRawString)", func() {
+		l := lexer.NewLexer("x `abc", nil)
+		l.Ignore()
+		l.Ignore()
+		v, err := lexer.ScanRawString(l, "`", "`", false)
+		Expect(v).To(Equal("abc"))
+		Expect(err).To(MatchError("unterminated raw string"))
+		Expect(err.(lexer.LexError).Position).To(Equal(lexer.RunePosition(2)))
+		Expect(l.Peek()).To(Equal(lexer.EOF))
+		l = lexer.NewLexer("x", nil)
+		_, err = lexer.ScanRawString(l, "`", "`", false)
+		Expect(err).To(MatchError("expected \"`\""))
+	})
+
+	It("should scan character literals (i.e. ScanCharLiteral)", func() {
+		for input, value := range map[string]rune{`'a'`: 'a', `'é'`: 'é', `'\n'`: '\n', `'\''`: '\'', `'\xff'`: 0xFF, `'\377'`: 0xFF} {
+			l := lexer.NewLexer(input+" x", nil)
+			v, err := lexer.ScanCharLiteral(l, lexer.GoEscapes)
+			Expect(err).NotTo(HaveOccurred(), input)
+			Expect(v).To(Equal(value), input)
+			Expect(l.Input[:l.CurrentPosition]).To(Equal(input))
+		}
+	})
+
+	It("should report malformed character literals (i.e. ScanCharLiteral)", func() {
+		for input, message := range map[string]string{
+			`''`:    "empty character literal",
+			`'ab'`:  "more than one character in character literal",
+			`'\na'`: "more than one character in character literal",
+			`'\q'`:  "unknown escape sequence",
+			`'a`:    "unterminated character literal",
+			"'\n'":  "unterminated character literal",
+			`a`:     `expected '\''`,
+		} {
+			l := lexer.NewLexer(input, nil)
+			_, err := lexer.ScanCharLiteral(l, lexer.GoEscapes)
+			Expect(err).To(MatchError(message), input)
+		}
+		l := lexer.NewLexer(`x 'ab' y`, nil)
+		l.Ignore()
+		l.Ignore()
+		_, err := lexer.ScanCharLiteral(l, lexer.GoEscapes)
+		Expect(err.(lexer.LexError).Position).To(Equal(lexer.RunePosition(2)))
+		Expect(l.Input[l.CurrentPosition:]).To(Equal(" y"))
+	})
+})
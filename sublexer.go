@@ -0,0 +1,59 @@
+package lexer
+
+// RunSubLexer runs a nested state machine, starting in the specified state, over the region
+// of the input from the runes consumed since the last token was emitted up to, but not
+// including, the first rune satisfying the predicate (or the end of the input), and emits
+// its tokens in place of the region.
+//
+// The positions of the tokens emitted by the nested state machine, including their trivia
+// and diagnostics, point into the input of the lexer. The nested lexer is created with the
+// specified options. Returns the rune last seen by the predicate.
+func (l *Lexer) RunSubLexer(state StateFunc, until RunePredicate, options ...Option) rune {
+	r := l.Peek()
+	for r != EOF && !until(r) {
+		l.Next()
+		r = l.Peek()
+	}
+	offset := l.startPosition
+	sub := NewLexer(l.Input[offset:l.CurrentPosition], state, append(options[:len(options):len(options)], WithSynchronous())...)
+	var tokens []Token
+	for t := range sub.All() {
+		tokens = append(tokens, t)
+	}
+	l.checkDeadline()
+	l.emitMutex.Lock()
+	defer l.emitMutex.Unlock()
+	diagnostics := sub.diagnostics
+	for _, t := range tokens {
+		if (t.Type == TokenError || t.Type == TokenWarning) && len(diagnostics) > 0 {
+			// The diagnostic of an error reported with a message as its value is kept
+			// alongside it, with its own position.
+			e := diagnostics[0]
+			diagnostics = diagnostics[1:]
+			e.Position += offset + l.origin.Position
+			l.reported = &e
+		}
+		l.sendLocked(l.relocate(t, offset))
+	}
+	l.startPosition = l.CurrentPosition
+	return r
+}
+
+// relocate returns the token of a nested lexer with its position, and the positions of its
+// trivia and diagnostic, moved by the offset of its input into the input of the lexer, and
+// by the origin of the lexer.
+func (l *Lexer) relocate(t Token, offset RunePosition) Token {
+	l.setPosition(&t, t.Position+offset)
+	if e, ok := t.Value.(LexError); ok {
+		e.Position += offset + l.origin.Position
+		t.Value = e
+	}
+	if len(t.Trivia) > 0 {
+		trivia := make([]Token, len(t.Trivia))
+		for i, u := range t.Trivia {
+			trivia[i] = l.relocate(u, offset)
+		}
+		t.Trivia = trivia
+	}
+	return t
+}
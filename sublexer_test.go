@@ -0,0 +1,128 @@
+package lexer_test
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SubLexer", func() {
+	const (
+		Text lexer.TokenType = iota
+		Delimiter
+		Keyword
+		Identifier
+	)
+
+	sql := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case r == '!':
+				return l.ErrorCodef("E1", "unexpected %q", r)
+			default:
+				l.NextUpTo(func(r rune) bool {
+					return unicode.IsSpace(r) || r == lexer.EOF
+				})
+				if strings.ToUpper(l.Input[l.CurrentPosition-1:l.CurrentPosition]) == l.Input[l.CurrentPosition-1:l.CurrentPosition] {
+					l.Emit(Keyword)
+				} else {
+					l.Emit(Identifier)
+				}
+			}
+		}
+	}
+
+	template := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch {
+			case l.Peek() == lexer.EOF:
+				l.EmitNonEmpty(Text)
+				return nil
+			case strings.HasPrefix(l.Input[l.CurrentPosition:], "{{"):
+				l.EmitNonEmpty(Text)
+				l.Next()
+				l.Next()
+				l.Emit(Delimiter)
+				l.RunSubLexer(sql, func(r rune) bool {
+					return r == '}'
+				})
+				l.Next()
+				l.Next()
+				l.Emit(Delimiter)
+			default:
+				l.Next()
+			}
+		}
+	}
+
+	It("should merge the tokens of a nested state machine with their positions (i.e. RunSubLexer)", func() {
+		tokens, err := lexer.Tokenize("a\n{{SELECT x}}b", template)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(6))
+		Expect(tokens[2]).To(EqualToken(lexer.Token{Type: Keyword, Value: "SELECT"}))
+		Expect(tokens[2].Position).To(Equal(lexer.RunePosition(4)))
+		Expect(tokens[2].Line).To(Equal(2))
+		Expect(tokens[2].Column).To(Equal(3))
+		Expect(tokens[3]).To(EqualToken(lexer.Token{Type: Identifier, Value: "x"}))
+		Expect(tokens[3].Column).To(Equal(10))
+		Expect(tokens[4]).To(EqualToken(lexer.Token{Type: Delimiter, Value: "}}"}))
+		Expect(tokens[5]).To(EqualToken(lexer.Token{Type: Text, Value: "b"}))
+	})
+
+	It("should report diagnostics of the nested state machine at their positions (i.e. RunSubLexer)", func() {
+		tokens, err := lexer.Tokenize("ab{{ ! }}", template)
+		Expect(tokens).To(HaveLen(2))
+		Expect(err).To(Equal(lexer.LexError{Code: "E1", Message: "unexpected '!'", Position: 5, Severity: lexer.SeverityError}))
+	})
+
+	It("should report diagnostics of the nested state machine relative to the origin (i.e. RunSubLexer)", func() {
+		_, err := lexer.Tokenize("ab{{ ! }}", template, lexer.WithOrigin(100, 5, 1))
+		Expect(err.(lexer.LexError).Position).To(Equal(lexer.RunePosition(105)))
+
+		l := lexer.NewLexer(`{{ "abc }}`, func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Next()
+			l.Emit(Delimiter)
+			l.RunSubLexer(func(l *lexer.Lexer) lexer.StateFunc {
+				l.Ignore()
+				_, err := lexer.ScanString(l, '"', lexer.GoEscapes)
+				return l.ReportError(err)
+			}, func(r rune) bool {
+				return r == '}'
+			})
+			return nil
+		}, lexer.WithOrigin(100, 5, 1))
+		defer l.Close()
+		for range l.All() {
+		}
+		diagnostics := l.Diagnostics()
+		Expect(diagnostics).To(HaveLen(1))
+		Expect(diagnostics[0].Message).To(Equal("unterminated string"))
+		Expect(diagnostics[0].Position).To(Equal(lexer.RunePosition(103)))
+	})
+
+	It("should attach trivia of the nested state machine at their positions (i.e. RunSubLexer)", func() {
+		l := lexer.NewLexer("{{  x}}", func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Next()
+			l.Ignore()
+			l.RunSubLexer(sql, func(r rune) bool {
+				return r == '}'
+			}, lexer.WithTrivia(lexer.TriviaLeading))
+			return nil
+		}, lexer.WithTrivia(lexer.TriviaLeading))
+		t := l.NextToken()
+		Expect(t.Value).To(Equal("x"))
+		Expect(t.Position).To(Equal(lexer.RunePosition(4)))
+		Expect(t.Trivia).To(HaveLen(2))
+		Expect(t.Trivia[1].Position).To(Equal(lexer.RunePosition(3)))
+	})
+})
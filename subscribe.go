@@ -0,0 +1,58 @@
+package lexer
+
+import "iter"
+
+type subscriber struct {
+	queue []Token
+}
+
+// Subscribe returns an independent stream of the tokens emitted by the lexer, so that
+// several consumers (e.g. a highlighter and a parser) can share a single lexing pass.
+//
+// Each subscriber receives every token received by any subscriber once it has subscribed;
+// tokens are buffered for subscribers that fall behind. Subscribers may be consumed from
+// different goroutines, but the lexer must not otherwise be consumed once Subscribe has been
+// called. Breaking out of a subscription unsubscribes it without closing the lexer.
+func (l *Lexer) Subscribe() iter.Seq[Token] {
+	s := &subscriber{}
+	l.subscribeMutex.Lock()
+	l.subscribers = append(l.subscribers, s)
+	l.subscribeMutex.Unlock()
+	return func(yield func(Token) bool) {
+		defer l.unsubscribe(s)
+		for {
+			t, ok := l.receiveSubscribed(s)
+			if !ok || !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+func (l *Lexer) receiveSubscribed(s *subscriber) (Token, bool) {
+	l.subscribeMutex.Lock()
+	defer l.subscribeMutex.Unlock()
+	if len(s.queue) == 0 {
+		t, ok := l.receive()
+		if !ok {
+			return t, false
+		}
+		for _, u := range l.subscribers {
+			u.queue = append(u.queue, t)
+		}
+	}
+	t := s.queue[0]
+	s.queue = s.queue[1:]
+	return t, true
+}
+
+func (l *Lexer) unsubscribe(s *subscriber) {
+	l.subscribeMutex.Lock()
+	defer l.subscribeMutex.Unlock()
+	for i, u := range l.subscribers {
+		if u == s {
+			l.subscribers = append(l.subscribers[:i:i], l.subscribers[i+1:]...)
+			return
+		}
+	}
+}
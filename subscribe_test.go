@@ -0,0 +1,65 @@
+package lexer_test
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Subscribe", func() {
+	var state lexer.StateFunc
+	state = func(l *lexer.Lexer) lexer.StateFunc {
+		if l.Next() == lexer.EOF {
+			return nil
+		}
+		l.Emit(Token)
+		return state
+	}
+
+	values := func(tokens iter.Seq[lexer.Token]) []interface{} {
+		var values []interface{}
+		for t := range tokens {
+			values = append(values, t.Value)
+		}
+		return values
+	}
+
+	It("should deliver every token to each subscriber (i.e. Subscribe)", func() {
+		l := lexer.NewLexer("abc", state)
+		highlighter, parser := l.Subscribe(), l.Subscribe()
+		Expect(values(highlighter)).To(Equal([]interface{}{"a", "b", "c"}))
+		Expect(values(parser)).To(Equal([]interface{}{"a", "b", "c"}))
+	})
+
+	It("should allow subscribers to be consumed concurrently (i.e. Subscribe)", func() {
+		l := lexer.NewLexer("abcdef", state)
+		subscriptions := []iter.Seq[lexer.Token]{l.Subscribe(), l.Subscribe(), l.Subscribe()}
+		results := make([][]interface{}, len(subscriptions))
+		var wg sync.WaitGroup
+		for i, s := range subscriptions {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = values(s)
+			}()
+		}
+		wg.Wait()
+		for _, r := range results {
+			Expect(r).To(Equal([]interface{}{"a", "b", "c", "d", "e", "f"}))
+		}
+	})
+
+	It("should unsubscribe without closing the lexer when breaking out of a subscription (i.e. Subscribe)", func() {
+		l := lexer.NewLexer("abc", state, lexer.WithSynchronous())
+		first, second := l.Subscribe(), l.Subscribe()
+		for range first {
+			break
+		}
+		Expect(values(second)).To(Equal([]interface{}{"a", "b", "c"}))
+		Expect(values(first)).To(BeEmpty())
+	})
+})
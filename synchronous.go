@@ -0,0 +1,60 @@
+package lexer
+
+// WithSynchronous runs the state machine on the caller's goroutine instead of a goroutine
+// of its own.
+//
+// NextToken executes states until one emits a token, eliminating the overhead of
+// goroutines and channels. A synchronous lexer must only be used from one goroutine at a
+// time.
+func WithSynchronous() Option {
+	return func(l *Lexer) {
+		l.synchronous = true
+	}
+}
+
+func (l *Lexer) fetchSynchronously() (Token, bool) {
+	for len(l.queue) == 0 && l.step() {
+	}
+	if len(l.queue) == 0 {
+		return Token{}, false
+	}
+	t := l.queue[0]
+	l.queue[0] = Token{}
+	l.queue = l.queue[1:]
+	return t, true
+}
+
+func (l *Lexer) forwardTokens() {
+	go func() {
+		defer close(l.tokens)
+		for {
+			t, ok := l.fetchSynchronously()
+			if !ok {
+				return
+			}
+			select {
+			case l.tokens <- t:
+			case <-l.done:
+				return
+			}
+		}
+	}()
+}
+
+// Step executes exactly one state of a lexer created with WithSynchronous and returns the
+// tokens emitted while doing so, along with whether the state machine has finished.
+//
+// Step panics if the lexer was not created with WithSynchronous.
+func (l *Lexer) Step() (emitted []Token, done bool) {
+	if !l.synchronous {
+		panic("lexer: Step called on a lexer created without WithSynchronous")
+	}
+	if !l.closed() {
+		l.step()
+	}
+	emitted, l.queue = l.queue, nil
+	for _, t := range emitted {
+		l.track(t)
+	}
+	return emitted, l.finished || l.closed()
+}
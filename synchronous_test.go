@@ -0,0 +1,94 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Synchronous lexer", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Token)
+			}
+		}
+	}
+
+	values := func(l *lexer.Lexer) []interface{} {
+		var values []interface{}
+		for t := range l.All() {
+			values = append(values, t.Value)
+		}
+		return values
+	}
+
+	It("should drive the state machine on the caller's goroutine (i.e. WithSynchronous)", func() {
+		ran := false
+		l := lexer.NewLexer("a^2 + b^2", func(l *lexer.Lexer) lexer.StateFunc {
+			ran = true
+			return state
+		}, lexer.WithSynchronous())
+		Expect(ran).To(BeFalse())
+		assertValue := func(t lexer.Token, value string) {
+			Expect(t).To(EqualToken(lexer.Token{Type: Token, Value: value}))
+		}
+		assertValue(l.NextToken(), "a^2")
+		Expect(ran).To(BeTrue())
+		assertValue(l.NextToken(), "+")
+		assertValue(l.NextToken(), "b^2")
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{}))
+	})
+
+	It("should deliver tokens emitted once the state machine finishes", func() {
+		l := lexer.NewLexer(" a ", state, lexer.WithSynchronous(), lexer.WithTrivia(lexer.TriviaTrailing))
+		Expect(values(l)).To(Equal([]interface{}{"", "a"}))
+	})
+
+	It("should send tokens on a channel (i.e. Tokens)", func() {
+		l := lexer.NewLexer("x y", state, lexer.WithSynchronous())
+		var values []interface{}
+		for t := range l.Tokens() {
+			values = append(values, t.Value)
+		}
+		Expect(values).To(Equal([]interface{}{"x", "y"}))
+	})
+
+	It("should execute exactly one state at a time (i.e. Step)", func() {
+		var second lexer.StateFunc
+		first := func(l *lexer.Lexer) lexer.StateFunc {
+			l.Next()
+			l.Emit(Token)
+			l.Next()
+			l.Emit(Token)
+			return second
+		}
+		second = func(l *lexer.Lexer) lexer.StateFunc {
+			l.Ignore()
+			return nil
+		}
+		l := lexer.NewLexer("E=m", first, lexer.WithSynchronous())
+		emitted, done := l.Step()
+		Expect(emitted).To(HaveLen(2))
+		Expect(emitted[1]).To(EqualToken(lexer.Token{Type: Token, Value: "="}))
+		Expect(done).To(BeFalse())
+		emitted, done = l.Step()
+		Expect(emitted).To(BeEmpty())
+		Expect(done).To(BeFalse())
+		emitted, done = l.Step()
+		Expect(emitted).To(BeEmpty())
+		Expect(done).To(BeTrue())
+		Expect(func() {
+			lexer.NewLexer("", nil).Step()
+		}).To(Panic())
+	})
+})
@@ -0,0 +1,25 @@
+package lexer
+
+// TokenWriter is implemented by sinks that tokens can be written to, e.g. a
+// TokenJSONLinesWriter.
+type TokenWriter interface {
+	WriteToken(t Token) error
+}
+
+// Tee writes every token emitted by the lexer from now on to the writer, as transformed by
+// any middleware already in use, in addition to delivering it to the consumer.
+//
+// Once the writer returns an error it is no longer written to; the error is returned by
+// the function Tee returns, which should only be called once the lexer has finished.
+func (l *Lexer) Tee(w TokenWriter) func() error {
+	var err error
+	l.Use(func(t Token) []Token {
+		if err == nil {
+			err = w.WriteToken(t)
+		}
+		return []Token{t}
+	})
+	return func() error {
+		return err
+	}
+}
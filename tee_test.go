@@ -0,0 +1,66 @@
+package lexer_test
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type tokenRecorder struct {
+	tokens []lexer.Token
+	limit  int
+}
+
+func (r *tokenRecorder) WriteToken(t lexer.Token) error {
+	if r.limit > 0 && len(r.tokens) == r.limit {
+		return errors.New("recorder full")
+	}
+	r.tokens = append(r.tokens, t)
+	return nil
+}
+
+var _ = Describe("Tee", func() {
+	var state lexer.StateFunc
+	state = func(l *lexer.Lexer) lexer.StateFunc {
+		if l.Next() == lexer.EOF {
+			return nil
+		}
+		l.Emit(Token)
+		return state
+	}
+
+	It("should write every emitted token to the writer as well as the consumer (i.e. Tee)", func() {
+		l := lexer.NewLexer("abc", state, lexer.WithSynchronous())
+		r := &tokenRecorder{}
+		teeErr := l.Tee(r)
+		var consumed []lexer.Token
+		for t := range l.All() {
+			consumed = append(consumed, t)
+		}
+		Expect(teeErr()).To(Succeed())
+		Expect(consumed).To(HaveLen(3))
+		Expect(r.tokens).To(Equal(consumed))
+	})
+
+	It("should write tokens in any format implementing TokenWriter (i.e. Tee)", func() {
+		var buf bytes.Buffer
+		l := lexer.NewLexer("a", state, lexer.WithSynchronous())
+		l.Tee(lexer.NewTokenJSONLinesWriter(&buf))
+		l.NextToken()
+		Expect(buf.String()).To(Equal(`{"type":0,"value":"a","position":0,"line":1,"column":1}` + "\n"))
+	})
+
+	It("should stop writing once the writer returns an error (i.e. Tee)", func() {
+		l := lexer.NewLexer("abc", state, lexer.WithSynchronous())
+		r := &tokenRecorder{limit: 1}
+		teeErr := l.Tee(r)
+		for range l.All() {
+		}
+		Expect(teeErr()).To(MatchError("recorder full"))
+		Expect(r.tokens).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,107 @@
+package lexer
+
+import "strings"
+
+const (
+	// TokenText represents a type of token that contains the literal text of a template (see
+	// TemplateDelimiters).
+	TokenText TokenType = -20
+
+	// TokenLeftDelim represents a type of token that contains the delimiter opening an action
+	// of a template (see TemplateDelimiters).
+	TokenLeftDelim TokenType = -21
+
+	// TokenRightDelim represents a type of token that contains the delimiter closing an action
+	// of a template (see TemplateDelimiters).
+	TokenRightDelim TokenType = -22
+)
+
+// ModeAction is the mode in which the actions of a template are lexed, unless the Mode of
+// its TemplateDelimiters is set.
+const ModeAction Mode = -1
+
+// TemplateDelimiters are the delimiters of the actions of a template language, as set by the
+// Delims method of text/template, e.g. "{{" and "}}", the default if empty.
+type TemplateDelimiters struct {
+	Open, Close string
+
+	// Mode is the mode in which the actions are lexed, ModeAction if ModeInitial.
+	Mode Mode
+}
+
+// TemplateState returns a state lexing a template with the default delimiters, "{{" and "}}"
+// (see TemplateDelimiters.State).
+func TemplateState(action StateFunc) StateFunc {
+	return TemplateDelimiters{}.State(action)
+}
+
+// State returns a state lexing a template: the literal text up to each open delimiter is
+// emitted as a token of type TokenText, unless empty, and the delimiter as a token of type
+// TokenLeftDelim. The action that follows is lexed in the mode of the delimiters (see
+// PushMode) by the action state, and the states it returns, until the close delimiter,
+// which is emitted as a token of type TokenRightDelim, returning to the mode of the text,
+// e.g.
+//
+//	state := lexer.TemplateDelimiters{Open: "<%", Close: "%>"}.State(expression)
+//
+// The close delimiter is recognized between the tokens of the action, so the action state
+// should lex at most one token each time it is called, and may consume literals containing
+// the close delimiter (e.g. strings). Each action is lexed starting over at the action
+// state, and lexing stops if a state returns nil (e.g. having emitted an error).
+//
+// An error token is emitted if the input ends in an action, positioned at its open
+// delimiter.
+func (d TemplateDelimiters) State(action StateFunc) StateFunc {
+	open, close := d.Open, d.Close
+	if open == "" {
+		open = "{{"
+	}
+	if close == "" {
+		close = "}}"
+	}
+	mode := d.Mode
+	if mode == ModeInitial {
+		mode = ModeAction
+	}
+	return func(l *Lexer) StateFunc {
+		// The variables of each lexer are its own, so that the state can be shared by
+		// lexers running concurrently.
+		var text, inAction StateFunc
+		var start RunePosition
+		next := action
+		text = func(l *Lexer) StateFunc {
+			rest := l.Input[l.CurrentPosition:]
+			i := strings.Index(rest, open)
+			if i < 0 {
+				l.advance(len(rest))
+				l.EmitNonEmpty(TokenText)
+				return nil
+			}
+			l.advance(i)
+			l.EmitNonEmpty(TokenText)
+			start = l.CurrentPosition
+			l.advance(len(open))
+			l.Emit(TokenLeftDelim)
+			l.PushMode(mode)
+			next = action
+			return inAction
+		}
+		inAction = func(l *Lexer) StateFunc {
+			rest := l.Input[l.CurrentPosition:]
+			switch {
+			case strings.HasPrefix(rest, close):
+				l.advance(len(close))
+				l.Emit(TokenRightDelim)
+				l.PopMode()
+				return text
+			case rest == "":
+				return l.ReportError(l.errorAt(start, "unclosed action"))
+			}
+			if next = next(l); next == nil {
+				return nil
+			}
+			return inAction
+		}
+		return text(l)
+	}
+}
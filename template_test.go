@@ -0,0 +1,113 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template", func() {
+	var action lexer.StateFunc
+	action = func(l *lexer.Lexer) lexer.StateFunc {
+		switch r := l.Peek(); {
+		case r == ' ':
+			l.Ignore()
+		case r == '"':
+			if _, err := lexer.ScanString(l, '"', lexer.GoEscapes); err != nil {
+				return l.ReportError(err)
+			}
+			l.Emit(lexer.TokenString)
+		default:
+			if err := lexer.ScanIdentifier(l); err != nil {
+				return l.ReportError(err)
+			}
+			l.Emit(lexer.TokenIdentifier)
+		}
+		return action
+	}
+
+	It("should lex text and actions with the default delimiters (i.e. TemplateState)", func() {
+		tokens, err := lexer.Tokenize(`Hello, {{ name }}! {{print "}}"}}`, lexer.TemplateState(action))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(9))
+		for i, expected := range []lexer.Token{
+			{Type: lexer.TokenText, Value: "Hello, "},
+			{Type: lexer.TokenLeftDelim, Value: "{{"},
+			{Type: lexer.TokenIdentifier, Value: "name"},
+			{Type: lexer.TokenRightDelim, Value: "}}"},
+			{Type: lexer.TokenText, Value: "! "},
+			{Type: lexer.TokenLeftDelim, Value: "{{"},
+			{Type: lexer.TokenIdentifier, Value: "print"},
+			{Type: lexer.TokenString, Value: `"}}"`},
+			{Type: lexer.TokenRightDelim, Value: "}}"},
+		} {
+			Expect(tokens[i].Type).To(Equal(expected.Type), "token %d", i)
+			Expect(tokens[i].Value).To(Equal(expected.Value), "token %d", i)
+		}
+		Expect(tokens[2].Position).To(Equal(lexer.RunePosition(10)))
+	})
+
+	It("should lex text and actions with custom delimiters (i.e. State)", func() {
+		tokens, err := lexer.Tokenize("<%a%><%b%>{{c}}", lexer.TemplateDelimiters{Open: "<%", Close: "%>"}.State(action))
+		Expect(err).NotTo(HaveOccurred())
+		var values []interface{}
+		for _, t := range tokens {
+			values = append(values, t.Value)
+		}
+		Expect(values).To(Equal([]interface{}{"<%", "a", "%>", "<%", "b", "%>", "{{c}}"}))
+	})
+
+	It("should lex actions in the mode of the delimiters (i.e. State)", func() {
+		var modes []lexer.Mode
+		mode := func(l *lexer.Lexer) lexer.StateFunc {
+			modes = append(modes, l.Mode())
+			return action(l)
+		}
+		tokens, err := lexer.Tokenize("{{a}}{{b}}", lexer.TemplateState(mode))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(6))
+		Expect(modes).To(Equal([]lexer.Mode{lexer.ModeAction, lexer.ModeAction}))
+
+		modes = nil
+		l := lexer.NewLexer("<%a%> b", lexer.TemplateDelimiters{Open: "<%", Close: "%>", Mode: 7}.State(mode))
+		defer l.Close()
+		for range l.All() {
+		}
+		Expect(modes).To(Equal([]lexer.Mode{7}))
+		Expect(l.Mode()).To(Equal(lexer.ModeInitial))
+	})
+
+	It("should be shared by lexers running concurrently (i.e. State)", func() {
+		state := lexer.TemplateState(action)
+		results := make(chan []lexer.Token)
+		for i := 0; i < 2; i++ {
+			go func() {
+				defer GinkgoRecover()
+				tokens, err := lexer.Tokenize("a {{ b }} c {{ d }}", state)
+				Expect(err).NotTo(HaveOccurred())
+				results <- tokens
+			}()
+		}
+		for i := 0; i < 2; i++ {
+			Expect(<-results).To(HaveLen(8))
+		}
+	})
+
+	It("should report unclosed actions (i.e. State)", func() {
+		l := lexer.NewLexer("text {{ name", lexer.TemplateState(action))
+		defer l.Close()
+		for range l.All() {
+		}
+		diagnostics := l.Diagnostics()
+		Expect(diagnostics).To(HaveLen(1))
+		Expect(diagnostics[0].Message).To(Equal("unclosed action"))
+		Expect(diagnostics[0].Position).To(Equal(lexer.RunePosition(5)))
+	})
+
+	It("should stop if the action state returns nil (i.e. State)", func() {
+		tokens, err := lexer.Tokenize("a {{ ! }} b", lexer.TemplateState(action))
+		Expect(err).To(MatchError("expected an identifier"))
+		Expect(tokens).To(HaveLen(2))
+	})
+})
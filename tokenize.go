@@ -0,0 +1,24 @@
+package lexer
+
+// Tokenize runs a lexer created from the input, initial state, and options to completion
+// and returns every token it emitted.
+//
+// If the lexer emits an error token, lexing stops and the tokens emitted before it are
+// returned along with the error it carries, as a LexError. Warnings are returned like any
+// other token.
+func Tokenize(input string, initialState StateFunc, options ...Option) ([]Token, error) {
+	l := NewLexer(input, initialState, options...)
+	defer l.Close()
+	var tokens []Token
+	for t := range l.All() {
+		if t.Type == TokenError {
+			e, _ := t.LexError()
+			if e.Position == 0 {
+				e.Position = t.Position
+			}
+			return tokens, e
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,54 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tokenize", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case r == '?':
+				return l.Errorf("unexpected %q", r)
+			case unicode.IsSpace(r):
+				l.Ignore()
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Token)
+			}
+		}
+	}
+
+	values := func(tokens []lexer.Token) []interface{} {
+		var values []interface{}
+		for _, t := range tokens {
+			values = append(values, t.Value)
+		}
+		return values
+	}
+
+	It("should return every emitted token", func() {
+		tokens, err := lexer.Tokenize("x := y", state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values(tokens)).To(Equal([]interface{}{"x", ":=", "y"}))
+	})
+
+	It("should stop at the first error", func() {
+		tokens, err := lexer.Tokenize("x ? y", state)
+		Expect(values(tokens)).To(Equal([]interface{}{"x"}))
+		Expect(err).To(Equal(lexer.LexError{Message: "unexpected '?'", Position: 2, Severity: lexer.SeverityError}))
+	})
+
+	It("should apply the specified options", func() {
+		tokens, err := lexer.Tokenize("x", state, lexer.WithLossless())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens[0].Raw).To(Equal("x"))
+	})
+})
@@ -0,0 +1,91 @@
+package tokenstream
+
+import (
+	"iter"
+	"sort"
+
+	"github.com/eczarny/lexer"
+)
+
+// Source represents the stream of tokens emitted from a single named input, e.g. a file of
+// a program assembled from several files or string fragments.
+type Source struct {
+	Name   string
+	Size   int
+	Tokens iter.Seq[lexer.Token]
+
+	// Close, if set, releases the source if its tokens are not all consumed, e.g. closing
+	// its lexer.
+	Close func()
+}
+
+// FromLexer returns the source of the tokens emitted by the lexer.
+func FromLexer(name string, l *lexer.Lexer) Source {
+	return Source{Name: name, Size: len(l.Input), Tokens: l.All(), Close: l.Close}
+}
+
+// SourceMap maps positions in a stream joined from several sources back to their sources.
+type SourceMap struct {
+	names []string
+	bases []lexer.RunePosition
+}
+
+// Join returns a single stream of the tokens of each source in turn and the source map for
+// its positions.
+//
+// Each source is assigned a distinct range of positions, as go/token.FileSet does, so the
+// positions of tokens (including their trivia and diagnostics) from different sources never
+// collide; lines and columns are left relative to the token's own source. Breaking out of
+// the stream closes the sources whose tokens have not all been consumed.
+func Join(sources ...Source) (iter.Seq[lexer.Token], *SourceMap) {
+	m := &SourceMap{}
+	var base lexer.RunePosition
+	for _, s := range sources {
+		m.names = append(m.names, s.Name)
+		m.bases = append(m.bases, base)
+		base += lexer.RunePosition(s.Size) + 1
+	}
+	tokens := func(yield func(lexer.Token) bool) {
+		for i, s := range sources {
+			for t := range s.Tokens {
+				if !yield(rebase(t, m.bases[i])) {
+					for _, s := range sources[i:] {
+						if s.Close != nil {
+							s.Close()
+						}
+					}
+					return
+				}
+			}
+		}
+	}
+	return tokens, m
+}
+
+// Locate returns the name of the source the position of a joined stream falls within and
+// the position relative to that source.
+func (m *SourceMap) Locate(p lexer.RunePosition) (name string, position lexer.RunePosition, ok bool) {
+	i := sort.Search(len(m.bases), func(i int) bool {
+		return m.bases[i] > p
+	}) - 1
+	if i < 0 || p < 0 {
+		return "", 0, false
+	}
+	return m.names[i], p - m.bases[i], true
+}
+
+func rebase(t lexer.Token, base lexer.RunePosition) lexer.Token {
+	t.Position += base
+	if e, ok := t.Value.(lexer.LexError); ok {
+		e.Position += base
+		t.Value = e
+	}
+	if len(t.Trivia) > 0 {
+		trivia := make([]lexer.Token, len(t.Trivia))
+		for i, u := range t.Trivia {
+			trivia[i] = rebase(u, base)
+		}
+		t.Trivia = trivia
+	}
+	return t
+}
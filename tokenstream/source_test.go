@@ -0,0 +1,84 @@
+package tokenstream_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/tokenstream"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Source", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case r == '!':
+				return l.ErrorCodef("E1", "unexpected %q", r)
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Word)
+			}
+		}
+	}
+
+	It("should join the streams of several sources with distinct positions (i.e. Join)", func() {
+		tokens, m := tokenstream.Join(
+			tokenstream.FromLexer("a.txt", lexer.NewLexer("x y", state)),
+			tokenstream.FromLexer("b.txt", lexer.NewLexer("z\n!", state)),
+		)
+		var joined []lexer.Token
+		for t := range tokens {
+			joined = append(joined, t)
+		}
+		Expect(joined).To(HaveLen(4))
+		Expect(joined[2].Value).To(Equal("z"))
+		Expect(joined[2].Position).To(Equal(lexer.RunePosition(4)))
+		Expect(joined[2].Line).To(Equal(1))
+		Expect(joined[3].Value.(lexer.LexError).Position).To(Equal(lexer.RunePosition(6)))
+		Expect(joined[3].Line).To(Equal(2))
+
+		name, position, ok := m.Locate(joined[1].Position)
+		Expect(ok).To(BeTrue())
+		Expect(name).To(Equal("a.txt"))
+		Expect(position).To(Equal(lexer.RunePosition(2)))
+		name, position, ok = m.Locate(joined[3].Position)
+		Expect(ok).To(BeTrue())
+		Expect(name).To(Equal("b.txt"))
+		Expect(position).To(Equal(lexer.RunePosition(2)))
+	})
+
+	It("should close the remaining sources when the stream is broken out of (i.e. Join)", func() {
+		a, b := lexer.NewLexer("x y", state), lexer.NewLexer("z", state)
+		tokens, _ := tokenstream.Join(tokenstream.FromLexer("a.txt", a), tokenstream.FromLexer("b.txt", b))
+		for range tokens {
+			break
+		}
+		_, err := a.ReadToken()
+		Expect(err).To(Equal(lexer.ErrClosed))
+		_, err = b.ReadToken()
+		Expect(err).To(Equal(lexer.ErrClosed))
+	})
+
+	It("should join sources of canned tokens and string fragments (i.e. Join and Locate)", func() {
+		tokens, m := tokenstream.Join(
+			tokenstream.Source{Name: "prelude", Size: 3, Tokens: func(yield func(lexer.Token) bool) {
+				yield(lexer.Token{Type: Word, Value: "abc", Trivia: []lexer.Token{{Type: lexer.TokenTrivia, Value: " ", Position: 3}}})
+			}},
+			tokenstream.FromLexer("fragment", lexer.NewLexer("d", state)),
+		)
+		var joined []lexer.Token
+		for t := range tokens {
+			joined = append(joined, t)
+		}
+		Expect(joined[0].Trivia[0].Position).To(Equal(lexer.RunePosition(3)))
+		Expect(joined[1].Position).To(Equal(lexer.RunePosition(4)))
+		_, _, ok := m.Locate(-1)
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,111 @@
+// Package tokenstream provides operations for composing the processing of token streams
+// emitted by a lexer.
+//
+// Streams are represented as iterators (see Lexer.All); FromChan and ToChan convert between
+// iterators and channels (see Lexer.Tokens).
+package tokenstream
+
+import (
+	"iter"
+
+	"github.com/eczarny/lexer"
+)
+
+// FromChan returns an iterator over the tokens received from the channel.
+func FromChan(tokens <-chan lexer.Token) iter.Seq[lexer.Token] {
+	return func(yield func(lexer.Token) bool) {
+		for t := range tokens {
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// ToChan returns a channel on which the tokens of the stream are sent by a goroutine; the
+// channel is closed once the stream is exhausted. The stream must be exhausted, or the
+// goroutine is leaked.
+func ToChan(tokens iter.Seq[lexer.Token]) <-chan lexer.Token {
+	ch := make(chan lexer.Token)
+	go func() {
+		defer close(ch)
+		for t := range tokens {
+			ch <- t
+		}
+	}()
+	return ch
+}
+
+// Map returns a stream of the tokens transformed by the function.
+func Map(tokens iter.Seq[lexer.Token], f func(lexer.Token) lexer.Token) iter.Seq[lexer.Token] {
+	return func(yield func(lexer.Token) bool) {
+		for t := range tokens {
+			if !yield(f(t)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns a stream of the tokens that satisfy the predicate.
+func Filter(tokens iter.Seq[lexer.Token], predicate func(lexer.Token) bool) iter.Seq[lexer.Token] {
+	return func(yield func(lexer.Token) bool) {
+		for t := range tokens {
+			if predicate(t) && !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhile returns a stream of the tokens up to, but not including, the first token that
+// does not satisfy the predicate.
+func TakeWhile(tokens iter.Seq[lexer.Token], predicate func(lexer.Token) bool) iter.Seq[lexer.Token] {
+	return func(yield func(lexer.Token) bool) {
+		for t := range tokens {
+			if !predicate(t) || !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// Buffer returns a stream that reads up to n tokens ahead of its consumer on a goroutine of
+// its own, decoupling a slow consumer from a slow producer.
+//
+// Breaking out of the iteration stops the goroutine.
+func Buffer(tokens iter.Seq[lexer.Token], n int) iter.Seq[lexer.Token] {
+	return func(yield func(lexer.Token) bool) {
+		buffer := make(chan lexer.Token, n)
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			defer close(buffer)
+			for t := range tokens {
+				select {
+				case buffer <- t:
+				case <-done:
+					return
+				}
+			}
+		}()
+		for t := range buffer {
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// Concat returns a stream of the tokens of each stream in turn.
+func Concat(streams ...iter.Seq[lexer.Token]) iter.Seq[lexer.Token] {
+	return func(yield func(lexer.Token) bool) {
+		for _, tokens := range streams {
+			for t := range tokens {
+				if !yield(t) {
+					return
+				}
+			}
+		}
+	}
+}
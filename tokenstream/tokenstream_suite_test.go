@@ -0,0 +1,13 @@
+package tokenstream_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestTokenstream(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tokenstream Suite")
+}
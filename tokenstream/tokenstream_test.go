@@ -0,0 +1,87 @@
+package tokenstream_test
+
+import (
+	"iter"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/tokenstream"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	Word lexer.TokenType = iota
+	Number
+)
+
+var _ = Describe("Tokenstream", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case unicode.IsDigit(r):
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Number)
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Word)
+			}
+		}
+	}
+
+	stream := func(input string) iter.Seq[lexer.Token] {
+		return lexer.NewLexer(input, state).All()
+	}
+
+	values := func(tokens iter.Seq[lexer.Token]) []interface{} {
+		var values []interface{}
+		for t := range tokens {
+			values = append(values, t.Value)
+		}
+		return values
+	}
+
+	isWord := func(t lexer.Token) bool {
+		return t.Type == Word
+	}
+
+	It("should transform tokens (i.e. Map)", func() {
+		upper := tokenstream.Map(stream("a 1 b"), func(t lexer.Token) lexer.Token {
+			t.Value = strings.ToUpper(t.Value.(string))
+			return t
+		})
+		Expect(values(upper)).To(Equal([]interface{}{"A", "1", "B"}))
+	})
+
+	It("should keep tokens satisfying a predicate (i.e. Filter)", func() {
+		Expect(values(tokenstream.Filter(stream("a 1 b 2"), isWord))).To(Equal([]interface{}{"a", "b"}))
+	})
+
+	It("should stop at the first token not satisfying a predicate (i.e. TakeWhile)", func() {
+		Expect(values(tokenstream.TakeWhile(stream("a b 1 c"), isWord))).To(Equal([]interface{}{"a", "b"}))
+	})
+
+	It("should read ahead of its consumer (i.e. Buffer)", func() {
+		Expect(values(tokenstream.Buffer(stream("a 1 b"), 2))).To(Equal([]interface{}{"a", "1", "b"}))
+		for t := range tokenstream.Buffer(stream("a 1 b"), 1) {
+			Expect(t.Value).To(Equal("a"))
+			break
+		}
+	})
+
+	It("should concatenate streams (i.e. Concat)", func() {
+		Expect(values(tokenstream.Concat(stream("a 1"), stream("b"), slices.Values([]lexer.Token{{Value: "c"}})))).To(Equal([]interface{}{"a", "1", "b", "c"}))
+	})
+
+	It("should convert between channels and iterators (i.e. FromChan and ToChan)", func() {
+		Expect(values(tokenstream.FromChan(tokenstream.ToChan(stream("a 1"))))).To(Equal([]interface{}{"a", "1"}))
+		Expect(values(tokenstream.FromChan(lexer.NewLexer("b 2", state).Tokens()))).To(Equal([]interface{}{"b", "2"}))
+	})
+})
@@ -0,0 +1,81 @@
+package lexer
+
+import (
+	"strconv"
+	"sync"
+)
+
+var tokenTypes = struct {
+	sync.RWMutex
+	names map[TokenType]string
+	types map[string]TokenType
+}{
+	names: make(map[TokenType]string),
+	types: make(map[string]TokenType),
+}
+
+func init() {
+	RegisterTokenType(TokenError, "lexer.ERROR")
+	RegisterTokenType(TokenWarning, "lexer.WARNING")
+	RegisterTokenType(TokenTrivia, "lexer.TRIVIA")
+	RegisterTokenType(TokenTooManyErrors, "lexer.TOO_MANY_ERRORS")
+	RegisterTokenType(TokenIllegal, "lexer.ILLEGAL")
+	RegisterTokenType(TokenInt, "lexer.INT")
+	RegisterTokenType(TokenFloat, "lexer.FLOAT")
+	RegisterTokenType(TokenString, "lexer.STRING")
+	RegisterTokenType(TokenChar, "lexer.CHAR")
+	RegisterTokenType(TokenComment, "lexer.COMMENT")
+	RegisterTokenType(TokenIdentifier, "lexer.IDENT")
+	RegisterTokenType(TokenWhitespace, "lexer.WS")
+	RegisterTokenType(TokenNewline, "lexer.NEWLINE")
+	RegisterTokenType(TokenDate, "lexer.DATE")
+	RegisterTokenType(TokenTime, "lexer.TIME")
+	RegisterTokenType(TokenDateTime, "lexer.DATETIME")
+	RegisterTokenType(TokenDuration, "lexer.DURATION")
+	RegisterTokenType(TokenURL, "lexer.URL")
+	RegisterTokenType(TokenEmail, "lexer.EMAIL")
+	RegisterTokenType(TokenText, "lexer.TEXT")
+	RegisterTokenType(TokenLeftDelim, "lexer.LEFT_DELIM")
+	RegisterTokenType(TokenRightDelim, "lexer.RIGHT_DELIM")
+}
+
+// RegisterTokenType associates a name with the specified token type, replacing any name
+// it was registered with, and taking the name from any token type it was registered for.
+//
+// Registered names are used whenever tokens are printed or encoded, e.g. by Dump. The names
+// of the token types of this package are prefixed with "lexer.", e.g. "lexer.IDENT".
+func RegisterTokenType(tokenType TokenType, name string) {
+	tokenTypes.Lock()
+	defer tokenTypes.Unlock()
+	if previous, ok := tokenTypes.names[tokenType]; ok {
+		delete(tokenTypes.types, previous)
+	}
+	if previous, ok := tokenTypes.types[name]; ok {
+		delete(tokenTypes.names, previous)
+	}
+	tokenTypes.names[tokenType] = name
+	tokenTypes.types[name] = tokenType
+}
+
+// TokenTypeByName returns the token type registered with the specified name.
+func TokenTypeByName(name string) (TokenType, bool) {
+	tokenTypes.RLock()
+	defer tokenTypes.RUnlock()
+	t, ok := tokenTypes.types[name]
+	return t, ok
+}
+
+// String returns the name registered for the token type, or its number if it has none.
+func (t TokenType) String() string {
+	if name, ok := t.name(); ok {
+		return name
+	}
+	return strconv.Itoa(int(t))
+}
+
+func (t TokenType) name() (string, bool) {
+	tokenTypes.RLock()
+	defer tokenTypes.RUnlock()
+	name, ok := tokenTypes.names[t]
+	return name, ok
+}
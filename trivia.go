@@ -0,0 +1,115 @@
+package lexer
+
+import "sort"
+
+// TokenTrivia represents a type of token that contains runes skipped by the lexer as its
+// value.
+const TokenTrivia TokenType = -3
+
+// TriviaMode determines what the lexer does with the runes it skips (e.g. whitespace and
+// comments).
+type TriviaMode int
+
+const (
+	// TriviaDiscard discards skipped runes.
+	TriviaDiscard TriviaMode = iota
+
+	// TriviaLeading attaches skipped runes to the following token.
+	TriviaLeading
+
+	// TriviaTrailing attaches skipped runes to the preceding token.
+	TriviaTrailing
+)
+
+// WithTrivia captures the runes skipped by Ignore, IgnoreUpTo, and EmitTrivia as trivia
+// attached to the emitted tokens according to the specified mode.
+//
+// Trivia that cannot be attached to a token, such as trivia at the start of the input in
+// TriviaTrailing mode or at the end of the input in TriviaLeading mode, is attached to a
+// token of type TokenTrivia.
+//
+// In TriviaTrailing mode each token is held back until the next token is emitted, or the
+// lexer stops, so that its trailing trivia is complete when it is received.
+func WithTrivia(mode TriviaMode) Option {
+	return func(l *Lexer) {
+		l.triviaMode = mode
+	}
+}
+
+// EmitTrivia captures the runes consumed since the last token was emitted as trivia of the
+// specified type (e.g. a comment).
+//
+// If the lexer was not created with WithTrivia the runes are skipped.
+func (l *Lexer) EmitTrivia(tokenType TokenType) {
+	l.captureTrivia(tokenType)
+	l.startPosition = l.CurrentPosition
+}
+
+func (l *Lexer) captureTrivia(tokenType TokenType) {
+	if l.triviaMode == TriviaDiscard || l.startPosition >= l.CurrentPosition {
+		return
+	}
+	if l.trivia == nil && l.allocator != nil {
+		l.trivia = l.allocator.Trivia()
+	}
+	if n := len(l.trivia); n > 0 && tokenType == TokenTrivia && l.trivia[n-1].Type == TokenTrivia && l.triviaEnd == l.startPosition {
+		start := l.triviaEnd - RunePosition(len(l.trivia[n-1].Value.(string)))
+		l.trivia[n-1].Value = l.Input[start:l.CurrentPosition]
+	} else {
+		t := Token{Type: tokenType, Value: l.Input[l.startPosition:l.CurrentPosition]}
+		l.setPosition(&t, l.startPosition)
+		l.trivia = append(l.trivia, t)
+	}
+	if l.lossless {
+		n := len(l.trivia)
+		l.trivia[n-1].Raw = l.trivia[n-1].Value.(string)
+	}
+	l.triviaEnd = l.CurrentPosition
+}
+
+func (l *Lexer) attachTrivia(t Token) {
+	if l.triviaMode == TriviaLeading {
+		t.Trivia, l.trivia = append(l.trivia, t.Trivia...), nil
+		l.deliver(t)
+		return
+	}
+	l.flushTrivia()
+	l.heldToken = &t
+}
+
+func (l *Lexer) flushTrivia() {
+	if l.heldToken != nil {
+		h := *l.heldToken
+		h.Trivia, l.trivia = append(h.Trivia, l.trivia...), nil
+		l.heldToken = nil
+		l.deliver(h)
+	}
+	if len(l.trivia) > 0 {
+		t := Token{Type: TokenTrivia, Value: "", Trivia: l.trivia}
+		l.trivia = nil
+		l.deliver(t)
+	}
+}
+
+type flatToken struct {
+	Token
+	trivia bool
+}
+
+// flattenTrivia returns the tokens, and their trivia, ordered by position. Error, warning,
+// and trivia tokens, which do not correspond to input of their own, are omitted.
+func flattenTrivia(tokens []Token) []flatToken {
+	var flattened []flatToken
+	for _, t := range tokens {
+		for _, trivia := range t.Trivia {
+			flattened = append(flattened, flatToken{trivia, true})
+		}
+		if t.Type != TokenError && t.Type != TokenWarning && t.Type != TokenTrivia {
+			flattened = append(flattened, flatToken{t, false})
+		}
+	}
+	sort.SliceStable(flattened, func(i, j int) bool {
+		return flattened[i].Position < flattened[j].Position
+	})
+	return flattened
+}
@@ -0,0 +1,68 @@
+package lexer_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const Comment lexer.TokenType = 1
+
+var _ = Describe("Trivia", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case r == '#':
+				l.NextUpTo(func(r rune) bool {
+					return r == '\n'
+				})
+				l.EmitTrivia(Comment)
+			default:
+				l.NextUpTo(unicode.IsSpace)
+				l.Emit(Token)
+			}
+		}
+	}
+
+	trivia := func(values ...interface{}) []lexer.Token {
+		var tokens []lexer.Token
+		for i := 0; i < len(values); i += 2 {
+			tokens = append(tokens, lexer.Token{Type: values[i].(lexer.TokenType), Value: values[i+1]})
+		}
+		return tokens
+	}
+
+	It("should discard skipped runes by default", func() {
+		l := lexer.NewLexer("  a # b\n", state)
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "a"}))
+	})
+
+	It("should attach skipped runes to the following token (i.e. TriviaLeading)", func() {
+		l := lexer.NewLexer("  a # b\n c  ", state, lexer.WithTrivia(lexer.TriviaLeading))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "a", Trivia: trivia(lexer.TokenTrivia, "  ")}))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "c", Trivia: trivia(
+			lexer.TokenTrivia, " ",
+			Comment, "# b",
+			lexer.TokenTrivia, "\n ",
+		)}))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: lexer.TokenTrivia, Value: "", Trivia: trivia(lexer.TokenTrivia, "  ")}))
+	})
+
+	It("should attach skipped runes to the preceding token (i.e. TriviaTrailing)", func() {
+		l := lexer.NewLexer("  a # b\n c  ", state, lexer.WithTrivia(lexer.TriviaTrailing))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: lexer.TokenTrivia, Value: "", Trivia: trivia(lexer.TokenTrivia, "  ")}))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "a", Trivia: trivia(
+			lexer.TokenTrivia, " ",
+			Comment, "# b",
+			lexer.TokenTrivia, "\n ",
+		)}))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Token, Value: "c", Trivia: trivia(lexer.TokenTrivia, "  ")}))
+	})
+})
@@ -0,0 +1,53 @@
+package lexer
+
+import "unicode"
+
+const (
+	// TokenWhitespace represents a type of token that contains a run of whitespace (see
+	// LexWhitespace).
+	TokenWhitespace TokenType = -12
+
+	// TokenNewline represents a type of token that contains a line break (see
+	// LexWhitespace).
+	TokenNewline TokenType = -13
+)
+
+// WhitespaceMode determines what LexWhitespace does with whitespace.
+type WhitespaceMode int
+
+const (
+	// WhitespaceSkip skips whitespace, capturing it as trivia if the lexer was created with
+	// WithTrivia.
+	WhitespaceSkip WhitespaceMode = iota
+
+	// WhitespaceEmit emits a token of type TokenWhitespace for each run of whitespace.
+	WhitespaceEmit
+
+	// WhitespaceNewlines emits a token of type TokenNewline for each line break ("\n" or
+	// "\r\n"), e.g. for line-sensitive grammars, and skips other whitespace.
+	WhitespaceNewlines
+)
+
+// LexWhitespace lexes the run of whitespace (as defined by unicode.IsSpace) at the current
+// position of the lexer, if any, as the mode determines, and reports whether there was any.
+func LexWhitespace(l *Lexer, mode WhitespaceMode) bool {
+	start := l.CurrentPosition
+	for r := l.Peek(); unicode.IsSpace(r); r = l.Peek() {
+		if mode == WhitespaceNewlines && (r == '\n' || r == '\r' && l.peekAt(1) == '\n') {
+			l.EmitTrivia(TokenTrivia)
+			if r == '\r' {
+				l.Next()
+			}
+			l.Next()
+			l.Emit(TokenNewline)
+			continue
+		}
+		l.Next()
+	}
+	if mode == WhitespaceEmit {
+		l.EmitNonEmpty(TokenWhitespace)
+	} else {
+		l.EmitTrivia(TokenTrivia)
+	}
+	return l.CurrentPosition > start
+}
@@ -0,0 +1,62 @@
+package lexer_test
+
+import (
+	"github.com/eczarny/lexer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Whitespace", func() {
+	const Word lexer.TokenType = iota + 2000
+
+	state := func(mode lexer.WhitespaceMode) lexer.StateFunc {
+		var state lexer.StateFunc
+		state = func(l *lexer.Lexer) lexer.StateFunc {
+			if lexer.LexWhitespace(l, mode) {
+				return state
+			}
+			if l.Next() == lexer.EOF {
+				return nil
+			}
+			l.Emit(Word)
+			return state
+		}
+		return state
+	}
+
+	lex := func(input string, mode lexer.WhitespaceMode) []lexer.Token {
+		tokens, err := lexer.Tokenize(input, state(mode))
+		Expect(err).NotTo(HaveOccurred())
+		for i := range tokens {
+			tokens[i] = lexer.Token{Type: tokens[i].Type, Value: tokens[i].Value}
+		}
+		return tokens
+	}
+
+	It("should skip whitespace (i.e. WhitespaceSkip)", func() {
+		Expect(lex(" a \t\nb ", lexer.WhitespaceSkip)).To(Equal([]lexer.Token{{Type: Word, Value: "a"}, {Type: Word, Value: "b"}}))
+		l := lexer.NewLexer(" a", state(lexer.WhitespaceSkip), lexer.WithTrivia(lexer.TriviaLeading))
+		Expect(l.NextToken()).To(EqualToken(lexer.Token{Type: Word, Value: "a", Trivia: []lexer.Token{{Type: lexer.TokenTrivia, Value: " "}}}))
+	})
+
+	It("should emit a token for each run of whitespace (i.e. WhitespaceEmit)", func() {
+		Expect(lex(" a \t\nb", lexer.WhitespaceEmit)).To(Equal([]lexer.Token{
+			{Type: lexer.TokenWhitespace, Value: " "},
+			{Type: Word, Value: "a"},
+			{Type: lexer.TokenWhitespace, Value: " \t\n"},
+			{Type: Word, Value: "b"},
+		}))
+	})
+
+	It("should emit a token for each line break (i.e. WhitespaceNewlines)", func() {
+		Expect(lex("a \r\n\n b\n", lexer.WhitespaceNewlines)).To(Equal([]lexer.Token{
+			{Type: Word, Value: "a"},
+			{Type: lexer.TokenNewline, Value: "\r\n"},
+			{Type: lexer.TokenNewline, Value: "\n"},
+			{Type: Word, Value: "b"},
+			{Type: lexer.TokenNewline, Value: "\n"},
+		}))
+		Expect(lexer.LexWhitespace(lexer.NewLexer("a", nil), lexer.WhitespaceNewlines)).To(BeFalse())
+	})
+})
@@ -0,0 +1,79 @@
+// Package yacc adapts lexers to the yyLexer interface of parsers generated by goyacc.
+//
+// goyacc generates the symbol type (yySymType) in the package of the grammar, so the
+// adapter is generic over it; a *Lexer[yySymType] satisfies the generated yyLexer
+// interface:
+//
+//	l := yacc.NewLexer(lexer.NewLexer(input, lexText), tokens, func(lval *yySymType, t lexer.Token) {
+//		lval.text = t.Value.(string)
+//	})
+//	yyParse(l)
+package yacc
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/eczarny/lexer"
+)
+
+// Lexer implements goyacc's Lex and Error contract on top of a lexer.
+type Lexer[S any] struct {
+	lexer  *lexer.Lexer
+	tokens map[lexer.TokenType]int
+	set    func(lval *S, t lexer.Token)
+	last   lexer.Token
+	errors []lexer.LexError
+}
+
+// NewLexer creates an adapter that maps the types of tokens emitted by the lexer to the
+// token numbers of the grammar, and sets the semantic value of each token with set.
+func NewLexer[S any](l *lexer.Lexer, tokens map[lexer.TokenType]int, set func(lval *S, t lexer.Token)) *Lexer[S] {
+	return &Lexer[S]{lexer: l, tokens: tokens, set: set}
+}
+
+// Lex returns the token number of the next token emitted by the lexer after setting its
+// semantic value, or 0 at the end of the input.
+//
+// Tokens of types without a token number whose value is a single rune are returned as that
+// rune, matching character literals in the grammar. Error tokens end the input, their
+// diagnostics being recorded as they are, with their codes and positions (see Errors).
+func (y *Lexer[S]) Lex(lval *S) int {
+	t, err := y.lexer.ReadToken()
+	if err == io.EOF {
+		return 0
+	}
+	if err != nil {
+		y.Error(err.Error())
+		return 0
+	}
+	y.last = t
+	if t.Type == lexer.TokenError {
+		e, _ := t.LexError()
+		y.errors = append(y.errors, e)
+		return 0
+	}
+	if y.set != nil {
+		y.set(lval, t)
+	}
+	if n, ok := y.tokens[t.Type]; ok {
+		return n
+	}
+	if s, ok := t.Value.(string); ok {
+		if r := []rune(s); len(r) == 1 {
+			return int(r[0])
+		}
+	}
+	y.Error(fmt.Sprintf("no token number for token %s", t))
+	return 0
+}
+
+// Error records a syntax error reported by the parser at the most recent token.
+func (y *Lexer[S]) Error(s string) {
+	y.errors = append(y.errors, lexer.LexError{Message: s, Position: y.last.Position, Severity: lexer.SeverityError})
+}
+
+// Errors returns the errors reported by the lexer and the parser, in order.
+func (y *Lexer[S]) Errors() []lexer.LexError {
+	return y.errors
+}
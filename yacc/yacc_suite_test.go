@@ -0,0 +1,13 @@
+package yacc_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestYacc(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Yacc Suite")
+}
@@ -0,0 +1,102 @@
+package yacc_test
+
+import (
+	"unicode"
+
+	"github.com/eczarny/lexer"
+	"github.com/eczarny/lexer/yacc"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	Number lexer.TokenType = iota
+	Operator
+	Unknown
+)
+
+// NUMBER is the token number goyacc would generate for a %token NUMBER declaration.
+const NUMBER = 57346
+
+type yySymType struct {
+	yys  int
+	text string
+}
+
+type yyLexer interface {
+	Lex(lval *yySymType) int
+	Error(s string)
+}
+
+var _ yyLexer = (*yacc.Lexer[yySymType])(nil)
+
+var _ = Describe("Yacc", func() {
+	state := func(l *lexer.Lexer) lexer.StateFunc {
+		for {
+			switch r := l.Peek(); {
+			case r == lexer.EOF:
+				return nil
+			case unicode.IsSpace(r):
+				l.Ignore()
+			case unicode.IsDigit(r):
+				l.NextUpTo(func(r rune) bool {
+					return !unicode.IsDigit(r)
+				})
+				l.Emit(Number)
+			case r == '+' || r == '*':
+				l.Next()
+				l.Emit(Operator)
+			case r == '?':
+				l.Next()
+				l.Next()
+				l.Emit(Unknown)
+			default:
+				return l.Errorf("unexpected %q", r)
+			}
+		}
+	}
+
+	newLexer := func(input string) *yacc.Lexer[yySymType] {
+		return yacc.NewLexer(lexer.NewLexer(input, state), map[lexer.TokenType]int{Number: NUMBER}, func(lval *yySymType, t lexer.Token) {
+			lval.text = t.Value.(string)
+		})
+	}
+
+	It("should return the token numbers and semantic values of tokens (i.e. Lex)", func() {
+		y := newLexer("12 + 3")
+		var lval yySymType
+		Expect(y.Lex(&lval)).To(Equal(NUMBER))
+		Expect(lval.text).To(Equal("12"))
+		Expect(y.Lex(&lval)).To(Equal(int('+')))
+		Expect(y.Lex(&lval)).To(Equal(NUMBER))
+		Expect(lval.text).To(Equal("3"))
+		Expect(y.Lex(&lval)).To(Equal(0))
+		Expect(y.Errors()).To(BeEmpty())
+	})
+
+	It("should report error tokens and end the input (i.e. Lex and Errors)", func() {
+		y := newLexer("1 $")
+		var lval yySymType
+		Expect(y.Lex(&lval)).To(Equal(NUMBER))
+		Expect(y.Lex(&lval)).To(Equal(0))
+		Expect(y.Errors()).To(Equal([]lexer.LexError{{Message: `unexpected '$'`, Severity: lexer.SeverityError}}))
+	})
+
+	It("should report tokens without a token number (i.e. Lex)", func() {
+		y := newLexer("??")
+		var lval yySymType
+		Expect(y.Lex(&lval)).To(Equal(0))
+		Expect(y.Errors()).To(HaveLen(1))
+		Expect(y.Errors()[0].Message).To(HavePrefix("no token number for token"))
+	})
+
+	It("should record syntax errors reported by the parser at the most recent token (i.e. Error)", func() {
+		y := newLexer("1 2")
+		var lval yySymType
+		y.Lex(&lval)
+		y.Lex(&lval)
+		y.Error("syntax error")
+		Expect(y.Errors()).To(Equal([]lexer.LexError{{Message: "syntax error", Position: 2, Severity: lexer.SeverityError}}))
+	})
+})